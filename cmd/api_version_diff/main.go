@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Detect the swagger properties that are new since the linked API version. For each swagger schema linked by the terraform
+resources, the same schema in the latest API version of the same swagger file is diffed against the linked one, reporting the
+added, removed and changed properties per resource. The resources are ordered by the amount of the new writable properties.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The local path to the swagger spec directory (e.g. azure-rest-api-specs/specification), which is walked to find the newer API versions")
+	resource := flag.String("resource", "", "Only report the terraform resource (e.g. azurerm_virtual_network)")
+	maxDepth := flag.Int("max-depth", 10, "The maximum depth of the swagger properties to be considered")
+	format := flag.String("format", formatMarkdown, fmt.Sprintf("The format of the report, one of %q and %q", formatMarkdown, formatJSON))
+	outputPath := flag.String("output", "", "The path of the report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *tfSchemaDir == "" || *swaggerSpecPath == "" {
+		log.Fatal("both -tf-schema-dir and -swagger-spec-path are required")
+	}
+
+	// The files that are failed to be loaded are reported, but they don't prevent the others from being reported.
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+	}
+
+	var schemas []core.TFSchema
+	for _, f := range files {
+		if *resource != "" && f.Name != *resource {
+			continue
+		}
+		schemas = append(schemas, f.TFSchema)
+	}
+	if *resource != "" && len(schemas) == 0 {
+		log.Fatalf("terraform resource %q is not found in %s", *resource, *tfSchemaDir)
+	}
+
+	diffs, err := core.NewTFResourceAPIVersionDiffs(*swaggerSpecPath, schemas, *maxDepth)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatMarkdown:
+		err = core.WriteTFResourceAPIVersionDiffsMarkdown(&buf, diffs)
+	case formatJSON:
+		if diffs == nil {
+			diffs = []core.TFResourceAPIVersionDiff{}
+		}
+		var b []byte
+		b, err = json.MarshalIndent(diffs, "", "  ")
+		buf.Write(b)
+		buf.WriteString("\n")
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
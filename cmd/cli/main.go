@@ -10,6 +10,7 @@ import (
 
 	"github.com/magodo/ghwalk"
 
+	"github.com/gdamore/tcell"
 	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
 	"github.com/rivo/tview"
 )
@@ -28,6 +29,7 @@ func main() {
 
 	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
 	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
 	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
 	showHelp := flag.Bool("help", false, "Display this message")
 	githubToken := flag.String("github-token", "", "Github access token used to interact with github repos")
@@ -40,12 +42,19 @@ func main() {
 		return
 	}
 
-	swgschemas, err := core.NewSWGSchemasFromTerraformSchema(*swaggerSpecPath, *tfSchemaDir, *swaggerGrantBaseDir)
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
 	if err != nil {
 		log.Fatal(err)
 	}
+	swgschemas, err := core.NewSWGSchemasFromTFSchemaFiles(*swaggerSpecPath, files, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(swgschemas.StaleInheritedGrants) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", swgschemas.StaleInheritedGrants)
+	}
 
-	azureswgschemas := NewSWGResourceProviders(*swgschemas)
+	azureswgschemas := NewSWGResourceProviders(swgschemas)
 
 	swaggerURL, err := url.Parse(*swaggerSpecPath)
 	if err != nil {
@@ -61,6 +70,11 @@ func main() {
 		}
 	}
 
+	// The grant directory has been loaded (and validated) while building the swgschemas.
+	if *swaggerGrantBaseDir != "" {
+		azureswgschemas.Grant(swgschemas.SWGGrant)
+	}
+
 	if *schemaAllowList != "" {
 		var err error
 		azureswgschemas, err = azureswgschemas.Filter(*schemaAllowList)
@@ -69,12 +83,33 @@ func main() {
 		}
 	}
 
-	page := PageSwagger(azureswgschemas)
+	tfschemas := make([]core.TFSchema, 0, len(files))
+	for _, f := range files {
+		tfschemas = append(tfschemas, f.TFSchema)
+	}
+
+	// The pages are switched via function keys.
+	pages := tview.NewPages().
+		AddPage("swagger", PageSwagger(azureswgschemas), true, true).
+		AddPage("terraform", PageTerraform(tfschemas), true, false)
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyF1:
+			pages.SwitchToPage("swagger")
+			return nil
+		case tcell.KeyF2:
+			pages.SwitchToPage("terraform")
+			return nil
+		}
+		return event
+	})
+	footer := tview.NewTextView().SetText("F1: Swagger coverage    F2: Terraform coverage")
 
 	// Create the main layout.
 	layout := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(page, 0, 1, true)
+		AddItem(pages, 0, 1, true).
+		AddItem(footer, 1, 0, false)
 
 	// Start the application.
 	if err := app.SetRoot(layout, true).EnableMouse(true).Run(); err != nil {
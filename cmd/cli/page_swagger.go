@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
 
@@ -50,7 +51,12 @@ func refreshResourceProviderList(items pageSwaggerItems, swgrps SWGResourceProvi
 
 	for _, k := range rps {
 		v := swgrps[k]
-		items.rpList.AddItem(k, "", 0,
+		mainText, secondaryText := k, ""
+		if v.Grant != nil {
+			mainText = fmt.Sprintf("[%s]%s", colorTextGrantedSchema, k)
+			secondaryText = fmt.Sprintf("Granted: %s", v.Grant.Comment)
+		}
+		items.rpList.AddItem(mainText, secondaryText, 0,
 			func() {
 				refreshApiVersionList(items, v.Apis)
 				app.SetFocus(items.apiList)
@@ -58,6 +64,27 @@ func refreshResourceProviderList(items pageSwaggerItems, swgrps SWGResourceProvi
 	}
 }
 
+// formatGrantInfo formats the grant metadata, the expired grant is highlighted as it needs to be reviewed again.
+func formatGrantInfo(info core.GrantInfo) string {
+	text := info.Comment
+	if info.Category != "" {
+		text += fmt.Sprintf("\n    Category: %s", info.Category)
+	}
+	if info.Issue != "" {
+		text += fmt.Sprintf("\n    Issue: %s", info.Issue)
+	}
+	if info.Owner != "" {
+		text += fmt.Sprintf("\n    Owner: %s", info.Owner)
+	}
+	if info.ReviewBy != "" {
+		text += fmt.Sprintf("\n    Review By: %s", info.ReviewBy)
+		if info.IsExpired(time.Now()) {
+			text += " (expired)"
+		}
+	}
+	return text
+}
+
 func refreshApiVersionList(items pageSwaggerItems, swgapis SWGResourceProviderAPIs) {
 	items.apiList.Clear()
 
@@ -70,6 +97,16 @@ func refreshApiVersionList(items pageSwaggerItems, swgapis SWGResourceProviderAP
 	for _, k := range apis {
 		v := swgapis[k]
 
+		// The granted API version is not expected to be covered at all, hence no progress bar.
+		if v.Grant != nil {
+			items.apiList.AddItem(fmt.Sprintf("[%s]%s", colorTextGrantedSchema, k), fmt.Sprintf("Granted: %s", v.Grant.Comment), 0,
+				func() {
+					refreshSchemaList(items, v.Schemas)
+					app.SetFocus(items.schemaList)
+				})
+			continue
+		}
+
 		var covered, total int
 
 		swgschemas := v.Schemas
@@ -130,6 +167,9 @@ func refreshSchemaList(items pageSwaggerItems, swgschemas SWGSchemas) {
 		)
 		if v.IsGranted {
 			mainText = formatText(colorTextGrantedSchema, k)
+			if info := v.GrantInfo(); info.IsExpired(time.Now()) {
+				secondaryText = "grant expired\n"
+			}
 		} else if propCovered == 0 {
 			mainText = formatText(colorTextNotCoveredSchema, k)
 		} else {
@@ -230,13 +270,23 @@ func refreshPropertyTree(items pageSwaggerItems, swgschema SWGSchema) {
 			items.propertyDetail.Clear()
 
 			if prop.IsGranted {
-				fmt.Fprintf(items.propertyDetail, "Deliberately not supported in Terraform: %s", prop.GrantComment)
+				fmt.Fprintf(items.propertyDetail, "Deliberately not supported in Terraform: %s", formatGrantInfo(prop.GrantInfo()))
+				if prop.GrantPattern != "" {
+					fmt.Fprintf(items.propertyDetail, "\n    Granted By: %s (%s)", prop.GrantPattern, prop.GrantSource)
+				}
 				return
 			}
 			tfproperties := make([]string, 0, len(prop.TFLinks))
 			for _, tflink := range prop.TFLinks {
 				prop := tflink.Prop
-				tfproperties = append(tfproperties, fmt.Sprintf("- %s: %s", prop.ResourceName, prop.PropertyAddr.String()))
+				tfproperty := fmt.Sprintf("- %s: %s", prop.ResourceName, prop.PropertyAddr.String())
+				if tflink.Transform != "" {
+					tfproperty += fmt.Sprintf("\n    Transform: %s", tflink.Transform)
+				}
+				if tflink.Note != "" {
+					tfproperty += fmt.Sprintf("\n    Note: %s", tflink.Note)
+				}
+				tfproperties = append(tfproperties, tfproperty)
 			}
 
 			if len(tfproperties) == 0 {
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+var (
+	colorTextLinkedProperty        = "white"
+	colorTextNotApplicableProperty = "grey"
+	colorTextUnmappedProperty      = "red"
+)
+
+type pageTerraformItems struct {
+	resourceList   *tview.List
+	propertyList   *tview.List
+	propertyDetail *tview.TextView
+}
+
+func refreshTerraformResourceList(items pageTerraformItems, report core.TFCoverageReport, schemas map[string]core.TFSchema) {
+	items.resourceList.Clear()
+	items.resourceList.SetTitle(fmt.Sprintf("Terraform Resource %s", drawProgressBar(report.Total.Completeness())))
+
+	for _, cov := range report.Resources {
+		schema := schemas[cov.Name]
+		secondaryText := fmt.Sprintf("%s (linked: %d, n/a: %d, unmapped: %d)", drawProgressBar(cov.Completeness()), cov.Linked, cov.NotApplicable, cov.Unmapped)
+		items.resourceList.AddItem(cov.Name, secondaryText, 0,
+			func() {
+				refreshTerraformPropertyList(items, schema)
+				app.SetFocus(items.propertyList)
+			})
+	}
+}
+
+func refreshTerraformPropertyList(items pageTerraformItems, schema core.TFSchema) {
+	items.propertyList.Clear()
+	items.propertyDetail.Clear()
+
+	// See refreshSchemaList for why the changed func is reset here.
+	items.propertyList.SetChangedFunc(nil)
+
+	tfProps := make([]string, 0, len(schema.PropertyLinks))
+	for k := range schema.PropertyLinks {
+		tfProps = append(tfProps, k)
+	}
+	sort.Strings(tfProps)
+
+	for _, tfProp := range tfProps {
+		colorText := colorTextLinkedProperty
+		switch schema.PropertyLinks.Status(tfProp) {
+		case core.TFPropertyNotApplicable:
+			colorText = colorTextNotApplicableProperty
+		case core.TFPropertyUnmapped:
+			colorText = colorTextUnmappedProperty
+		}
+		items.propertyList.AddItem(fmt.Sprintf("[%s]%s", colorText, tfProp), "", 0, nil)
+	}
+
+	showPropertyInfo := func(index int) {
+		items.propertyDetail.Clear()
+		if index < 0 || index >= len(tfProps) {
+			return
+		}
+		tfProp := tfProps[index]
+
+		switch schema.PropertyLinks.Status(tfProp) {
+		case core.TFPropertyNotApplicable:
+			reason, _ := schema.PropertyLinks.NotApplicableReason(tfProp)
+			fmt.Fprintf(items.propertyDetail, "Not applicable to Swagger: %s", reason)
+			return
+		case core.TFPropertyUnmapped:
+			fmt.Fprintf(items.propertyDetail, "To be mapped in the knowledge base.")
+			return
+		}
+
+		swgproperties := make([]string, 0, len(schema.PropertyLinks[tfProp]))
+		for _, link := range schema.PropertyLinks[tfProp] {
+			spec := schema.SwaggerSpec
+			if link.Spec != nil {
+				spec = *link.Spec
+			}
+			swgproperty := fmt.Sprintf("- %s (%s)", link.SchemaProp, spec)
+			if link.Transform != "" {
+				swgproperty += fmt.Sprintf("\n    Transform: %s", link.Transform)
+			}
+			if link.Note != "" {
+				swgproperty += fmt.Sprintf("\n    Note: %s", link.Note)
+			}
+			swgproperties = append(swgproperties, swgproperty)
+		}
+		fmt.Fprintf(items.propertyDetail, `Related Swagger Properties:
+
+%s
+`, strings.Join(swgproperties, "\n"))
+	}
+
+	items.propertyList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		showPropertyInfo(index)
+	})
+	showPropertyInfo(items.propertyList.GetCurrentItem())
+}
+
+func PageTerraform(schemas []core.TFSchema) tview.Primitive {
+	resourceList := tview.NewList().ShowSecondaryText(true)
+	resourceList.SetBorder(true)
+
+	propertyList := tview.NewList().ShowSecondaryText(false)
+	propertyList.SetTitle("Property").SetBorder(true)
+	propertyList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyESC {
+			app.SetFocus(resourceList)
+			return nil
+		}
+		return event
+	})
+
+	propertyDetail := tview.NewTextView()
+	propertyDetail.SetTitle("PropertyDetail").SetBorder(true)
+
+	items := pageTerraformItems{
+		resourceList:   resourceList,
+		propertyList:   propertyList,
+		propertyDetail: propertyDetail,
+	}
+
+	schemaMap := map[string]core.TFSchema{}
+	for _, schema := range schemas {
+		schemaMap[schema.Name] = schema
+	}
+	refreshTerraformResourceList(items, core.NewTFCoverageReport(schemas), schemaMap)
+
+	return tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(items.resourceList, 0, 2, true).
+		AddItem(
+			tview.NewFlex().
+				SetDirection(tview.FlexRow).
+				AddItem(items.propertyList, 0, 3, true).
+				AddItem(items.propertyDetail, 0, 1, true),
+			0, 3, true,
+		)
+}
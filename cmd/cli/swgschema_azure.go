@@ -13,8 +13,6 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
-	openapispec "github.com/go-openapi/spec"
-
 	"github.com/magodo/ghwalk"
 	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
 )
@@ -52,6 +50,7 @@ func (providers SWGResourceProviders) CopyAPIFrom(oproviders SWGResourceProvider
 		providers[rpName] = &SWGResourceProvider{
 			SwaggerRelPath: rp.SwaggerRelPath,
 			Apis:           SWGResourceProviderAPIs{},
+			Grant:          rp.Grant,
 		}
 	}
 
@@ -78,6 +77,7 @@ func (providers SWGResourceProviders) CopySchemaFrom(oproviders SWGResourceProvi
 		providers[rpName] = &SWGResourceProvider{
 			SwaggerRelPath: rp.SwaggerRelPath,
 			Apis:           SWGResourceProviderAPIs{},
+			Grant:          rp.Grant,
 		}
 	}
 
@@ -85,6 +85,7 @@ func (providers SWGResourceProviders) CopySchemaFrom(oproviders SWGResourceProvi
 		providers[rpName].Apis[apiVersion] = &SWGResourceProviderAPI{
 			SwaggerRelPath: api.SwaggerRelPath,
 			Schemas:        SWGSchemas{},
+			Grant:          api.Grant,
 		}
 	}
 
@@ -96,12 +97,16 @@ func (providers SWGResourceProviders) CopySchemaFrom(oproviders SWGResourceProvi
 type SWGResourceProvider struct {
 	SwaggerRelPath string
 	Apis           SWGResourceProviderAPIs
+
+	// The directory grant of the whole resource provider, nil if it is not granted.
+	Grant *core.SWGDirectoryGrant
 }
 
 func (v SWGResourceProvider) Copy() SWGResourceProvider {
 	return SWGResourceProvider{
 		SwaggerRelPath: v.SwaggerRelPath,
 		Apis:           v.Apis.Copy(),
+		Grant:          v.Grant,
 	}
 }
 
@@ -119,12 +124,16 @@ func (apis SWGResourceProviderAPIs) Copy() SWGResourceProviderAPIs {
 type SWGResourceProviderAPI struct {
 	SwaggerRelPath string
 	Schemas        SWGSchemas
+
+	// The directory grant of the whole API version, which might be inherited from the resource provider, nil if it is not granted.
+	Grant *core.SWGDirectoryGrant
 }
 
 func (v SWGResourceProviderAPI) Copy() SWGResourceProviderAPI {
 	return SWGResourceProviderAPI{
 		SwaggerRelPath: v.SwaggerRelPath,
 		Schemas:        v.Schemas.ShallowCopy(),
+		Grant:          v.Grant,
 	}
 }
 
@@ -179,7 +188,7 @@ func (addr SWGSchemaAddr) RelPathToSwaggerFile() string {
 
 // NewSWGResourceProviders convert the core.SWGSchemas, whose key is swagger file + schema name,
 // into a hierarchy of structures mapping to the Azure concept, beginning from the resource provider level.
-func NewSWGResourceProviders(swgschemas core.SWGSchemas) SWGResourceProviders {
+func NewSWGResourceProviders(swgschemas *core.SWGSchemas) SWGResourceProviders {
 	out := map[string]*SWGResourceProvider{}
 	for addr, swgschema := range swgschemas.GetAll() {
 		addr := ParseSWGSchemaAddr(addr)
@@ -198,6 +207,29 @@ func NewSWGResourceProviders(swgschemas core.SWGSchemas) SWGResourceProviders {
 	return out
 }
 
+// Grant applies the directory grants onto the resource providers and the API versions, every schema underneath a granted
+// resource provider or API version is granted as a whole.
+// This is expected to be called after the swagger resource providers are completed, so that the completed schemas are granted too.
+func (swgrps SWGResourceProviders) Grant(grant core.SWGGrant) {
+	for _, rp := range swgrps {
+		if dirGrant, ok := grant.DirectoryGrant(rp.SwaggerRelPath); ok {
+			rp.Grant = &dirGrant
+		}
+		for _, api := range rp.Apis {
+			dirGrant, ok := grant.DirectoryGrant(api.SwaggerRelPath)
+			if !ok {
+				continue
+			}
+			api.Grant = &dirGrant
+			for _, schema := range api.Schemas {
+				if !schema.IsGranted {
+					schema.SWGSchema.Grant(dirGrant.GrantInfo, dirGrant.Source())
+				}
+			}
+		}
+	}
+}
+
 // CompleteSWGResourceProvidersViaGithubAPI completes the swagger resource providers by querying swagger spec repo via Github.
 // For each (RP,API Version), searching for all the swagger spec files to collect all the schemas that belongs to
 // the "in-body" parameter of an endpoint which has PUT and DELETE methods.
@@ -444,67 +476,27 @@ func (swgrps SWGResourceProviders) Filter(allowListFile string) (SWGResourceProv
 	return newswgrps, nil
 }
 
+// collectAllTFCandidateSchemas collects the PUT body schemas of the terraform resource candidates of the swagger file.
 func collectAllTFCandidateSchemas(swaggerRepoBaseURI, relPath string) ([]SWGSchema, error) {
-	coreSchemas, err := core.CollectSWGSchemas(swaggerRepoBaseURI, relPath, func(swagger *openapispec.Swagger) (schemaNames []string) {
-		if swagger.Paths == nil {
-			return nil
-		}
-		schemaNameSet := map[string]struct{}{}
-		for _, p := range swagger.Paths.Paths {
-			// We only consider resource contains GET, PUT and DELETE methods as a Terraform candidate
-			if p.Put == nil || p.Delete == nil || p.Get == nil {
-				continue
-			}
-
-			for _, param := range p.Put.Parameters {
-				// The param is a ref, we simply deref it for once
-				if param.Ref.String() != "" {
-					swaggerURI := swaggerRepoBaseURI + "/" + relPath
-					swagger, err := core.LoadSwagger(swaggerURI)
-					if err != nil {
-						panic(fmt.Sprintf("loading swagger %q: %v", relPath, err))
-					}
-					pparam, err := openapispec.ResolveParameterWithBase(swagger, param.Ref, &openapispec.ExpandOptions{RelativeBase: swaggerURI})
-					if err != nil {
-						panic(fmt.Sprintf("resolve param ref %q: %v", param.Ref.String(), err))
-					}
-					param = *pparam
-				}
-
-				if param.In != "body" {
-					continue
-				}
-
-				// TODO: we should handle cross file reference for these in-body parameters.
-				// Here we simply ignore the cross file reference.
-				if !param.Schema.Ref.HasFragmentOnly {
-					continue
-				}
-
-				refString := param.Schema.Ref.GetPointer().String()
-				refStringPattern := regexp.MustCompile(`^/definitions/([^/]+)$`)
-				matches := refStringPattern.FindStringSubmatch(refString)
-				if len(matches) != 2 {
-					continue
-				}
-
-				schemaNameSet[matches[1]] = struct{}{}
-			}
-		}
-		schemaNames = make([]string, 0, len(schemaNameSet))
-		for schemaName := range schemaNameSet {
-			schemaNames = append(schemaNames, schemaName)
-		}
-		return schemaNames
-	})
-
+	candidates, err := core.CollectSWGResourceTypeCandidates(swaggerRepoBaseURI, relPath)
 	if err != nil {
 		return nil, err
 	}
 
-	schemas := make([]SWGSchema, 0, len(coreSchemas))
-	for _, coreSchema := range coreSchemas {
-		schemas = append(schemas, SWGSchema{coreSchema})
+	// Different paths (e.g. of different scopes) might share the same body schema.
+	schemaNameSet := map[string]struct{}{}
+	var schemas []SWGSchema
+	for _, candidate := range candidates {
+		schemaName := candidate.Schema.SchemaName()
+		if _, ok := schemaNameSet[schemaName]; ok {
+			continue
+		}
+		schemaNameSet[schemaName] = struct{}{}
+		schema, err := core.NewSWGSchema(swaggerRepoBaseURI, relPath, schemaName)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, SWGSchema{*schema})
 	}
 	return schemas, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Export the swagger schema coverage figures as OpenMetrics gauges, which can be scraped via the textfile collector of the
+Prometheus node exporter.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	swaggerSchemaPath := flag.String("swagger-schema", "", "The path to the swagger schema file (generated by cmd/swagger_schema), or the knowledge base directory (e.g. azure_knowledgebase)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version (only for knowledge base directory)")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification), required for knowledge base directory")
+	outputPath := flag.String("output", "", "The path of the metrics file (e.g. <textfile directory>/azurerm_insight.prom), which is replaced atomically. If not specified, the metrics are printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *swaggerSchemaPath == "" {
+		log.Fatal("-swagger-schema is required")
+	}
+
+	report, stale, err := core.NewSWGSchemaReportFromPath(*swaggerSchemaPath, *swaggerSpecPath, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(stale) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", stale)
+	}
+
+	var buf bytes.Buffer
+	if err := core.WriteSWGCoverageMetrics(&buf, report.Schemas()); err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+
+	// The textfile collector might read the file at any time, so write to a temporary file then rename it.
+	tmpPath := *outputPath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Rename(tmpPath, *outputPath); err != nil {
+		log.Fatal(err)
+	}
+}
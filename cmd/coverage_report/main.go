@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Report the swagger schema coverage per resource provider, API version and swagger schema, together with the uncovered
+properties (and their descriptions) and the linked terraform resources of each swagger schema.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	format := flag.String("format", formatMarkdown, fmt.Sprintf("The format of the report, either %q or %q", formatMarkdown, formatJSON))
+	outputPath := flag.String("output", "", "The path of the report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	swgschemas, err := core.NewSWGSchemasFromTerraformSchema(*swaggerSpecPath, *tfSchemaDir, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the knowledge base:\n%v", err)
+	}
+	if len(swgschemas.StaleInheritedGrants) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", swgschemas.StaleInheritedGrants)
+	}
+
+	summary := core.NewSWGCoverageSummary(swgschemas.GetAll())
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatMarkdown:
+		err = summary.WriteMarkdown(&buf)
+	case formatJSON:
+		var b []byte
+		b, err = json.MarshalIndent(summary, "", "  ")
+		buf.Write(b)
+		buf.WriteString("\n")
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
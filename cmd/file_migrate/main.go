@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+type kbFile struct {
+	path string
+	kind core.FileKind
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Migrate the knowledge base files to the current version of their formats. The files that are already of the current
+version are left untouched. The YAML comments are kept for the entries that still exist after the migration.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	swaggerSchemaPath := flag.String("swagger-schema", "", "The path to the swagger schema file (generated by cmd/swagger_schema)")
+	check := flag.Bool("check", false, "Only list the files to migrate without modifying them, and exit with 1 if there is any")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *tfSchemaDir == "" && *swaggerGrantBaseDir == "" && *swaggerSchemaPath == "" {
+		log.Fatal("at least one of -tf-schema-dir, -swagger-grant-dir and -swagger-schema is required")
+	}
+
+	var files []kbFile
+	if *tfSchemaDir != "" {
+		paths, err := listFiles(*tfSchemaDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, path := range paths {
+			files = append(files, kbFile{path: path, kind: core.FileKindTFSchema})
+		}
+	}
+	if *swaggerGrantBaseDir != "" {
+		paths, err := listFiles(*swaggerGrantBaseDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, path := range paths {
+			files = append(files, kbFile{path: path, kind: core.SWGGrantFileKind(path)})
+		}
+	}
+	if *swaggerSchemaPath != "" {
+		files = append(files, kbFile{path: *swaggerSchemaPath, kind: core.FileKindSWGSchemaReport})
+	}
+
+	var (
+		diags    core.Diagnostics
+		outdated int
+	)
+	for _, f := range files {
+		format, _ := core.FileFormatOf(f.path)
+		b, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, version, err := core.MigrateFile(f.kind, format, b)
+		if err != nil {
+			var fileDiags core.Diagnostics
+			fileDiags.Append(err)
+			diags = append(diags, fileDiags.WithFile(f.path, b)...)
+			continue
+		}
+		current := core.CurrentFormatVersion(f.kind)
+		if version == current {
+			continue
+		}
+		outdated++
+		if *check {
+			fmt.Printf("%s: format version %d (current: %d)\n", f.path, version, current)
+			continue
+		}
+		if format == core.FileFormatJSON {
+			out = append(out, '\n')
+		}
+		if err := ioutil.WriteFile(f.path, out, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: migrated from format version %d to %d\n", f.path, version, current)
+	}
+
+	if err := diags.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if *check && outdated != 0 {
+		os.Exit(1)
+	}
+}
+
+// listFiles lists the knowledge base files (either in JSON or YAML) resides in the directory.
+func listFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if _, ok := core.FileFormatOf(path); !ok {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	return paths, err
+}
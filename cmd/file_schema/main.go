@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Generate the JSON Schema of the current format of each kind of knowledge base file, as "<kind>.schema.json" in the output
+directory. The JSON Schemas are generated from the Go types, and are the same ones that the files are validated against.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	outputDir := flag.String("output-dir", "", "The directory to write the JSON Schemas into (e.g. doc/schema)")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *outputDir == "" {
+		log.Fatal("-output-dir is required")
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, kind := range core.FileKinds {
+		b, err := json.MarshalIndent(core.FileJSONSchema(kind), "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(*outputDir, string(kind)+".schema.json"), append(b, '\n'), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
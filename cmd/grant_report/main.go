@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, "Report the granted swagger schemas and properties grouped by the grant category, and flag the grants whose review-by date has passed.\n\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	outputPath := flag.String("output", "", "The path of the JSON report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	swgschemas, err := core.NewSWGSchemasFromTerraformSchema(*swaggerSpecPath, *tfSchemaDir, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the knowledge base:\n%v", err)
+	}
+	if len(swgschemas.StaleInheritedGrants) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", swgschemas.StaleInheritedGrants)
+	}
+
+	report := core.NewGrantReport(swgschemas.GetAll(), time.Now())
+	if len(report.Expired) != 0 {
+		log.Printf("Warning: %d grant(s) have passed the review-by date", len(report.Expired))
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Println(string(b))
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
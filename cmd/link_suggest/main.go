@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/providersrc"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, "Suggest the swagger properties to be linked by the terraform properties that have no link yet.\n\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaPath := flag.String("tf-schema", "", "The path to the terraform schema file (JSON or YAML)")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	schemaName := flag.String("schema", "", "The root swagger schema of the resource. If not specified, will use the schema linked most by the terraform schema.")
+	providerSchemaPath := flag.String("provider-schema", "", `The path to the Terraform provider schema file (generated by "$ terraform providers schema -json"). If specified, the type compatibility is taken into consideration.`)
+	providerName := flag.String("provider-name", "registry.terraform.io/hashicorp/azurerm", "Full qualified name of the provider")
+	minScore := flag.Float64("min-score", 0.5, "The minimum score of a suggestion to be proposed")
+	maxCandidates := flag.Int("top", 3, "The maximum amount of suggestions proposed for each terraform property")
+	providerSrcDir := flag.String("provider-source", "", "The path to the Go package of the provider that implements the resource (e.g. azurerm/internal/services/network). If specified, the links inferred from the source code take precedence over the suggestions made by matching the property addresses.")
+	maxDepth := flag.Int("max-depth", 10, "The maximum depth of the swagger properties to be considered")
+	outputPath := flag.String("output", "", "The path of the draft terraform schema file, which has the best suggestion filled in. The format (JSON or YAML) is determined by the file extension. If not specified, the ranked suggestions will be printed instead.")
+	asJSON := flag.Bool("json", false, "Print the ranked suggestions in JSON")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	f, err := core.LoadTFSchemaFile(*tfSchemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tfschema := f.TFSchema
+	if tfschema.SwaggerSpec == "" {
+		log.Fatalf(`The "swagger" of the terraform schema %s is not specified`, tfschema.Name)
+	}
+
+	if *schemaName == "" {
+		var ok bool
+		*schemaName, ok = tfschema.GuessRootSchema()
+		if !ok {
+			log.Fatalf(`Can't guess the root swagger schema of the terraform schema %s, please specify it via "-schema"`, tfschema.Name)
+		}
+	}
+
+	swgschema, err := core.NewSWGSchema(*swaggerSpecPath, tfschema.SwaggerSpec, *schemaName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := core.LinkSuggestOptions{
+		MinScore:      *minScore,
+		MaxCandidates: *maxCandidates,
+		MaxDepth:      *maxDepth,
+		Conversions:   core.DefaultTypeConversions,
+	}
+	if *providerSchemaPath != "" {
+		provider, err := core.LoadTerraformProvider(*providerSchemaPath, *providerName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		block, ok := provider.FindBlock(tfschema.Name)
+		if !ok {
+			log.Fatalf("%s is not found in the provider schema", tfschema.Name)
+		}
+		opts.Block = block
+	}
+
+	suggestions, err := core.SuggestLinks(tfschema, swgschema, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *providerSrcDir != "" {
+		inferred, err := providersrc.InferLinks(*providerSrcDir, tfschema, swgschema, *maxDepth)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inferredTFProps := map[string]bool{}
+		for _, suggestion := range inferred {
+			inferredTFProps[suggestion.TFProp] = true
+		}
+		for _, suggestion := range suggestions {
+			if !inferredTFProps[suggestion.TFProp] {
+				inferred = append(inferred, suggestion)
+			}
+		}
+		suggestions = inferred
+		sort.SliceStable(suggestions, func(i, j int) bool {
+			return suggestions[i].TFProp < suggestions[j].TFProp
+		})
+	}
+
+	if *outputPath != "" {
+		format, ok := core.FileFormatOf(*outputPath)
+		if !ok {
+			format = f.Format
+		}
+		b, err := core.EncodeFile(core.FileKindTFSchema, format, tfschema.ApplyLinkSuggestions(suggestions), f.Content)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*outputPath, b, 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *asJSON {
+		b, err := json.MarshalIndent(suggestions, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(b))
+		return
+	}
+
+	var lastTFProp string
+	for _, suggestion := range suggestions {
+		if suggestion.TFProp != lastTFProp {
+			fmt.Printf("%s:\n", suggestion.TFProp)
+			lastTFProp = suggestion.TFProp
+		}
+		if suggestion.Origin != "" {
+			fmt.Printf("  %.2f  %s (%s)\n", suggestion.Score, suggestion.SWGProp, suggestion.Origin)
+			continue
+		}
+		fmt.Printf("  %.2f  %s\n", suggestion.Score, suggestion.SWGProp)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Lint the knowledge base files (the terraform schemas and the swagger grants), and report the required swagger properties
+that are neither linked nor granted. The findings are written as a SARIF 2.1.0 log, each result points to the offending
+entry of the knowledge base file, so that they can be shown as annotations in code review.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	providerSchemaPath := flag.String("provider-schema", "", `The path to the Terraform provider schema file (generated by "$ terraform providers schema -json"), which enables the type compatibility check (optional)`)
+	providerName := flag.String("provider-name", "registry.terraform.io/hashicorp/azurerm", "Full qualified name of the provider")
+	baseDir := flag.String("base-dir", ".", "The directory that the file paths in the SARIF log are relative to (e.g. the root of the repository)")
+	outputPath := flag.String("output", "", "The path of the SARIF log. If not specified, the SARIF log is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *tfSchemaDir == "" || *swaggerSpecPath == "" {
+		log.Fatal("both -tf-schema-dir and -swagger-spec-path are required")
+	}
+
+	var diags core.Diagnostics
+
+	// The problems of the knowledge base files are reported, but they don't prevent the others from being checked.
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		loadDiags, ok := err.(core.Diagnostics)
+		if !ok {
+			log.Fatal(err)
+		}
+		diags = append(diags, loadDiags.WithRule(core.RuleTFSchemaInvalid)...)
+	}
+	swgschemas, err := core.NewSWGSchemasFromTFSchemaFiles(*swaggerSpecPath, files, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		diags.Append(err)
+	}
+	diags = append(diags, swgschemas.StaleInheritedGrants...)
+
+	if *providerSchemaPath != "" {
+		provider, err := core.LoadTerraformProvider(*providerSchemaPath, *providerName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, f := range files {
+			block, ok := provider.FindBlock(f.Name)
+			if !ok {
+				diags = append(diags, f.Locate(fmt.Errorf("%s is not found in the provider schema", f.Name)).WithRule(core.RuleTFSchemaProvider)...)
+				continue
+			}
+			if err := f.CheckTypeCompatibility(swgschemas, block, core.DefaultTypeConversions); err != nil {
+				diags = append(diags, f.Locate(err).WithRule(core.RuleTFSchemaType)...)
+			}
+		}
+	}
+
+	diags = append(diags, core.FindUncoveredRequiredProperties(swgschemas, files)...)
+	diags.Sort()
+
+	sarif, err := core.NewSARIFLog(diags, *baseDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := sarif.WriteJSON(&buf); err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
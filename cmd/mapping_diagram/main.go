@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatMermaid = "mermaid"
+	formatDOT     = "dot"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Generate the mapping diagram of a terraform resource or a swagger schema: the terraform property tree on one side, the
+expanded swagger property tree on the other side, and an edge for every link in between. The uncovered, granted and not
+applicable properties are highlighted.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	resourceName := flag.String("resource", "", "The terraform resource to generate the diagram for (e.g. azurerm_virtual_network)")
+	schemaAddr := flag.String("schema", "", "The swagger schema to generate the diagram for, in the form of <swagger spec relative path>#/definitions/<schema name>")
+	format := flag.String("format", formatMermaid, fmt.Sprintf("The format of the diagram, either %q or %q", formatMermaid, formatDOT))
+	outputPath := flag.String("output", "", "The path of the diagram. If not specified, the diagram is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if (*resourceName == "") == (*schemaAddr == "") {
+		log.Fatal("exactly one of -resource and -schema is required")
+	}
+
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+	}
+	swgschemas, err := core.NewSWGSchemasFromTFSchemaFiles(*swaggerSpecPath, files, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the knowledge base:\n%v", err)
+	}
+	if len(swgschemas.StaleInheritedGrants) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", swgschemas.StaleInheritedGrants)
+	}
+
+	var diagram core.MappingDiagram
+	if *resourceName != "" {
+		var tfschema *core.TFSchema
+		for _, f := range files {
+			if f.Name == *resourceName {
+				tfschema = &f.TFSchema
+				break
+			}
+		}
+		if tfschema == nil {
+			log.Fatalf("terraform resource %q is not found in %s", *resourceName, *tfSchemaDir)
+		}
+		diagram = core.NewTFResourceMappingDiagram(*resourceName, tfschema, swgschemas.GetAll())
+	} else {
+		schema := swgschemas.Get(core.SWGSchemaAddr(*schemaAddr))
+		if schema == nil {
+			log.Fatalf("swagger schema %q is not linked by any terraform resource", *schemaAddr)
+		}
+		diagram = core.NewSWGSchemaMappingDiagram(core.SWGSchemaAddr(*schemaAddr), schema)
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatMermaid:
+		err = diagram.WriteMermaid(&buf)
+	case formatDOT:
+		err = diagram.WriteDOT(&buf)
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,449 @@
+package main
+
+// The assets of the site, which are kept as plain strings so that the site is a self-contained directory that doesn't rely
+// on any CDN. The data of the site is loaded via a script (rather than fetched) so that the site works via "file://" as well.
+
+const siteDataFileName = "data.js"
+
+const siteIndexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Terraform AzureRM Provider Insight</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<header>
+  <nav>
+    <button id="tab-swagger" class="tab active" data-page="swagger">Swagger coverage</button>
+    <button id="tab-terraform" class="tab" data-page="terraform">Terraform coverage</button>
+  </nav>
+  <input id="search" type="search" placeholder="Search schemas and properties">
+</header>
+<main>
+  <section id="page-swagger" class="page">
+    <div class="pane"><h2>Resource Provider</h2><ul id="rp-list" class="list"></ul></div>
+    <div class="pane"><h2>API Version</h2><ul id="api-list" class="list"></ul></div>
+    <div class="pane wide"><h2>Schema</h2><ul id="schema-list" class="list"></ul></div>
+    <div class="pane widest column">
+      <div class="pane-body grow">
+        <h2>Property <span class="hint">(<a href="#" id="expand-all">expand all</a> / <a href="#" id="collapse-all">collapse all</a>)</span></h2>
+        <div id="property-tree" class="tree"></div>
+      </div>
+      <div class="pane-body"><h2>PropertyDetail</h2><pre id="property-detail" class="detail"></pre></div>
+    </div>
+  </section>
+  <section id="page-terraform" class="page hidden">
+    <div class="pane wide"><h2 id="tf-resource-title">Terraform Resource</h2><ul id="tf-resource-list" class="list"></ul></div>
+    <div class="pane widest column">
+      <div class="pane-body grow"><h2>Property</h2><ul id="tf-property-list" class="list"></ul></div>
+      <div class="pane-body"><h2>PropertyDetail</h2><pre id="tf-property-detail" class="detail"></pre></div>
+    </div>
+  </section>
+</main>
+<script src="` + siteDataFileName + `"></script>
+<script src="app.js"></script>
+</body>
+</html>
+`
+
+const siteStyleCSS = `* { box-sizing: border-box; }
+html, body { height: 100%; margin: 0; }
+body {
+  display: flex;
+  flex-direction: column;
+  background: #000;
+  color: #fff;
+  font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace;
+  font-size: 13px;
+}
+header { display: flex; align-items: center; gap: 8px; padding: 6px; border-bottom: 1px solid #555; }
+nav { display: flex; gap: 4px; }
+.tab { background: #222; color: #fff; border: 1px solid #555; padding: 4px 10px; cursor: pointer; font: inherit; }
+.tab.active { background: #3399ff; border-color: #3399ff; }
+#search { flex: 1; background: #111; color: #fff; border: 1px solid #555; padding: 4px 8px; font: inherit; }
+main { flex: 1; min-height: 0; }
+.page { display: flex; height: 100%; }
+.hidden { display: none; }
+.pane { flex: 1; display: flex; flex-direction: column; min-width: 0; border: 1px solid #555; margin: 2px; }
+.pane.wide { flex: 2; }
+.pane.widest { flex: 5; }
+.pane.column { border: none; margin: 0; }
+.pane-body { display: flex; flex-direction: column; min-height: 0; flex: 1; border: 1px solid #555; margin: 2px; }
+.pane-body.grow { flex: 3; }
+h2 { margin: 0; padding: 2px 6px; font-size: 13px; text-align: center; border-bottom: 1px solid #555; font-weight: normal; }
+.hint a { color: #aaa; }
+.list { list-style: none; margin: 0; padding: 0; overflow: auto; flex: 1; }
+.list li { padding: 2px 6px; cursor: pointer; }
+.list li:hover { background: #1a1a1a; }
+.list li.selected { background: #3399ff; }
+.list li.selected .main, .list li.selected .secondary { color: #000; }
+.secondary { color: #3f3; white-space: pre; }
+.tree { overflow: auto; flex: 1; padding: 2px 6px; }
+.tree ul { list-style: none; margin: 0; padding-left: 16px; }
+.tree > ul { padding-left: 0; }
+.tree .node { cursor: pointer; white-space: nowrap; }
+.tree .node.selected { background: #3399ff; color: #000 !important; }
+.tree .toggle { display: inline-block; width: 12px; color: #aaa; }
+.detail { margin: 0; padding: 4px 6px; overflow: auto; flex: 1; white-space: pre-wrap; }
+.detail a { color: #3399ff; }
+.color-covered-property { color: #fff; }
+.color-not-covered-property { color: #ff6666; }
+.color-granted-property { color: #696969; }
+.color-object-property { color: #3399ff; }
+.color-covered-schema { color: #fff; }
+.color-not-covered-schema { color: #f00; }
+.color-granted-schema { color: #808080; }
+.color-linked-tf-property { color: #fff; }
+.color-not-applicable-tf-property { color: #808080; }
+.color-unmapped-tf-property { color: #f00; }
+`
+
+const siteAppJS = `(function () {
+  "use strict";
+
+  var data = window.INSIGHT_DATA || { resource_providers: [], tf_resources: [] };
+  var query = "";
+  var state = { rp: null, api: null, schema: null };
+
+  function $(id) { return document.getElementById(id); }
+
+  function el(tag, className, text) {
+    var e = document.createElement(tag);
+    if (className) { e.className = className; }
+    if (text !== undefined) { e.textContent = text; }
+    return e;
+  }
+
+  function progressBar(percentage) {
+    var n = Math.floor(percentage * 10);
+    return "[" + "#".repeat(n) + " ".repeat(10 - n) + "] - " + (100 * percentage).toFixed(2) + "%";
+  }
+
+  function formatGrant(grant) {
+    var text = grant.comment || "";
+    if (grant.category) { text += "\n    Category: " + grant.category; }
+    if (grant.issue) { text += "\n    Issue: " + grant.issue; }
+    if (grant.owner) { text += "\n    Owner: " + grant.owner; }
+    if (grant.review_by) {
+      text += "\n    Review By: " + grant.review_by;
+      if (grant.expired) { text += " (expired)"; }
+    }
+    return text;
+  }
+
+  function matches(text) {
+    return query === "" || text.toLowerCase().indexOf(query) !== -1;
+  }
+
+  function schemaMatches(schema) {
+    if (matches(schema.addr)) { return true; }
+    return schema.properties.some(function (p) { return matches(p.address); });
+  }
+
+  function apiMatches(api) { return api.schemas.some(schemaMatches); }
+
+  function rpMatches(rp) { return rp.apis.some(apiMatches); }
+
+  function fillList(list, items, selected, render, onSelect) {
+    list.innerHTML = "";
+    items.forEach(function (item) {
+      var li = el("li");
+      render(li, item);
+      if (item === selected) { li.classList.add("selected"); }
+      li.addEventListener("click", function () { onSelect(item); });
+      list.appendChild(li);
+    });
+  }
+
+  function addText(li, mainText, mainClass, secondaryText) {
+    li.appendChild(el("div", "main " + (mainClass || ""), mainText));
+    if (secondaryText) { li.appendChild(el("div", "secondary", secondaryText)); }
+  }
+
+  // Swagger page
+
+  function refreshResourceProviderList() {
+    fillList($("rp-list"), data.resource_providers.filter(rpMatches), state.rp, function (li, rp) {
+      if (rp.grant) {
+        addText(li, rp.name, "color-granted-schema", "Granted: " + rp.grant.comment);
+      } else {
+        addText(li, rp.name);
+      }
+    }, function (rp) {
+      state.rp = rp; state.api = null; state.schema = null;
+      refreshSwaggerPage();
+    });
+  }
+
+  function apiCoverage(api) {
+    var covered = 0, total = 0;
+    api.schemas.forEach(function (s) {
+      if (s.grant) { return; }
+      total++;
+      if (s.covered !== 0) { covered++; }
+    });
+    return total === 0 ? 0 : covered / total;
+  }
+
+  function refreshApiVersionList() {
+    var apis = state.rp ? state.rp.apis.filter(apiMatches) : [];
+    fillList($("api-list"), apis, state.api, function (li, api) {
+      // The granted API version is not expected to be covered at all, hence no progress bar.
+      if (api.grant) {
+        addText(li, api.name, "color-granted-schema", "Granted: " + api.grant.comment);
+      } else {
+        addText(li, api.name, "", progressBar(apiCoverage(api)));
+      }
+      li.title = api.path;
+    }, function (api) {
+      state.api = api; state.schema = null;
+      refreshSwaggerPage();
+    });
+  }
+
+  function refreshSchemaList() {
+    var schemas = state.api ? state.api.schemas.filter(schemaMatches) : [];
+    fillList($("schema-list"), schemas, state.schema, function (li, schema) {
+      if (schema.grant) {
+        addText(li, schema.name, "color-granted-schema", schema.grant.expired ? "grant expired" : "");
+      } else if (schema.covered === 0) {
+        addText(li, schema.name, "color-not-covered-schema");
+      } else {
+        addText(li, schema.name, "color-covered-schema", progressBar(schema.total === 0 ? 0 : schema.covered / schema.total));
+      }
+      li.title = schema.addr;
+    }, function (schema) {
+      state.schema = schema;
+      refreshSwaggerPage();
+    });
+  }
+
+  function buildTree(schema) {
+    var root = { name: ".", children: [], childMap: {}, covered: 0, total: 0 };
+    schema.properties.forEach(function (prop) {
+      if (!matches(prop.address) && !matches(schema.addr)) { return; }
+      var node = root;
+      prop.segments.forEach(function (segment, idx) {
+        var child = node.childMap[segment];
+        if (!child) {
+          child = { name: segment, children: [], childMap: {}, covered: 0, total: 0 };
+          node.childMap[segment] = child;
+          node.children.push(child);
+        }
+        if (idx === prop.segments.length - 1) { child.prop = prop; }
+        node = child;
+      });
+    });
+    (function count(node) {
+      if (node.prop) {
+        if (!node.prop.grant) {
+          node.total = 1;
+          node.covered = node.prop.links && node.prop.links.length ? 1 : 0;
+        }
+        return;
+      }
+      node.children.forEach(function (c) {
+        count(c);
+        node.covered += c.covered;
+        node.total += c.total;
+      });
+    })(root);
+    return root;
+  }
+
+  function showPropertyDetail(node) {
+    var detail = $("property-detail");
+    if (!node.prop) {
+      detail.textContent = "Coverage: " + (node.total === 0 ? 0 : 100 * node.covered / node.total).toFixed(2) + "%";
+      return;
+    }
+    var prop = node.prop;
+    var text = "";
+    if (prop.grant) {
+      text = "Deliberately not supported in Terraform: " + formatGrant(prop.grant);
+      if (prop.grant.pattern) { text += "\n    Granted By: " + prop.grant.pattern + " (" + prop.grant.source + ")"; }
+    } else if (!prop.links || prop.links.length === 0) {
+      text = "To be supported in Terraform in the future.";
+    } else {
+      text = "Related Terraform Properties:\n\n" + prop.links.map(function (link) {
+        var s = "- " + link.resource + ": " + link.property;
+        if (link.transform) { s += "\n    Transform: " + link.transform; }
+        if (link.note) { s += "\n    Note: " + link.note; }
+        return s;
+      }).join("\n");
+    }
+    if (prop.description) { text += "\n\nDescription: " + prop.description; }
+    detail.textContent = text;
+  }
+
+  function renderTreeNode(node, expanded) {
+    var li = el("li");
+    var line = el("div", "node");
+    var toggle = el("span", "toggle", node.children.length ? (expanded ? "-" : "+") : "");
+    line.appendChild(toggle);
+    var label = el("span", "", node.name);
+    if (node.prop) {
+      if (node.prop.grant) {
+        line.classList.add("color-granted-property");
+      } else if (!node.prop.links || node.prop.links.length === 0) {
+        line.classList.add("color-not-covered-property");
+      } else {
+        line.classList.add("color-covered-property");
+      }
+    } else {
+      line.classList.add("color-object-property");
+    }
+    line.appendChild(label);
+    li.appendChild(line);
+
+    var ul = null;
+    if (node.children.length) {
+      ul = el("ul");
+      node.children.forEach(function (c) { ul.appendChild(renderTreeNode(c, expanded)); });
+      if (!expanded) { ul.classList.add("hidden"); }
+      li.appendChild(ul);
+    }
+    line.addEventListener("click", function () {
+      var selected = document.querySelector("#property-tree .node.selected");
+      if (selected) { selected.classList.remove("selected"); }
+      line.classList.add("selected");
+      showPropertyDetail(node);
+      if (ul) {
+        // Collapse if visible, expand if collapsed.
+        ul.classList.toggle("hidden");
+        toggle.textContent = ul.classList.contains("hidden") ? "+" : "-";
+      }
+    });
+    return li;
+  }
+
+  function refreshPropertyTree(expanded) {
+    var tree = $("property-tree");
+    tree.innerHTML = "";
+    $("property-detail").textContent = "";
+    if (!state.schema) { return; }
+    var root = buildTree(state.schema);
+    var ul = el("ul");
+    ul.appendChild(renderTreeNode(root, true));
+    tree.appendChild(ul);
+    setTreeExpanded(expanded || query !== "");
+  }
+
+  function setTreeExpanded(expanded) {
+    var uls = document.querySelectorAll("#property-tree > ul > li > ul ul");
+    Array.prototype.forEach.call(uls, function (ul) {
+      ul.classList.toggle("hidden", !expanded);
+      var toggle = ul.parentNode.querySelector(".toggle");
+      if (toggle) { toggle.textContent = expanded ? "-" : "+"; }
+    });
+  }
+
+  function refreshSwaggerPage() {
+    refreshResourceProviderList();
+    refreshApiVersionList();
+    refreshSchemaList();
+    refreshPropertyTree(false);
+  }
+
+  // Terraform page
+
+  var tfState = { resource: null, property: null };
+
+  function tfResourceMatches(res) {
+    if (matches(res.name)) { return true; }
+    return res.properties.some(function (p) { return matches(p.name); });
+  }
+
+  function refreshTerraformResourceList() {
+    if (data.tf_total) {
+      var total = data.tf_total;
+      var completeness = total.total === 0 ? 1 : (total.linked + total.not_applicable) / total.total;
+      $("tf-resource-title").textContent = "Terraform Resource " + progressBar(completeness);
+    }
+    fillList($("tf-resource-list"), data.tf_resources.filter(tfResourceMatches), tfState.resource, function (li, res) {
+      var cov = res.coverage;
+      var completeness = cov.total === 0 ? 1 : (cov.linked + cov.not_applicable) / cov.total;
+      var main = el("div", "main");
+      var a = el("a", "", res.name);
+      a.href = res.doc_url;
+      a.target = "_blank";
+      a.rel = "noopener";
+      a.style.color = "inherit";
+      main.appendChild(a);
+      li.appendChild(main);
+      li.appendChild(el("div", "secondary", progressBar(completeness) + " (linked: " + cov.linked + ", n/a: " + cov.not_applicable + ", unmapped: " + cov.unmapped + ")"));
+    }, function (res) {
+      tfState.resource = res; tfState.property = null;
+      refreshTerraformPage();
+    });
+  }
+
+  function showTerraformPropertyDetail(res, prop) {
+    var detail = $("tf-property-detail");
+    if (!prop) { detail.textContent = ""; return; }
+    if (prop.status === "not_applicable") {
+      detail.textContent = "Not applicable to Swagger: " + prop.not_applicable;
+      return;
+    }
+    if (prop.status === "unmapped") {
+      detail.textContent = "To be mapped in the knowledge base.";
+      return;
+    }
+    detail.textContent = "Related Swagger Properties:\n\n" + prop.links.map(function (link) {
+      var s = "- " + link.prop + " (" + (link.swagger || res.swagger) + ")";
+      if (link.transform) { s += "\n    Transform: " + link.transform; }
+      if (link.note) { s += "\n    Note: " + link.note; }
+      return s;
+    }).join("\n");
+  }
+
+  function refreshTerraformPropertyList() {
+    var res = tfState.resource;
+    var props = res ? res.properties.filter(function (p) { return matches(p.name) || matches(res.name); }) : [];
+    fillList($("tf-property-list"), props, tfState.property, function (li, prop) {
+      var colorClass = "color-linked-tf-property";
+      if (prop.status === "not_applicable") { colorClass = "color-not-applicable-tf-property"; }
+      if (prop.status === "unmapped") { colorClass = "color-unmapped-tf-property"; }
+      addText(li, prop.name, colorClass);
+    }, function (prop) {
+      tfState.property = prop;
+      refreshTerraformPropertyList();
+    });
+    showTerraformPropertyDetail(res, tfState.property);
+  }
+
+  function refreshTerraformPage() {
+    refreshTerraformResourceList();
+    refreshTerraformPropertyList();
+  }
+
+  // Navigation
+
+  function switchPage(page) {
+    ["swagger", "terraform"].forEach(function (p) {
+      $("page-" + p).classList.toggle("hidden", p !== page);
+      $("tab-" + p).classList.toggle("active", p === page);
+    });
+  }
+
+  Array.prototype.forEach.call(document.querySelectorAll(".tab"), function (tab) {
+    tab.addEventListener("click", function () { switchPage(tab.getAttribute("data-page")); });
+  });
+  document.addEventListener("keydown", function (e) {
+    if (e.key === "F1") { e.preventDefault(); switchPage("swagger"); }
+    if (e.key === "F2") { e.preventDefault(); switchPage("terraform"); }
+  });
+  $("expand-all").addEventListener("click", function (e) { e.preventDefault(); setTreeExpanded(true); });
+  $("collapse-all").addEventListener("click", function (e) { e.preventDefault(); setTreeExpanded(false); });
+  $("search").addEventListener("input", function (e) {
+    query = e.target.value.trim().toLowerCase();
+    refreshSwaggerPage();
+    refreshTerraformPage();
+  });
+
+  refreshSwaggerPage();
+  refreshTerraformPage();
+})();
+`
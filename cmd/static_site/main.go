@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Generate a static HTML site, mirroring the swagger and terraform pages of cmd/cli, from the swagger schema file (generated by
+cmd/swagger_schema). The site is a self-contained directory that can be opened locally or published as a build artifact.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	swaggerSchemaPath := flag.String("swagger-schema", "", "The path to the swagger schema file generated by cmd/swagger_schema")
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas, which is used to generate the terraform page (optional)")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path, which is used to show the property descriptions (optional)")
+	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants), which is used to show the granted resource providers and API versions (optional)")
+	outputDir := flag.String("output-dir", "", "The directory to generate the site into, which is created if not exists")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *swaggerSchemaPath == "" || *outputDir == "" {
+		log.Fatal("both -swagger-schema and -output-dir are required")
+	}
+
+	report, err := core.LoadSWGSchemaReport(*swaggerSchemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var tfschemas []core.TFSchema
+	if *tfSchemaDir != "" {
+		files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+		if err != nil {
+			if _, ok := err.(core.Diagnostics); !ok {
+				log.Fatal(err)
+			}
+			log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+		}
+		for _, f := range files {
+			tfschemas = append(tfschemas, f.TFSchema)
+		}
+	}
+
+	var grant *core.SWGGrant
+	if *swaggerGrantBaseDir != "" {
+		swggrant, err := core.NewSWGGrantFromFiles(*swaggerGrantBaseDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grant = &swggrant
+	}
+
+	var descriptions siteDescriptions
+	if *swaggerSpecPath != "" {
+		descriptions = loadSiteDescriptions(report, *swaggerSpecPath)
+	}
+
+	b, err := json.Marshal(newSiteData(report, tfschemas, grant, descriptions, time.Now()))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+	files := map[string]string{
+		"index.html":     siteIndexHTML,
+		"style.css":      siteStyleCSS,
+		"app.js":         siteAppJS,
+		siteDataFileName: "window.INSIGHT_DATA = " + string(b) + ";\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(*outputDir, name), []byte(content), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// The types below are the data consumed by the site, which mirror the hierarchy of the swagger page of cmd/cli
+// (resource provider -> API version -> schema -> property), together with the terraform resources.
+
+type siteGrant struct {
+	Comment  string `json:"comment,omitempty"`
+	Category string `json:"category,omitempty"`
+	Issue    string `json:"issue,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+	ReviewBy string `json:"review_by,omitempty"`
+	Expired  bool   `json:"expired,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+}
+
+func newSiteGrant(info core.GrantInfo, source, pattern string, now time.Time) *siteGrant {
+	return &siteGrant{
+		Comment:  info.Comment,
+		Category: string(info.Category),
+		Issue:    info.Issue,
+		Owner:    info.Owner,
+		ReviewBy: info.ReviewBy,
+		Expired:  info.IsExpired(now),
+		Source:   source,
+		Pattern:  pattern,
+	}
+}
+
+type siteTFLink struct {
+	Resource  string `json:"resource"`
+	Property  string `json:"property"`
+	Transform string `json:"transform,omitempty"`
+	Note      string `json:"note,omitempty"`
+}
+
+type siteSWGProperty struct {
+	Address     string       `json:"address"`
+	Segments    []string     `json:"segments"`
+	Description string       `json:"description,omitempty"`
+	Links       []siteTFLink `json:"links,omitempty"`
+	Grant       *siteGrant   `json:"grant,omitempty"`
+}
+
+type siteSWGSchema struct {
+	Name       string            `json:"name"`
+	Addr       string            `json:"addr"`
+	Covered    int               `json:"covered"`
+	Total      int               `json:"total"`
+	Grant      *siteGrant        `json:"grant,omitempty"`
+	Properties []siteSWGProperty `json:"properties"`
+}
+
+type siteSWGAPI struct {
+	Name    string          `json:"name"`
+	Path    string          `json:"path"`
+	Grant   *siteGrant      `json:"grant,omitempty"`
+	Schemas []siteSWGSchema `json:"schemas"`
+}
+
+type siteSWGResourceProvider struct {
+	Name  string       `json:"name"`
+	Grant *siteGrant   `json:"grant,omitempty"`
+	APIs  []siteSWGAPI `json:"apis"`
+}
+
+type siteTFProperty struct {
+	Name          string             `json:"name"`
+	Status        string             `json:"status"`
+	NotApplicable string             `json:"not_applicable,omitempty"`
+	Links         []core.SwaggerLink `json:"links,omitempty"`
+}
+
+type siteTFResource struct {
+	Name        string                `json:"name"`
+	SwaggerSpec string                `json:"swagger,omitempty"`
+	DocURL      string                `json:"doc_url"`
+	Coverage    core.TFSchemaCoverage `json:"coverage"`
+	Properties  []siteTFProperty      `json:"properties"`
+}
+
+type siteData struct {
+	ResourceProviders []siteSWGResourceProvider `json:"resource_providers"`
+	TFTotal           *core.TFSchemaCoverage    `json:"tf_total,omitempty"`
+	TFResources       []siteTFResource          `json:"tf_resources"`
+}
+
+// siteDescriptions records the descriptions of the swagger properties, keyed by the schema, then by the property address.
+type siteDescriptions map[core.SWGSchemaAddr]map[string]string
+
+// loadSiteDescriptions loads the descriptions of the properties of the swagger schema report from the swagger specs, as they
+// are not recorded in the report. The schemas that fail to be loaded are reported as warnings, and have no description.
+func loadSiteDescriptions(report core.SWGSchemaReport, swaggerBasePath string) siteDescriptions {
+	descriptions := siteDescriptions{}
+	for _, addr := range report.SortedAddrs() {
+		schema, err := core.NewSWGSchema(swaggerBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+		if err != nil {
+			log.Printf("Warning: failed to load the descriptions of %s: %v", addr, err)
+			continue
+		}
+		descriptions[addr] = map[string]string{}
+		for propAddr := range report[addr].Properties {
+			description, err := schema.FindPropertyDescription(propAddr)
+			if err != nil {
+				log.Printf("Warning: failed to load the description of %s of %s: %v", propAddr, addr, err)
+				continue
+			}
+			if description != "" {
+				descriptions[addr][propAddr] = description
+			}
+		}
+	}
+	return descriptions
+}
+
+// newSiteData builds the data of the site from the swagger schema report, the terraform schemas, the swagger grant and the
+// property descriptions, the latter three are optional.
+func newSiteData(report core.SWGSchemaReport, tfschemas []core.TFSchema, grant *core.SWGGrant, descriptions siteDescriptions, now time.Time) siteData {
+	data := siteData{
+		ResourceProviders: []siteSWGResourceProvider{},
+		TFResources:       []siteTFResource{},
+	}
+
+	for _, addr := range report.SortedAddrs() {
+		schema := report[addr]
+		relPath := filepath.ToSlash(addr.SwaggerRelPath())
+		rpName := addr.ResourceProvider()
+		apiPath := path.Dir(relPath)
+		apiName := path.Base(apiPath)
+
+		var rp *siteSWGResourceProvider
+		for i := range data.ResourceProviders {
+			if data.ResourceProviders[i].Name == rpName {
+				rp = &data.ResourceProviders[i]
+			}
+		}
+		if rp == nil {
+			data.ResourceProviders = append(data.ResourceProviders, siteSWGResourceProvider{Name: rpName})
+			rp = &data.ResourceProviders[len(data.ResourceProviders)-1]
+			if grant != nil {
+				if dirGrant, ok := grant.DirectoryGrant(rpName); ok {
+					rp.Grant = newSiteGrant(dirGrant.GrantInfo, dirGrant.Source(), "", now)
+				}
+			}
+		}
+
+		var api *siteSWGAPI
+		for i := range rp.APIs {
+			if rp.APIs[i].Path == apiPath {
+				api = &rp.APIs[i]
+			}
+		}
+		if api == nil {
+			rp.APIs = append(rp.APIs, siteSWGAPI{Name: apiName, Path: apiPath})
+			api = &rp.APIs[len(rp.APIs)-1]
+			if grant != nil {
+				if dirGrant, ok := grant.DirectoryGrant(filepath.FromSlash(apiPath)); ok {
+					api.Grant = newSiteGrant(dirGrant.GrantInfo, dirGrant.Source(), "", now)
+				}
+			}
+		}
+
+		api.Schemas = append(api.Schemas, newSiteSWGSchema(addr, schema.SWGSchema, api.Grant, descriptions[addr], now))
+	}
+
+	for _, rp := range data.ResourceProviders {
+		sort.SliceStable(rp.APIs, func(i, j int) bool {
+			return rp.APIs[i].Name < rp.APIs[j].Name
+		})
+		for _, api := range rp.APIs {
+			sort.SliceStable(api.Schemas, func(i, j int) bool {
+				return api.Schemas[i].Name < api.Schemas[j].Name
+			})
+		}
+	}
+
+	if len(tfschemas) != 0 {
+		tfReport := core.NewTFCoverageReport(tfschemas)
+		data.TFTotal = &tfReport.Total
+		schemaMap := map[string]core.TFSchema{}
+		for _, schema := range tfschemas {
+			schemaMap[schema.Name] = schema
+		}
+		for _, cov := range tfReport.Resources {
+			data.TFResources = append(data.TFResources, newSiteTFResource(schemaMap[cov.Name], cov))
+		}
+	}
+	return data
+}
+
+func newSiteSWGSchema(addr core.SWGSchemaAddr, schema *core.SWGSchema, apiGrant *siteGrant, descriptions map[string]string, now time.Time) siteSWGSchema {
+	out := siteSWGSchema{
+		Name:       addr.SchemaName(),
+		Addr:       string(addr),
+		Properties: []siteSWGProperty{},
+	}
+	switch {
+	case schema.IsGranted:
+		out.Grant = newSiteGrant(schema.GrantInfo(), schema.GrantSource, "", now)
+	case apiGrant != nil:
+		// Every schema underneath a granted API version is granted as a whole, the same as the swagger page of cmd/cli.
+		out.Grant = apiGrant
+	}
+
+	propAddrs := make([]string, 0, len(schema.Properties))
+	for propAddr := range schema.Properties {
+		propAddrs = append(propAddrs, propAddr)
+	}
+	sort.Strings(propAddrs)
+
+	for _, propAddr := range propAddrs {
+		prop := schema.Properties[propAddr]
+		siteProp := siteSWGProperty{
+			Address:     propAddr,
+			Description: descriptions[propAddr],
+		}
+		for _, segment := range propertyaddr.MustParseSwaggerPropertyAddr(propAddr).PropertyAddr {
+			siteProp.Segments = append(siteProp.Segments, segment.String())
+		}
+		if prop.IsGranted {
+			siteProp.Grant = newSiteGrant(prop.GrantInfo(), prop.GrantSource, prop.GrantPattern, now)
+		} else {
+			out.Total++
+			if len(prop.TFLinks) != 0 {
+				out.Covered++
+			}
+		}
+		for _, link := range prop.TFLinks {
+			siteProp.Links = append(siteProp.Links, siteTFLink{
+				Resource:  link.Prop.ResourceName,
+				Property:  link.Prop.PropertyAddr.String(),
+				Transform: string(link.Transform),
+				Note:      link.Note,
+			})
+		}
+		out.Properties = append(out.Properties, siteProp)
+	}
+	return out
+}
+
+func newSiteTFResource(schema core.TFSchema, cov core.TFSchemaCoverage) siteTFResource {
+	out := siteTFResource{
+		Name:        schema.Name,
+		SwaggerSpec: schema.SwaggerSpec,
+		DocURL:      core.TerraformResourceDocURL(schema.Name),
+		Coverage:    cov,
+		Properties:  []siteTFProperty{},
+	}
+
+	tfProps := make([]string, 0, len(schema.PropertyLinks))
+	for tfProp := range schema.PropertyLinks {
+		tfProps = append(tfProps, tfProp)
+	}
+	sort.Strings(tfProps)
+
+	for _, tfProp := range tfProps {
+		prop := siteTFProperty{
+			Name:   tfProp,
+			Status: string(schema.PropertyLinks.Status(tfProp)),
+		}
+		switch schema.PropertyLinks.Status(tfProp) {
+		case core.TFPropertyNotApplicable:
+			prop.NotApplicable, _ = schema.PropertyLinks.NotApplicableReason(tfProp)
+		case core.TFPropertyLinked:
+			prop.Links = schema.PropertyLinks[tfProp]
+		}
+		out.Properties = append(out.Properties, prop)
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Generate the swagger changelog between two API versions of a resource provider, independent of terraform. It reports the
+added/removed operations and definitions, the added/removed/retyped properties and the new enum values of each PUT body schema,
+and the new discriminator variants.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The local path to the swagger spec directory (e.g. azure-rest-api-specs/specification)")
+	rpDir := flag.String("rp-dir", "", "The directory that contains the API version directories, relative to the swagger spec directory (e.g. network/resource-manager/Microsoft.Network/stable)")
+	oldAPIVersion := flag.String("old-api-version", "", "The old API version (e.g. 2020-05-01)")
+	newAPIVersion := flag.String("new-api-version", "", "The new API version. If not specified, the latest API version in the resource provider directory is used.")
+	maxDepth := flag.Int("max-depth", 10, "The maximum depth of the swagger properties to be considered")
+	format := flag.String("format", formatMarkdown, fmt.Sprintf("The format of the changelog, one of %q and %q", formatMarkdown, formatJSON))
+	outputPath := flag.String("output", "", "The path of the changelog. If not specified, the changelog is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *swaggerSpecPath == "" || *rpDir == "" || *oldAPIVersion == "" {
+		log.Fatal("-swagger-spec-path, -rp-dir and -old-api-version are required")
+	}
+
+	if *newAPIVersion == "" {
+		latest, err := core.LatestSWGAPIVersion(*swaggerSpecPath, *rpDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*newAPIVersion = latest
+	}
+
+	changelog, err := core.NewSWGChangelog(*swaggerSpecPath, *rpDir, *oldAPIVersion, *newAPIVersion, *maxDepth)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatMarkdown:
+		err = changelog.WriteMarkdown(&buf)
+	case formatJSON:
+		var b []byte
+		b, err = json.MarshalIndent(changelog, "", "  ")
+		buf.Write(b)
+		buf.WriteString("\n")
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
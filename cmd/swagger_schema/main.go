@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -26,8 +26,11 @@ func main() {
 
 	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
 	swaggerGrantBaseDir := flag.String("swagger-grant-dir", "", "The path to the base directory contains swagger grant info (e.g. azure_knowledgebase/swagger_grants)")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version")
 	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
 	outputPath := flag.String("output", filepath.Join(pwd, "swagger_schema.json"), "The output file")
+	csvDir := flag.String("csv-dir", "", `The directory to additionally export the swagger properties and the terraform properties into, as "swagger_properties.csv" and "terraform_properties.csv" (optional)`)
+	sqlitePath := flag.String("sqlite", "", "The path of the SQLite database to additionally export the swagger properties and the terraform properties into, which is overwritten if exists (optional)")
 	showHelp := flag.Bool("help", false, "Display this message")
 
 	flag.Parse()
@@ -53,26 +56,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	swgschemas, err := core.NewSWGSchemasFromTerraformSchema(*swaggerSpecPath, *tfSchemaDir, *swaggerGrantBaseDir)
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	// Construct a temporary type to include the property coverage info in schema level.
-	type swgSchemaWithCoverage struct {
-		Coverage float64
-		*core.SWGSchema
+	swgschemas, err := core.NewSWGSchemasFromTFSchemaFiles(*swaggerSpecPath, files, *swaggerGrantBaseDir, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		log.Fatal(err)
 	}
-	schemaMap := map[core.SWGSchemaAddr]swgSchemaWithCoverage{}
-	for schemaAddr, schema := range swgschemas.GetAll() {
-		covered, total := schema.SchemaCoverage()
-		schemaMap[schemaAddr] = swgSchemaWithCoverage{
-			Coverage:  float64(covered) / float64(total),
-			SWGSchema: schema,
-		}
+	if len(swgschemas.StaleInheritedGrants) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", swgschemas.StaleInheritedGrants)
 	}
 
-	b, err := json.MarshalIndent(schemaMap, "", "  ")
+	b, err := core.EncodeFile(core.FileKindSWGSchemaReport, core.FileFormatJSON, core.NewSWGSchemaReport(swgschemas), nil)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -80,4 +76,51 @@ func main() {
 	if err := ioutil.WriteFile(*outputPath, b, 0644); err != nil {
 		log.Fatal(err)
 	}
+
+	if *csvDir == "" && *sqlitePath == "" {
+		return
+	}
+
+	tfschemas := make([]core.TFSchema, 0, len(files))
+	for _, f := range files {
+		tfschemas = append(tfschemas, f.TFSchema)
+	}
+	swgRows := core.NewSWGPropertyRows(swgschemas.GetAll())
+	tfRows := core.NewTFPropertyRows(tfschemas)
+
+	if *csvDir != "" {
+		if err := writeCSVFiles(*csvDir, swgRows, tfRows); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *sqlitePath != "" {
+		if err := writeSQLite(*sqlitePath, swgRows, tfRows); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// writeCSVFiles writes the rows into "swagger_properties.csv" and "terraform_properties.csv" under the directory.
+func writeCSVFiles(dir string, swgRows []core.SWGPropertyRow, tfRows []core.TFPropertyRow) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tables := []struct {
+		name    string
+		columns []string
+		rows    [][]interface{}
+	}{
+		{"swagger_properties", core.SWGPropertyColumns, core.SWGPropertyRowValues(swgRows)},
+		{"terraform_properties", core.TFPropertyColumns, core.TFPropertyRowValues(tfRows)},
+	}
+	for _, table := range tables {
+		var buf bytes.Buffer
+		if err := core.WriteCSV(&buf, table.columns, table.rows); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, table.name+".csv"), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
 }
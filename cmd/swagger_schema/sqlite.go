@@ -0,0 +1,84 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema is the schema of the exported SQLite database, which is documented in doc/swagger_schema_export.md.
+// The columns are in the order of core.SWGPropertyColumns and core.TFPropertyColumns respectively.
+const sqliteSchema = `
+CREATE TABLE swagger_properties (
+	resource_provider TEXT NOT NULL,
+	api_version       TEXT NOT NULL,
+	swagger_file      TEXT NOT NULL,
+	schema            TEXT NOT NULL,
+	property          TEXT NOT NULL,
+	type              TEXT NOT NULL,
+	read_only         BOOLEAN NOT NULL,
+	granted           BOOLEAN NOT NULL,
+	grant_comment     TEXT NOT NULL,
+	tf_links          TEXT NOT NULL,
+	PRIMARY KEY (swagger_file, schema, property)
+);
+
+CREATE TABLE terraform_properties (
+	resource              TEXT NOT NULL,
+	property              TEXT NOT NULL,
+	status                TEXT NOT NULL,
+	not_applicable_reason TEXT NOT NULL,
+	swagger_spec          TEXT NOT NULL,
+	swagger_links         TEXT NOT NULL,
+	PRIMARY KEY (resource, property)
+);
+`
+
+// writeSQLite writes the rows into a new SQLite database, the existing database file is overwritten.
+func writeSQLite(path string, swgRows []core.SWGPropertyRow, tfRows []core.TFPropertyRow) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("creating tables: %v", err)
+	}
+	if err := insertRows(tx, "swagger_properties", core.SWGPropertyColumns, core.SWGPropertyRowValues(swgRows)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := insertRows(tx, "terraform_properties", core.TFPropertyColumns, core.TFPropertyRowValues(tfRows)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertRows(tx *sql.Tx, table string, columns []string, rows [][]interface{}) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",")
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ","), placeholders))
+	if err != nil {
+		return fmt.Errorf("preparing insert statement of %s: %v", table, err)
+	}
+	defer stmt.Close()
+	for _, row := range rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			return fmt.Errorf("inserting %v into %s: %v", row, table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Check the swagger schema coverage against a committed baseline (a swagger schema file generated by cmd/swagger_schema),
+and against the minimum coverage thresholds of the resource providers and the swagger schemas. Exit with a non-zero code
+if there is any regression.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	baselinePath := flag.String("baseline", "", "The path to the baseline swagger schema file")
+	currentPath := flag.String("current", "", "The path to the current swagger schema file, or the current knowledge base directory (e.g. azure_knowledgebase)")
+	thresholdsPath := flag.String("thresholds", "", "The path to the JSON/YAML file contains the minimum coverage thresholds of the resource providers and the swagger schemas")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version (only for knowledge base directory)")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification), required for knowledge base directory")
+	updateBaseline := flag.Bool("update-baseline", false, "Overwrite the baseline with the current swagger schema coverage instead of checking it")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *baselinePath == "" || *currentPath == "" {
+		log.Fatal("both -baseline and -current are required")
+	}
+
+	current, stale, err := core.NewSWGSchemaReportFromPath(*currentPath, *swaggerSpecPath, core.SWGGrantOptions{InheritMissing: *inheritGrants})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(stale) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", stale)
+	}
+
+	if *updateBaseline {
+		b, err := core.EncodeFile(core.FileKindSWGSchemaReport, core.FileFormatJSON, current, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*baselinePath, b, 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	baseline, err := core.LoadSWGSchemaReport(*baselinePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var thresholds core.SWGCoverageThresholds
+	if *thresholdsPath != "" {
+		thresholds, err = core.LoadSWGCoverageThresholds(*thresholdsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	regressions := core.CheckSWGCoverage(baseline, current, thresholds)
+	if err := regressions.WriteText(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	if len(regressions) != 0 {
+		os.Exit(1)
+	}
+}
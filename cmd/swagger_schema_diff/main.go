@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `Diff two swagger schema files (generated by cmd/swagger_schema), or two knowledge base directories (e.g. azure_knowledgebase),
+reporting the newly covered properties, the lost links, the new grants and the coverage delta of each swagger schema.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	oldPath := flag.String("old", "", "The path to the old swagger schema file, or the old knowledge base directory")
+	newPath := flag.String("new", "", "The path to the new swagger schema file, or the new knowledge base directory")
+	inheritGrants := flag.Bool("inherit-grants", false, "Whether the swagger specs without a grant file inherit the grants from the closest older API version (only for knowledge base directories)")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification), required for knowledge base directories")
+	format := flag.String("format", formatText, fmt.Sprintf("The format of the diff report, one of %q, %q and %q", formatText, formatMarkdown, formatJSON))
+	outputPath := flag.String("output", "", "The path of the diff report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *oldPath == "" || *newPath == "" {
+		log.Fatal("both -old and -new are required")
+	}
+
+	grantOpts := core.SWGGrantOptions{InheritMissing: *inheritGrants}
+	oldReport, oldStale, err := core.NewSWGSchemaReportFromPath(*oldPath, *swaggerSpecPath, grantOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	newReport, newStale, err := core.NewSWGSchemaReportFromPath(*newPath, *swaggerSpecPath, grantOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if stale := append(oldStale, newStale...); len(stale) != 0 {
+		log.Printf("Warning: some of the inherited grants are stale:\n%v", stale)
+	}
+
+	diff := core.NewSWGSchemaReportDiff(oldReport, newReport)
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatText:
+		err = diff.WriteText(&buf)
+	case formatMarkdown:
+		err = diff.WriteMarkdown(&buf)
+	case formatJSON:
+		var b []byte
+		b, err = json.MarshalIndent(diff, "", "  ")
+		buf.Write(b)
+		buf.WriteString("\n")
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -29,6 +29,7 @@ func main() {
 	resource := flag.String("resource", "", "The Terraform resource name which to generate its flattened schema scaffold. If not specified, will apply to all resources available.")
 	isDataSource := flag.Bool("data-source", false, "Whether applies to data source")
 	isNew := flag.Bool("-new", false, "Wehther to generate the brand new terraform schema file regardless of existing schemas.")
+	format := flag.String("format", string(core.FileFormatJSON), `The format of the newly generated terraform schema files, either "json" or "yaml". The existing schema files are updated in their own format.`)
 	showHelp := flag.Bool("help", false, "Display this message")
 
 	flag.Parse()
@@ -38,6 +39,11 @@ func main() {
 		return
 	}
 
+	ofmt := core.FileFormat(*format)
+	if ofmt != core.FileFormatJSON && ofmt != core.FileFormatYAML {
+		log.Fatalf("Unknown format: %s", *format)
+	}
+
 	// Read the provider schema
 	b, err := ioutil.ReadFile(*providerSchemaPath)
 	if err != nil {
@@ -77,7 +83,7 @@ func main() {
 				log.Fatalf("No such resource: %s", *resource)
 			}
 		}
-		if err := genFile(prefix+*resource, schema.Block, *outputPath, *isNew); err != nil {
+		if err := genFile(prefix+*resource, schema.Block, *outputPath, ofmt, *isNew); err != nil {
 			log.Fatal(err)
 		}
 		return
@@ -95,38 +101,38 @@ func main() {
 	}
 
 	for res, schema := range schemas {
-		if err := genFile(oprefix+res, schema.Block, *outputPath, *isNew); err != nil {
+		if err := genFile(oprefix+res, schema.Block, *outputPath, ofmt, *isNew); err != nil {
 			log.Fatal(err)
 		}
 	}
 	return
 }
 
-func genFile(schemaName string, blk *core.TerraformBlock, odir string, isNew bool) error {
+func genFile(schemaName string, blk *core.TerraformBlock, odir string, format core.FileFormat, isNew bool) error {
 
-	var schema *core.TFSchema
-	ofile := filepath.Join(odir, schemaName+".json")
+	var (
+		schema     *core.TFSchema
+		oldContent []byte
+	)
+	ofile := filepath.Join(odir, schemaName+"."+string(format))
 
-	// If the meta file already exists and the user doesn't specify the -new flag,
-	// we will generate the new schema by updating the existing one.
-	if stat, err := os.Stat(ofile); err == nil && stat.Mode().IsRegular() && !isNew {
-		b, err := ioutil.ReadFile(ofile)
+	// If the meta file already exists (in any format) and the user doesn't specify the -new flag,
+	// we will generate the new schema by updating the existing one, in its own format.
+	if existFile, ok := findSchemaFile(odir, schemaName); ok && !isNew {
+		f, err := core.LoadTFSchemaFile(existFile)
 		if err != nil {
-			return err
-		}
-		var oldSchema core.TFSchema
-		if err := json.Unmarshal(b, &oldSchema); err != nil {
 			return fmt.Errorf("failed to unmarshal for schema %q: %v", schemaName, err)
 		}
-		schema, err = core.UpdateSchemaScaffoldFromTerraformBlock(schemaName, blk, &oldSchema)
+		schema, err = core.UpdateSchemaScaffoldFromTerraformBlock(schemaName, blk, &f.TFSchema)
 		if err != nil {
 			return err
 		}
+		ofile, format, oldContent = existFile, f.Format, f.Content
 	} else {
 		schema = core.NewSchemaScaffoldFromTerraformBlock(schemaName, blk)
 	}
 
-	b, err := json.MarshalIndent(schema, "", "  ")
+	b, err := core.EncodeFile(core.FileKindTFSchema, format, schema, oldContent)
 	if err != nil {
 		return err
 	}
@@ -136,3 +142,14 @@ func genFile(schemaName string, blk *core.TerraformBlock, odir string, isNew boo
 	return nil
 
 }
+
+// findSchemaFile finds the existing terraform schema file of the schema in the directory, in any of the supported formats.
+func findSchemaFile(odir, schemaName string) (string, bool) {
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		path := filepath.Join(odir, schemaName+ext)
+		if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+			return path, true
+		}
+	}
+	return "", false
+}
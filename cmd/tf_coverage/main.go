@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, "Report the terraform side coverage, i.e. the terraform properties that are linked, marked as not applicable, or still unmapped, per resource and aggregated across the provider.\n\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	outputPath := flag.String("output", "", "The path of the JSON report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	// The files that are failed to be loaded are reported, but they don't prevent the others from being reported.
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+	}
+
+	schemas := make([]core.TFSchema, 0, len(files))
+	for _, f := range files {
+		schemas = append(schemas, f.TFSchema)
+	}
+
+	b, err := json.MarshalIndent(core.NewTFCoverageReport(schemas), "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Println(string(b))
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
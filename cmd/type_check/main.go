@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, "Check the type compatibility between the terraform properties and the swagger properties they link to.\n\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The path to the swagger spec directory, either a HTTP URI or local path (e.g. https://raw.githubusercontent.com/Azure/azure-rest-api-specs/master/specification)")
+	providerSchemaPath := flag.String("provider-schema", "", `The path to the Terraform provider schema file (generated by "$ terraform providers schema -json")`)
+	providerName := flag.String("provider-name", "registry.terraform.io/hashicorp/azurerm", "Full qualified name of the provider")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	provider, err := core.LoadTerraformProvider(*providerSchemaPath, *providerName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The files that are failed to be loaded or linked are reported, but they don't prevent the others from being checked.
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+	}
+
+	swgschemas, err := core.NewSWGSchemasFromTFSchemaFiles(*swaggerSpecPath, files, "", core.SWGGrantOptions{})
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to link some of the terraform schemas:\n%v", err)
+	}
+
+	var diags core.Diagnostics
+	for _, f := range files {
+		block, ok := provider.FindBlock(f.Name)
+		if !ok {
+			diags = append(diags, f.Locate(fmt.Errorf("%s is not found in the provider schema", f.Name))...)
+			continue
+		}
+		if err := f.CheckTypeCompatibility(swgschemas, block, core.DefaultTypeConversions); err != nil {
+			diags = append(diags, f.Locate(err)...)
+		}
+	}
+
+	if len(diags) == 0 {
+		return
+	}
+	diags.Sort()
+	for _, diag := range diags {
+		fmt.Println(diag)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, `List the Azure resource types that no terraform resource links to. The candidate resource types are the paths that have
+the PUT, GET and DELETE operations in the latest API versions of the resource providers, whose resource types are derived from
+the path templates (e.g. "Microsoft.Network/virtualNetworks/subnets"). The resource types are ordered by the amount of the
+properties of their PUT body schemas.
+
+`)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	tfSchemaDir := flag.String("tf-schema-dir", "", "The path to the directory contains terraform schemas")
+	swaggerSpecPath := flag.String("swagger-spec-path", "", "The local path to the swagger spec directory (e.g. azure-rest-api-specs/specification), which is walked to find the latest API versions")
+	rps := flag.String("rp", "", "The comma separated resource provider directories relative to the swagger spec directory (e.g. network,compute). If not specified, the ones linked by the terraform resources are used.")
+	maxDepth := flag.Int("max-depth", 10, "The maximum depth of the swagger properties to be considered")
+	format := flag.String("format", formatMarkdown, fmt.Sprintf("The format of the report, one of %q and %q", formatMarkdown, formatJSON))
+	outputPath := flag.String("output", "", "The path of the report. If not specified, the report is printed to stdout.")
+	showHelp := flag.Bool("help", false, "Display this message")
+
+	flag.Parse()
+
+	if *showHelp {
+		flag.Usage()
+		return
+	}
+
+	if *tfSchemaDir == "" || *swaggerSpecPath == "" {
+		log.Fatal("both -tf-schema-dir and -swagger-spec-path are required")
+	}
+
+	// The files that are failed to be loaded are reported, but they don't prevent the others from being reported.
+	files, err := core.LoadTFSchemaFiles(*tfSchemaDir)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: failed to load some of the terraform schemas:\n%v", err)
+	}
+	schemas := make([]core.TFSchema, 0, len(files))
+	for _, f := range files {
+		schemas = append(schemas, f.TFSchema)
+	}
+
+	var rpDirs []string
+	if *rps != "" {
+		rpDirs = strings.Split(*rps, ",")
+	}
+
+	types, err := core.NewSWGUnlinkedResourceTypes(*swaggerSpecPath, rpDirs, schemas, *maxDepth)
+	if err != nil {
+		if _, ok := err.(core.Diagnostics); !ok {
+			log.Fatal(err)
+		}
+		log.Printf("Warning: some of the swagger files are skipped:\n%v", err)
+	}
+
+	var buf bytes.Buffer
+	switch *format {
+	case formatMarkdown:
+		err = core.WriteSWGUnlinkedResourceTypesMarkdown(&buf, types)
+	case formatJSON:
+		var b []byte
+		b, err = json.MarshalIndent(types, "", "  ")
+		buf.Write(b)
+		buf.WriteString("\n")
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputPath == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
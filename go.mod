@@ -10,8 +10,10 @@ require (
 	github.com/rivo/tview v0.0.0-20200915114512-42866ecf6ca6
 	github.com/stretchr/testify v1.6.1
 	github.com/zclconf/go-cty v1.6.1
-	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
+	golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
+	gopkg.in/yaml.v3 v3.0.1
+	modernc.org/sqlite v1.21.2
 )
 
 replace github.com/go-openapi/spec => github.com/magodo/spec v0.19.10-0.20201124144715-3e5006560d1f
@@ -0,0 +1,294 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic records a single problem found in one of the knowledge base files (e.g. a TFSchema file or a grant file).
+type Diagnostic struct {
+	// The path of the knowledge base file where the problem resides in. Empty if the problem is not related to any file.
+	File string
+
+	// The JSON pointer (RFC 6901) of the offending entry inside the file (e.g. "/PropertyLinks/foo/0/prop").
+	Pointer string
+
+	// The 1-based position of the offending entry inside the file. Zero if it is unknown.
+	Line   int
+	Column int
+
+	// The rule (i.e. the check) that reports the problem, empty if the problem is not reported by any known check.
+	Rule DiagnosticRule
+
+	Err error
+}
+
+// DiagnosticRule identifies a check on the knowledge base files, which is used as the rule ID of the reported problems.
+type DiagnosticRule string
+
+const (
+	// RuleTFSchemaInvalid reports a TFSchema file that fails to be decoded, or that is invalid.
+	RuleTFSchemaInvalid DiagnosticRule = "tfschema-invalid"
+	// RuleTFSchemaLink reports a link of a TFSchema file that fails to be linked to the swagger property.
+	RuleTFSchemaLink DiagnosticRule = "tfschema-link"
+	// RuleTFSchemaProvider reports a TFSchema file whose terraform resource is not found in the provider schema.
+	RuleTFSchemaProvider DiagnosticRule = "tfschema-provider"
+	// RuleTFSchemaType reports a link between a terraform property and a swagger property of incompatible types.
+	RuleTFSchemaType DiagnosticRule = "tfschema-type"
+	// RuleGrantInvalid reports a grant file that fails to be decoded, or that is invalid.
+	RuleGrantInvalid DiagnosticRule = "grant-invalid"
+	// RuleGrantUnapplicable reports a grant that can't be applied onto the swagger schemas.
+	RuleGrantUnapplicable DiagnosticRule = "grant-unapplicable"
+	// RuleGrantStaleInherited reports an inherited property grant that no longer applies to the newer API version.
+	RuleGrantStaleInherited DiagnosticRule = "grant-stale-inherited"
+	// RuleCoverage reports a swagger schema whose coverage fails to be calculated.
+	RuleCoverage DiagnosticRule = "coverage"
+	// RuleUncoveredRequiredProperty reports a required swagger property that is neither linked nor granted, while its parent
+	// is present.
+	RuleUncoveredRequiredProperty DiagnosticRule = "uncovered-required-property"
+)
+
+func (d Diagnostic) Error() string {
+	loc := d.File
+	if loc != "" && d.Line != 0 {
+		loc += fmt.Sprintf(":%d:%d", d.Line, d.Column)
+	}
+	if loc == "" {
+		return d.Err.Error()
+	}
+	return loc + ": " + d.Err.Error()
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics aggregates the problems found so that all of them can be reported at once, rather than stopping at the first one.
+type Diagnostics []Diagnostic
+
+func (diags Diagnostics) Error() string {
+	msgs := make([]string, 0, len(diags))
+	for _, diag := range diags {
+		msgs = append(msgs, diag.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Err returns the Diagnostics as an error, or nil if there is no diagnostic.
+func (diags Diagnostics) Err() error {
+	if len(diags) == 0 {
+		return nil
+	}
+	return diags
+}
+
+// Append appends the error to the Diagnostics. If the error is already a Diagnostics or a Diagnostic, it is flattened.
+func (diags *Diagnostics) Append(err error) {
+	switch err := err.(type) {
+	case nil:
+		return
+	case Diagnostics:
+		*diags = append(*diags, err...)
+	case Diagnostic:
+		*diags = append(*diags, err)
+	default:
+		*diags = append(*diags, Diagnostic{Err: err})
+	}
+}
+
+// appendWithRule appends the error to the Diagnostics as Append does, with the rule set onto the appended Diagnostics.
+func (diags *Diagnostics) appendWithRule(err error, rule DiagnosticRule) {
+	var appended Diagnostics
+	appended.Append(err)
+	*diags = append(*diags, appended.WithRule(rule)...)
+}
+
+// Sort sorts the Diagnostics by file and position, which gives a stable output.
+func (diags Diagnostics) Sort() {
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		if diags[i].Line != diags[j].Line {
+			return diags[i].Line < diags[j].Line
+		}
+		return diags[i].Column < diags[j].Column
+	})
+}
+
+// WithRule sets the rule of each Diagnostic that has no rule set yet.
+func (diags Diagnostics) WithRule(rule DiagnosticRule) Diagnostics {
+	out := make(Diagnostics, 0, len(diags))
+	for _, diag := range diags {
+		if diag.Rule == "" {
+			diag.Rule = rule
+		}
+		out = append(out, diag)
+	}
+	return out
+}
+
+// WithFile sets the file of each Diagnostic, and resolves the position of each Diagnostic whose pointer is found in the file content.
+// The file content is regarded as JSON, unless the file has a YAML extension.
+func (diags Diagnostics) WithFile(file string, content []byte) Diagnostics {
+	format, ok := FileFormatOf(file)
+	if !ok {
+		format = FileFormatJSON
+	}
+	positions, err := pointerPositions(format, content)
+	if err != nil {
+		positions = map[string]filePosition{}
+	}
+	out := make(Diagnostics, 0, len(diags))
+	for _, diag := range diags {
+		diag.File = file
+		if pos, ok := positions[diag.Pointer]; ok {
+			diag.Line, diag.Column = pos.Line, pos.Column
+		}
+		out = append(out, diag)
+	}
+	return out
+}
+
+// resolveFiles resolves the file (which is relative to the baseDir) and the position of each Diagnostic.
+func (diags Diagnostics) resolveFiles(baseDir string) Diagnostics {
+	files := []string{}
+	diagsByFile := map[string]Diagnostics{}
+	for _, diag := range diags {
+		if _, ok := diagsByFile[diag.File]; !ok {
+			files = append(files, diag.File)
+		}
+		diagsByFile[diag.File] = append(diagsByFile[diag.File], diag)
+	}
+
+	out := make(Diagnostics, 0, len(diags))
+	for _, file := range files {
+		path := filepath.Join(baseDir, file)
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			b = nil
+		}
+		out = append(out, diagsByFile[file].WithFile(path, b)...)
+	}
+	return out
+}
+
+// newFileDiagnostic constructs a Diagnostic for an error happened when decoding a file, with the position resolved if possible.
+func newFileDiagnostic(file string, content []byte, err error) Diagnostic {
+	if diag, ok := err.(Diagnostic); ok {
+		return Diagnostics{diag}.WithFile(file, content)[0]
+	}
+	diag := Diagnostic{File: file, Err: err}
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		if m := yamlErrorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			diag.Line, _ = strconv.Atoi(m[1])
+			diag.Column = 1
+		}
+		return diag
+	}
+	pos := offsetToPosition(content, int(offset))
+	diag.Line, diag.Column = pos.Line, pos.Column
+	return diag
+}
+
+// newFileDiagnostics is the same as newFileDiagnostic, except that a Diagnostics error (e.g. returned by DecodeFile) is kept
+// as is, with the file and positions set.
+func newFileDiagnostics(file string, content []byte, err error) Diagnostics {
+	if diags, ok := err.(Diagnostics); ok {
+		return diags.WithFile(file, content)
+	}
+	return Diagnostics{newFileDiagnostic(file, content, err)}
+}
+
+// jsonPointer builds a JSON pointer from its reference tokens, escaping each of them.
+func jsonPointer(tokens ...string) string {
+	replacer := strings.NewReplacer("~", "~0", "/", "~1")
+	var ptr string
+	for _, token := range tokens {
+		ptr += "/" + replacer.Replace(token)
+	}
+	return ptr
+}
+
+type filePosition struct {
+	Line   int
+	Column int
+}
+
+// jsonPointerPositions returns the position of each JSON value inside the JSON document, keyed by the JSON pointer of the value.
+func jsonPointerPositions(b []byte) (map[string]filePosition, error) {
+	positions := map[string]filePosition{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	var walk func(ptr string) error
+	walk = func(ptr string) error {
+		positions[ptr] = offsetToPosition(b, nextTokenOffset(b, int(dec.InputOffset())))
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(ptr + jsonPointer(key.(string))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(ptr + jsonPointer(strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+		}
+		return nil
+	}
+
+	if err := walk(""); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// nextTokenOffset skips the whitespaces and the separators starting from the offset, which points to the start of the next token.
+func nextTokenOffset(b []byte, offset int) int {
+	for offset < len(b) {
+		switch b[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func offsetToPosition(b []byte, offset int) filePosition {
+	if offset > len(b) {
+		offset = len(b)
+	}
+	line := 1 + bytes.Count(b[:offset], []byte("\n"))
+	column := offset - bytes.LastIndexByte(b[:offset], '\n')
+	return filePosition{Line: line, Column: column}
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPointerPositions(t *testing.T) {
+	input := []byte(`{
+  "Name": "res1",
+  "PropertyLinks": {
+    "a/b": [],
+    "p1": [
+      {
+        "prop": "schema1:p1"
+      },
+      {"prop": "schema1:p2", "swagger": "bar.json"}
+    ]
+  }
+}`)
+
+	expect := map[string]filePosition{
+		"":                            {Line: 1, Column: 1},
+		"/Name":                       {Line: 2, Column: 11},
+		"/PropertyLinks":              {Line: 3, Column: 20},
+		"/PropertyLinks/a~1b":         {Line: 4, Column: 12},
+		"/PropertyLinks/p1":           {Line: 5, Column: 11},
+		"/PropertyLinks/p1/0":         {Line: 6, Column: 7},
+		"/PropertyLinks/p1/0/prop":    {Line: 7, Column: 17},
+		"/PropertyLinks/p1/1":         {Line: 9, Column: 7},
+		"/PropertyLinks/p1/1/prop":    {Line: 9, Column: 16},
+		"/PropertyLinks/p1/1/swagger": {Line: 9, Column: 41},
+	}
+
+	actual, err := jsonPointerPositions(input)
+	require.NoError(t, err)
+	require.Equal(t, expect, actual)
+}
+
+func TestNewSWGSchemasFromTerraformSchema_Diagnostics(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger")
+	tfSchemaDir := filepath.Join(pwd, "testdata", "terraform_schema_invalid")
+	grantDir := filepath.Join(pwd, "testdata", "swagger_grants_invalid")
+
+	type position struct {
+		file   string
+		line   int
+		column int
+		rule   DiagnosticRule
+	}
+	expect := []position{
+		{filepath.Join(grantDir, "foo.json"), 5, 20, RuleGrantUnapplicable},
+		{filepath.Join(tfSchemaDir, "res1.json"), 12, 17, RuleTFSchemaLink},
+		{filepath.Join(tfSchemaDir, "res1.json"), 17, 17, RuleTFSchemaLink},
+		{filepath.Join(tfSchemaDir, "res2.json"), 7, 17, RuleTFSchemaInvalid},
+	}
+
+	swgschemas, err := NewSWGSchemasFromTerraformSchema(specBasePath, tfSchemaDir, grantDir, SWGGrantOptions{})
+	require.Error(t, err)
+	diags, ok := err.(Diagnostics)
+	require.True(t, ok)
+
+	actual := []position{}
+	for _, diag := range diags {
+		actual = append(actual, position{diag.File, diag.Line, diag.Column, diag.Rule})
+	}
+	require.Equal(t, expect, actual)
+
+	// The valid links and grants are still applied
+	schema := swgschemas.Get(NewSWGSchemaAddr("foo.json", "def_a"))
+	require.NotNil(t, schema)
+	require.Len(t, schema.Properties["prop_primitive"].TFLinks, 1)
+	require.True(t, schema.Properties["p2"].IsGranted)
+}
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileFormat is the format of a knowledge base file (e.g. a TFSchema file or a grant file).
+type FileFormat string
+
+const (
+	FileFormatJSON FileFormat = "json"
+	FileFormatYAML FileFormat = "yaml"
+)
+
+// yamlErrorLinePattern matches the line number reported by the YAML decoder (e.g. "yaml: line 3: mapping values are not allowed in this context").
+var yamlErrorLinePattern = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// maxYAMLNestingDepth limits the nesting depth of the YAML nodes being walked, which is far beyond the nesting of any knowledge
+// base file, to avoid exhausting the stack on a maliciously nested document.
+const maxYAMLNestingDepth = 32
+
+// FileFormatOf tells the format of a knowledge base file by its extension.
+func FileFormatOf(path string) (FileFormat, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FileFormatJSON, true
+	case ".yaml", ".yml":
+		return FileFormatYAML, true
+	}
+	return "", false
+}
+
+// UnmarshalFile decodes the content of a knowledge base file in the specified format.
+// The YAML content is converted to JSON before being decoded, so that the types (un)marshalled in a customized way (e.g. the
+// property addresses) are handled the same for both formats. A decoding error of the YAML content is reported as a Diagnostic
+// pointing to the offending entry.
+func UnmarshalFile(format FileFormat, b []byte, v interface{}) error {
+	if format != FileFormatYAML {
+		return json.Unmarshal(b, v)
+	}
+
+	jsonContent, err := fileContentToJSON(format, b)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(jsonContent, v); err != nil {
+		var offset int64
+		switch err := err.(type) {
+		case *json.UnmarshalTypeError:
+			offset = err.Offset
+		default:
+			return err
+		}
+		return Diagnostic{Pointer: jsonPointerAt(jsonContent, offset), Err: err}
+	}
+	return nil
+}
+
+// fileContentToJSON converts the content of a knowledge base file in the specified format to JSON, the key order is kept.
+func fileContentToJSON(format FileFormat, b []byte) ([]byte, error) {
+	if format != FileFormatYAML {
+		return b, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return yamlNodeToJSON(&doc)
+}
+
+// MarshalFile encodes the value in the specified format.
+// The prevContent is the previous content of the same file, if any. For the YAML format, the comments in the previous content
+// are kept for the entries that still exist, so that a file can be regenerated without losing its review comments.
+func MarshalFile(format FileFormat, v interface{}, prevContent []byte) ([]byte, error) {
+	jsonContent, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if format != FileFormatYAML {
+		return jsonContent, nil
+	}
+
+	// JSON is a subset of YAML, so that the key order is kept by decoding the JSON content as YAML.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(jsonContent, &doc); err != nil {
+		return nil, err
+	}
+	comments := map[string]yaml.Node{}
+	if len(prevContent) != 0 {
+		var prevDoc yaml.Node
+		if err := yaml.Unmarshal(prevContent, &prevDoc); err == nil {
+			walkYAMLNode(&prevDoc, "", func(ptr string, node, key *yaml.Node) {
+				comments[ptr] = yamlComments(node, key)
+			})
+		}
+	}
+	walkYAMLNode(&doc, "", func(ptr string, node, key *yaml.Node) {
+		node.Style = 0
+		if key != nil {
+			key.Style = 0
+		}
+		if c, ok := comments[ptr]; ok {
+			setYAMLComments(node, key, c)
+		}
+	})
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pointerPositions returns the position of each value inside the document in the specified format, keyed by the JSON pointer of the value.
+func pointerPositions(format FileFormat, b []byte) (map[string]filePosition, error) {
+	if format != FileFormatYAML {
+		return jsonPointerPositions(b)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	positions := map[string]filePosition{}
+	walkYAMLNode(&doc, "", func(ptr string, node, _ *yaml.Node) {
+		positions[ptr] = filePosition{Line: node.Line, Column: node.Column}
+	})
+	return positions, nil
+}
+
+// walkYAMLNode walks each value node of the YAML document, together with its JSON pointer and its key node (if it is a mapping value).
+func walkYAMLNode(node *yaml.Node, ptr string, f func(ptr string, node, key *yaml.Node)) {
+	var walk func(node, key *yaml.Node, ptr string, depth int)
+	walk = func(node, key *yaml.Node, ptr string, depth int) {
+		if depth > maxYAMLNestingDepth {
+			return
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, n := range node.Content {
+				walk(n, nil, ptr, depth+1)
+			}
+			return
+		case yaml.AliasNode:
+			// The aliased node is walked at where it is anchored.
+			return
+		}
+		f(ptr, node, key)
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], node.Content[i], ptr+jsonPointer(node.Content[i].Value), depth+1)
+			}
+		case yaml.SequenceNode:
+			for i, n := range node.Content {
+				walk(n, nil, ptr+jsonPointer(strconv.Itoa(i)), depth+1)
+			}
+		}
+	}
+	walk(node, nil, ptr, 0)
+}
+
+// yamlComments returns a node that only holds the comments of the value node and its key node.
+func yamlComments(node, key *yaml.Node) yaml.Node {
+	c := yaml.Node{
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+	}
+	if key != nil {
+		c.HeadComment = key.HeadComment
+		c.LineComment = key.LineComment
+		c.FootComment = key.FootComment
+		// The line comment of a scalar mapping value is attached to the value node.
+		if node.LineComment != "" {
+			c.LineComment = node.LineComment
+		}
+	}
+	return c
+}
+
+func setYAMLComments(node, key *yaml.Node, c yaml.Node) {
+	if key == nil {
+		node.HeadComment, node.LineComment, node.FootComment = c.HeadComment, c.LineComment, c.FootComment
+		return
+	}
+	key.HeadComment, key.FootComment = c.HeadComment, c.FootComment
+	if node.Kind == yaml.ScalarNode {
+		node.LineComment = c.LineComment
+	} else {
+		key.LineComment = c.LineComment
+	}
+}
+
+// yamlNodeToJSON converts a YAML document to JSON, the key order is kept.
+func yamlNodeToJSON(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	var convert func(node *yaml.Node, depth int) error
+	convert = func(node *yaml.Node, depth int) error {
+		if depth > maxYAMLNestingDepth {
+			return fmt.Errorf("line %d: too many levels of nesting", node.Line)
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				buf.WriteString("null")
+				return nil
+			}
+			return convert(node.Content[0], depth+1)
+		case yaml.AliasNode:
+			return convert(node.Alias, depth+1)
+		case yaml.MappingNode:
+			buf.WriteByte('{')
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if i != 0 {
+					buf.WriteByte(',')
+				}
+				key, err := json.Marshal(node.Content[i].Value)
+				if err != nil {
+					return err
+				}
+				buf.Write(key)
+				buf.WriteByte(':')
+				if err := convert(node.Content[i+1], depth+1); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte('}')
+		case yaml.SequenceNode:
+			buf.WriteByte('[')
+			for i, n := range node.Content {
+				if i != 0 {
+					buf.WriteByte(',')
+				}
+				if err := convert(n, depth+1); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte(']')
+		default:
+			var v interface{}
+			if err := node.Decode(&v); err != nil {
+				return err
+			}
+			// Keep the timestamps (e.g. the review-by date of a grant) as they are written, rather than in RFC 3339.
+			if node.ShortTag() == "!!timestamp" {
+				v = node.Value
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", node.Line, err)
+			}
+			buf.Write(b)
+		}
+		return nil
+	}
+	if err := convert(node, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonPointerAt returns the JSON pointer of the innermost value that starts before the offset in the JSON document.
+func jsonPointerAt(b []byte, offset int64) string {
+	positions, err := jsonPointerPositions(b)
+	if err != nil {
+		return ""
+	}
+	target := offsetToPosition(b, int(offset))
+	var (
+		ptr  string
+		best filePosition
+	)
+	for p, pos := range positions {
+		if pos.Line > target.Line || (pos.Line == target.Line && pos.Column > target.Column) {
+			continue
+		}
+		if pos.Line > best.Line || (pos.Line == best.Line && pos.Column > best.Column) || (pos == best && len(p) > len(ptr)) {
+			ptr, best = p, pos
+		}
+	}
+	return ptr
+}
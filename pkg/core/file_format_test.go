@@ -0,0 +1,149 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+func TestUnmarshalFile_YAML(t *testing.T) {
+	input := []byte(`
+Name: res1
+swagger: foo.json
+PropertyLinks:
+  p1:
+    - prop: def_a:prop_primitive
+  p2:
+    - prop: def_a:prop_object.p1
+      transform: flatten
+  p3: []
+`)
+	var schema TFSchema
+	require.NoError(t, UnmarshalFile(FileFormatYAML, input, &schema))
+	require.Equal(t, TFSchema{
+		Name:        "res1",
+		SwaggerSpec: "foo.json",
+		PropertyLinks: TFSchemaPropertyLinks{
+			"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("def_a:prop_primitive")}},
+			"p2": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("def_a:prop_object.p1"), Transform: LinkTransformFlatten}},
+			"p3": {},
+		},
+	}, schema)
+
+	// The decoding error is located in the YAML content
+	input = []byte(`
+Name: res1
+PropertyLinks:
+  p1:
+    - prop: def_a:prop_primitive
+      note: 123
+`)
+	err := UnmarshalFile(FileFormatYAML, input, &schema)
+	require.Error(t, err)
+	diag := newFileDiagnostic("res1.yaml", input, err)
+	require.Equal(t, "/PropertyLinks/p1/0/note", diag.Pointer)
+	require.Equal(t, 6, diag.Line)
+	require.Equal(t, 13, diag.Column)
+
+	input = []byte("Name: res1\n  PropertyLinks: {}\n")
+	err = UnmarshalFile(FileFormatYAML, input, &schema)
+	require.Error(t, err)
+	diag = newFileDiagnostic("res1.yaml", input, err)
+	require.Equal(t, 2, diag.Line)
+
+	// The YAML content nested too deep is rejected.
+	input = []byte("Name: res1\nPropertyLinks: " + strings.Repeat("[", maxYAMLNestingDepth+1) + strings.Repeat("]", maxYAMLNestingDepth+1) + "\n")
+	err = UnmarshalFile(FileFormatYAML, input, &schema)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too many levels of nesting")
+}
+
+func TestMarshalFile_YAML(t *testing.T) {
+	schema := TFSchema{
+		Name:        "res1",
+		SwaggerSpec: "foo.json",
+		PropertyLinks: TFSchemaPropertyLinks{
+			"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("def_a:prop_primitive")}},
+			"p2": {},
+			"p3": {},
+		},
+	}
+
+	prev := []byte(`# The resource res1.
+Name: res1
+swagger: foo.json
+PropertyLinks:
+  # Linked as is.
+  p1:
+    - prop: def_a:prop_primitive # the primitive one
+  # To be removed.
+  p4: []
+`)
+	expect := `# The resource res1.
+Name: res1
+swagger: foo.json
+PropertyLinks:
+  # Linked as is.
+  p1:
+    - prop: def_a:prop_primitive # the primitive one
+  p2: []
+  p3: []
+`
+	b, err := MarshalFile(FileFormatYAML, schema, prev)
+	require.NoError(t, err)
+	require.Equal(t, expect, string(b))
+
+	var actual TFSchema
+	require.NoError(t, UnmarshalFile(FileFormatYAML, b, &actual))
+	require.Equal(t, schema, actual)
+}
+
+func TestNewSWGSchemasFromTerraformSchema_YAML(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger")
+	tfSchemaDir := filepath.Join(pwd, "testdata", "terraform_schema_yaml")
+	grantDir := filepath.Join(pwd, "testdata", "swagger_grants_yaml")
+
+	type position struct {
+		file   string
+		line   int
+		column int
+	}
+	expect := []position{
+		{filepath.Join(grantDir, "foo.yaml"), 5, 16},
+		{filepath.Join(grantDir, "foo.yaml"), 7, 7},
+		{filepath.Join(tfSchemaDir, "res1.yaml"), 8, 13},
+	}
+
+	swgschemas, err := NewSWGSchemasFromTerraformSchema(specBasePath, tfSchemaDir, grantDir, SWGGrantOptions{})
+	require.Error(t, err)
+	diags, ok := err.(Diagnostics)
+	require.True(t, ok)
+
+	actual := []position{}
+	for _, diag := range diags {
+		actual = append(actual, position{diag.File, diag.Line, diag.Column})
+	}
+	require.Equal(t, expect, actual)
+
+	schema := swgschemas.Get(NewSWGSchemaAddr("foo.json", "def_a"))
+	require.NotNil(t, schema)
+	require.Len(t, schema.Properties["prop_primitive"].TFLinks, 1)
+	require.True(t, schema.Properties["p2"].IsGranted)
+	require.Equal(t, "granted", schema.Properties["p2"].GrantComment)
+	require.True(t, schema.Properties["p3"].IsGranted)
+	require.Equal(t, GrantInfo{
+		Comment:  "granted with metadata",
+		Category: "unknown",
+		Owner:    "foo",
+		ReviewBy: "2020-01-01",
+	}, schema.Properties["p3"].GrantInfo())
+}
@@ -0,0 +1,372 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect of the generated JSON Schemas.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+const jsonSchemaDefinitionPrefix = "#/definitions/"
+
+// JSONSchema is a JSON Schema (draft-07), which only supports the keywords needed to describe the knowledge base file formats.
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// One of "object", "array", "string", "integer", "number" and "boolean", empty for any type.
+	Type  string        `json:"type,omitempty"`
+	Const interface{}   `json:"const,omitempty"`
+	Enum  []interface{} `json:"enum,omitempty"`
+
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+
+	// Either a bool, or a *JSONSchema that the properties not listed in the Properties must conform to.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+
+	Items *JSONSchema   `json:"items,omitempty"`
+	OneOf []*JSONSchema `json:"oneOf,omitempty"`
+
+	Definitions map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+// jsonSchemaGenerator generates the JSON Schema from the Go types, following how they are (un)marshalled by encoding/json.
+// The named struct types are generated as definitions, which are referred to via "$ref".
+type jsonSchemaGenerator struct {
+	definitions map[string]*JSONSchema
+}
+
+// jsonSchemaOverrides generates the JSON Schema of the types that are (un)marshalled in a customized way, or that are
+// constrained further than their Go types.
+var jsonSchemaOverrides map[reflect.Type]func(g *jsonSchemaGenerator) *JSONSchema
+
+func init() {
+	// Assigned in init() as the overrides refer to the generator, which looks up the overrides.
+	jsonSchemaOverrides = map[reflect.Type]func(g *jsonSchemaGenerator) *JSONSchema{
+		reflect.TypeOf(propertyaddr.SwaggerPropertyAddr{}): func(*jsonSchemaGenerator) *JSONSchema {
+			return &JSONSchema{Type: "string", Description: `The swagger property address (e.g. "VirtualNetwork:properties.addressSpace").`}
+		},
+		reflect.TypeOf(propertyaddr.TerraformPropertyAddr{}): func(*jsonSchemaGenerator) *JSONSchema {
+			return &JSONSchema{Type: "string", Description: `The terraform property address (e.g. "azurerm_virtual_network:address_space").`}
+		},
+		// The values of the enums are not constrained here, but by the validation of the decoded value, which reports the
+		// invalid value without failing the file as a whole.
+		reflect.TypeOf(GrantCategory("")): func(*jsonSchemaGenerator) *JSONSchema {
+			var values []string
+			for _, category := range grantCategories {
+				values = append(values, string(category))
+			}
+			return &JSONSchema{Type: "string", Description: "One of: " + strings.Join(values, ", ") + "."}
+		},
+		reflect.TypeOf(LinkTransform("")): func(*jsonSchemaGenerator) *JSONSchema {
+			var values []string
+			for _, transform := range linkTransforms {
+				values = append(values, string(transform))
+			}
+			return &JSONSchema{Type: "string", Description: "One of: " + strings.Join(values, ", ") + "."}
+		},
+		reflect.TypeOf(SwaggerLink{}): func(g *jsonSchemaGenerator) *JSONSchema {
+			return g.define("SwaggerLink", func() *JSONSchema {
+				return &JSONSchema{
+					Description: "Either a link to the swagger property, or the marker of a terraform property that has no counterpart in swagger.",
+					OneOf: []*JSONSchema{
+						{
+							Type: "object",
+							Properties: map[string]*JSONSchema{
+								"swagger":   {Type: "string"},
+								"prop":      g.schemaOf(reflect.TypeOf(propertyaddr.SwaggerPropertyAddr{})),
+								"transform": g.schemaOf(reflect.TypeOf(LinkTransform(""))),
+								"note":      {Type: "string"},
+							},
+							Required:             []string{"prop"},
+							AdditionalProperties: false,
+						},
+						{
+							Type: "object",
+							Properties: map[string]*JSONSchema{
+								"not_applicable": {Type: "string"},
+							},
+							Required:             []string{"not_applicable"},
+							AdditionalProperties: false,
+						},
+					},
+				}
+			})
+		},
+		reflect.TypeOf(SWGPropertyGrant{}): func(g *jsonSchemaGenerator) *JSONSchema {
+			return g.define("SWGPropertyGrant", func() *JSONSchema {
+				return &JSONSchema{
+					Description: "Either the grant comment, or the grant metadata.",
+					OneOf: []*JSONSchema{
+						{Type: "string"},
+						g.schemaOf(reflect.TypeOf(GrantInfo{})),
+					},
+				}
+			})
+		},
+		reflect.TypeOf(TFLinks{}): func(g *jsonSchemaGenerator) *JSONSchema {
+			return &JSONSchema{
+				Type: "array",
+				Items: &JSONSchema{
+					Description: "Either the terraform property address, or an object of the address and the annotations.",
+					OneOf: []*JSONSchema{
+						g.schemaOf(reflect.TypeOf(propertyaddr.TerraformPropertyAddr{})),
+						g.schemaOf(reflect.TypeOf(tfLinkAnnotated{})),
+					},
+				},
+			}
+		},
+	}
+}
+
+// define generates the named definition (only once), and returns the reference to it.
+func (g *jsonSchemaGenerator) define(name string, f func() *JSONSchema) *JSONSchema {
+	if _, ok := g.definitions[name]; !ok {
+		// Register the name beforehand, in case the definition refers to itself.
+		g.definitions[name] = nil
+		g.definitions[name] = f()
+	}
+	return &JSONSchema{Ref: jsonSchemaDefinitionPrefix + name}
+}
+
+func (g *jsonSchemaGenerator) schemaOf(t reflect.Type) *JSONSchema {
+	if f, ok := jsonSchemaOverrides[t]; ok {
+		return f(g)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaOf(t.Elem())
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.define(t.Name(), func() *JSONSchema {
+			return g.structSchema(t)
+		})
+	}
+	panic(fmt.Sprintf("generating JSON Schema for unsupported type %s", t))
+}
+
+// structSchema generates the JSON Schema of the struct, whose fields without the "omitempty" option are required. The
+// fields of the embedded structs are flattened, as is done by encoding/json.
+func (g *jsonSchemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	s := &JSONSchema{
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: false,
+	}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts := tag, ""
+			if idx := strings.Index(tag, ","); idx != -1 {
+				name, opts = tag[:idx], tag[idx+1:]
+			}
+			if field.Anonymous && name == "" {
+				ft := field.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					addFields(ft)
+					continue
+				}
+			}
+			if field.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			s.Properties[name] = g.schemaOf(field.Type)
+			if !strings.Contains(","+opts+",", ",omitempty,") {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	addFields(t)
+	return s
+}
+
+// Validate validates the JSON decoded document (i.e. via decoding into an interface{}) against the JSON Schema. The returned
+// Diagnostics records every problem found, each pointing to the offending value.
+func (s *JSONSchema) Validate(doc interface{}) Diagnostics {
+	return s.validate(s, doc, "")
+}
+
+func (s *JSONSchema) validate(root *JSONSchema, v interface{}, ptr string) Diagnostics {
+	if s.Ref != "" {
+		def, ok := root.Definitions[strings.TrimPrefix(s.Ref, jsonSchemaDefinitionPrefix)]
+		if !ok {
+			return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("unresolvable JSON Schema reference %q", s.Ref)}}
+		}
+		return def.validate(root, v, ptr)
+	}
+
+	if len(s.OneOf) != 0 {
+		var (
+			matched    int
+			candidates []Diagnostics
+			types      []string
+		)
+		for _, branch := range s.OneOf {
+			diags := branch.validate(root, v, ptr)
+			if len(diags) == 0 {
+				matched++
+				continue
+			}
+			typ := branch.resolve(root).Type
+			types = append(types, typ)
+			if typ == "" || jsonTypeMatches(typ, v) {
+				candidates = append(candidates, diags)
+			}
+		}
+		switch {
+		case matched == 1:
+			return nil
+		case matched > 1:
+			return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("matches more than one of the expected forms")}}
+		case len(candidates) == 0:
+			return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("expected one of the types: %s, got %s", strings.Join(types, ", "), jsonTypeOf(v))}}
+		}
+		// Report the problems of the closest form of the same type as the value, which are more specific.
+		sort.SliceStable(candidates, func(i, j int) bool { return len(candidates[i]) < len(candidates[j]) })
+		if len(candidates) == 1 || len(candidates[0]) < len(candidates[1]) {
+			return candidates[0]
+		}
+		return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("doesn't match any of the expected forms")}}
+	}
+
+	if s.Type != "" && !jsonTypeMatches(s.Type, v) {
+		return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("expected %s, got %s", s.Type, jsonTypeOf(v))}}
+	}
+	if s.Const != nil && !jsonEqual(s.Const, v) {
+		return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("expected %v, got %v", s.Const, v)}}
+	}
+	if len(s.Enum) != 0 {
+		var values []string
+		found := false
+		for _, e := range s.Enum {
+			values = append(values, fmt.Sprintf("%v", e))
+			if jsonEqual(e, v) {
+				found = true
+			}
+		}
+		if !found {
+			return Diagnostics{{Pointer: ptr, Err: fmt.Errorf("%v is not one of: %s", v, strings.Join(values, ", "))}}
+		}
+	}
+
+	var diags Diagnostics
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				diags = append(diags, Diagnostic{Pointer: ptr, Err: fmt.Errorf("missing required property %q", name)})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if ps, ok := s.Properties[k]; ok {
+				diags = append(diags, ps.validate(root, v[k], ptr+jsonPointer(k))...)
+				continue
+			}
+			switch ap := s.AdditionalProperties.(type) {
+			case bool:
+				if !ap {
+					diags = append(diags, Diagnostic{Pointer: ptr + jsonPointer(k), Err: fmt.Errorf("unknown property %q", k)})
+				}
+			case *JSONSchema:
+				diags = append(diags, ap.validate(root, v[k], ptr+jsonPointer(k))...)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				diags = append(diags, s.Items.validate(root, item, ptr+jsonPointer(fmt.Sprint(i)))...)
+			}
+		}
+	}
+	return diags
+}
+
+func (s *JSONSchema) resolve(root *JSONSchema) *JSONSchema {
+	if s.Ref == "" {
+		return s
+	}
+	if def, ok := root.Definitions[strings.TrimPrefix(s.Ref, jsonSchemaDefinitionPrefix)]; ok {
+		return def
+	}
+	return s
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func jsonTypeMatches(typ string, v interface{}) bool {
+	actual := jsonTypeOf(v)
+	return actual == typ || (typ == "number" && actual == "integer")
+}
+
+// jsonEqual tells whether the two values are equal once they are marshalled into JSON.
+func jsonEqual(a, b interface{}) bool {
+	ba, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ba) == string(bb)
+}
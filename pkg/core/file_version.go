@@ -0,0 +1,350 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileKind is the kind of a knowledge base file, whose format is versioned on its own.
+type FileKind string
+
+const (
+	// The TFSchema file (e.g. "azure_knowledgebase/terraform_schema/azurerm_virtual_network.json").
+	FileKindTFSchema FileKind = "terraform_schema"
+	// The schema grant file, which is named after the swagger spec it grants.
+	FileKindSWGGrant FileKind = "swagger_grant"
+	// The pattern grant file (i.e. "_patterns.json" or "_patterns.yaml").
+	FileKindSWGPatternGrant FileKind = "swagger_pattern_grant"
+	// The directory grant file (i.e. "_grant.json" or "_grant.yaml").
+	FileKindSWGDirectoryGrant FileKind = "swagger_directory_grant"
+	// The swagger schema report (e.g. the "swagger_schema.json" generated by cmd/swagger_schema).
+	FileKindSWGSchemaReport FileKind = "swagger_schema"
+)
+
+// FileKinds are all the versioned kinds of knowledge base files.
+var FileKinds = []FileKind{
+	FileKindTFSchema,
+	FileKindSWGGrant,
+	FileKindSWGPatternGrant,
+	FileKindSWGDirectoryGrant,
+	FileKindSWGSchemaReport,
+}
+
+// FormatVersionKey is the top level key of a knowledge base file that records the version of its format. A file without it
+// is regarded as of version 0, i.e. before the format is versioned. The key is reserved, e.g. it can't be a swagger schema
+// name in a schema grant file.
+const FormatVersionKey = "FormatVersion"
+
+// FormatMigration migrates the (JSON decoded) document of a knowledge base file from a format version to the next one.
+// The FormatVersion of the document is maintained by the caller, so the Migrate only needs to migrate the content.
+type FormatMigration struct {
+	Description string
+	Migrate     func(doc map[string]interface{}) error
+}
+
+// fileKindInfo describes the format of a kind of knowledge base file.
+type fileKindInfo struct {
+	title string
+
+	// The Go type that the file is decoded into.
+	goType reflect.Type
+
+	// The migrations in order, where the i-th one migrates the document from version i to i+1. Therefore, the current
+	// format version is the amount of the migrations. A format change appends a migration here, together with the
+	// change of the goType.
+	migrations []FormatMigration
+}
+
+// migrationIntroduceFormatVersion is the first migration of every kind, which only introduces the FormatVersion.
+var migrationIntroduceFormatVersion = FormatMigration{
+	Description: "Introduce the FormatVersion",
+	Migrate:     func(map[string]interface{}) error { return nil },
+}
+
+var fileKindInfos = map[FileKind]fileKindInfo{
+	FileKindTFSchema: {
+		title:      "Terraform schema file",
+		goType:     reflect.TypeOf(TFSchema{}),
+		migrations: []FormatMigration{migrationIntroduceFormatVersion},
+	},
+	FileKindSWGGrant: {
+		title:      "Swagger schema grant file",
+		goType:     reflect.TypeOf(map[string]SWGSchemaGrant{}),
+		migrations: []FormatMigration{migrationIntroduceFormatVersion},
+	},
+	FileKindSWGPatternGrant: {
+		title:      "Swagger pattern grant file",
+		goType:     reflect.TypeOf(map[string]SWGPropertyGrant{}),
+		migrations: []FormatMigration{migrationIntroduceFormatVersion},
+	},
+	FileKindSWGDirectoryGrant: {
+		title:      "Swagger directory grant file",
+		goType:     reflect.TypeOf(GrantInfo{}),
+		migrations: []FormatMigration{migrationIntroduceFormatVersion},
+	},
+	FileKindSWGSchemaReport: {
+		title:      "Swagger schema report",
+		goType:     reflect.TypeOf(SWGSchemaReport{}),
+		migrations: []FormatMigration{migrationIntroduceFormatVersion},
+	},
+}
+
+func fileKindInfoOf(kind FileKind) fileKindInfo {
+	info, ok := fileKindInfos[kind]
+	if !ok {
+		panic(fmt.Sprintf("unknown file kind %q", kind))
+	}
+	return info
+}
+
+// CurrentFormatVersion returns the current format version of the kind of knowledge base file.
+func CurrentFormatVersion(kind FileKind) int {
+	return len(fileKindInfoOf(kind).migrations)
+}
+
+// SWGGrantFileKind tells the kind of a file in the swagger grant directory by its name.
+func SWGGrantFileKind(path string) FileKind {
+	switch {
+	case isDirectoryGrantFile(path):
+		return FileKindSWGDirectoryGrant
+	case isPatternGrantFile(path):
+		return FileKindSWGPatternGrant
+	default:
+		return FileKindSWGGrant
+	}
+}
+
+// fileJSONSchemaCache caches the JSON Schema of each kind of knowledge base file, as it is generated via reflection while every
+// file being decoded is validated against it.
+var fileJSONSchemaCache = struct {
+	sync.Mutex
+	m map[FileKind]*JSONSchema
+}{
+	m: map[FileKind]*JSONSchema{},
+}
+
+// FileJSONSchema returns the JSON Schema of the current format of the kind of knowledge base file, which is generated from
+// the Go type that the file is decoded into. The returned JSON Schema is shared, which must not be modified.
+func FileJSONSchema(kind FileKind) *JSONSchema {
+	fileJSONSchemaCache.Lock()
+	defer fileJSONSchemaCache.Unlock()
+	if schema, ok := fileJSONSchemaCache.m[kind]; ok {
+		return schema
+	}
+	schema := newFileJSONSchema(kind)
+	fileJSONSchemaCache.m[kind] = schema
+	return schema
+}
+
+func newFileJSONSchema(kind FileKind) *JSONSchema {
+	info := fileKindInfoOf(kind)
+	g := &jsonSchemaGenerator{definitions: map[string]*JSONSchema{}}
+
+	var root *JSONSchema
+	if info.goType.Kind() == reflect.Struct {
+		// Inline the root struct, rather than referring to its definition.
+		root = g.structSchema(info.goType)
+	} else {
+		root = g.schemaOf(info.goType)
+	}
+	if root.Properties == nil {
+		root.Properties = map[string]*JSONSchema{}
+	}
+	version := CurrentFormatVersion(kind)
+	root.Properties[FormatVersionKey] = &JSONSchema{
+		Type:        "integer",
+		Const:       version,
+		Description: "The version of the file format.",
+	}
+	root.Required = append([]string{FormatVersionKey}, root.Required...)
+
+	root.Schema = JSONSchemaDraft
+	root.Title = fmt.Sprintf("%s (format version %d)", info.title, version)
+	if len(g.definitions) != 0 {
+		root.Definitions = g.definitions
+	}
+	return root
+}
+
+// DecodeFile decodes the content of the kind of knowledge base file in the specified format. The content is migrated to the
+// current format version in memory first, then it is validated against the JSON Schema of the format.
+// The returned error is either a Diagnostic (e.g. for an unsupported format version) or a Diagnostics (for the validation
+// problems) pointing to the offending entries, or a decoding error of the content.
+func DecodeFile(kind FileKind, format FileFormat, b []byte, v interface{}) error {
+	doc, _, err := decodeVersionedDoc(kind, format, b)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		if err, ok := err.(*json.UnmarshalTypeError); ok {
+			return Diagnostic{Pointer: jsonPointerAt(content, err.Offset), Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// EncodeFile encodes the value as the kind of knowledge base file in the specified format, with the current FormatVersion
+// recorded. The prevContent is the same as the one of MarshalFile.
+func EncodeFile(kind FileKind, format FileFormat, v interface{}, prevContent []byte) ([]byte, error) {
+	return MarshalFile(format, versionedContent{version: CurrentFormatVersion(kind), content: v}, prevContent)
+}
+
+// MigrateFile migrates the content of the kind of knowledge base file to the current format version, together with the
+// format version it is migrated from. The content is re-encoded even if it is already of the current format version, in
+// which case it is only normalized. The key order of the objects is kept as in the original content, with the FormatVersion
+// inserted as the first key. The returned error is the same as the one of DecodeFile.
+func MigrateFile(kind FileKind, format FileFormat, b []byte) ([]byte, int, error) {
+	_, version, err := decodeVersionedDoc(kind, format, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	v := reflect.New(fileKindInfoOf(kind).goType)
+	if err := DecodeFile(kind, format, b, v.Interface()); err != nil {
+		return nil, 0, err
+	}
+	content, err := json.Marshal(versionedContent{version: CurrentFormatVersion(kind), content: v.Elem().Interface()})
+	if err != nil {
+		return nil, 0, err
+	}
+	content, err = keepKeyOrder(content, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := MarshalFile(format, json.RawMessage(content), b)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, version, nil
+}
+
+// keepKeyOrder reorders the keys of each object of the JSON content, as the same object in the previous content (either in
+// JSON or YAML). The keys absent from the previous content keep their positions, while the others take the rest positions
+// in the previous order.
+func keepKeyOrder(content, prevContent []byte) ([]byte, error) {
+	var prevDoc yaml.Node
+	if err := yaml.Unmarshal(prevContent, &prevDoc); err != nil {
+		return nil, err
+	}
+	prevOrders := map[string]map[string]int{}
+	walkYAMLNode(&prevDoc, "", func(ptr string, node, _ *yaml.Node) {
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		order := map[string]int{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			order[node.Content[i].Value] = i / 2
+		}
+		prevOrders[ptr] = order
+	})
+
+	// JSON is a subset of YAML, so that the key order is kept by decoding the JSON content as YAML.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	walkYAMLNode(&doc, "", func(ptr string, node, _ *yaml.Node) {
+		order, ok := prevOrders[ptr]
+		if !ok || node.Kind != yaml.MappingNode {
+			return
+		}
+		var positions []int
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if _, ok := order[node.Content[i].Value]; ok {
+				positions = append(positions, i)
+			}
+		}
+		pairs := make([]int, len(positions))
+		copy(pairs, positions)
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return order[node.Content[pairs[i]].Value] < order[node.Content[pairs[j]].Value]
+		})
+		nodes := make([]*yaml.Node, len(node.Content))
+		copy(nodes, node.Content)
+		for i, pos := range positions {
+			node.Content[pos], node.Content[pos+1] = nodes[pairs[i]], nodes[pairs[i]+1]
+		}
+	})
+	return yamlNodeToJSON(&doc)
+}
+
+// decodeVersionedDoc decodes the content into a generic document, which is migrated to the current format version, then
+// validated. The FormatVersion is removed from the returned document, together with the version it is migrated from.
+func decodeVersionedDoc(kind FileKind, format FileFormat, b []byte) (interface{}, int, error) {
+	content, err := fileContentToJSON(format, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, 0, err
+	}
+
+	info := fileKindInfoOf(kind)
+	current := len(info.migrations)
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		// Leave it to the validation to report.
+		return doc, 0, FileJSONSchema(kind).Validate(doc).Err()
+	}
+
+	version := 0
+	if rawVersion, ok := obj[FormatVersionKey]; ok {
+		f, ok := rawVersion.(float64)
+		if !ok || f != math.Trunc(f) || f < 0 {
+			return nil, 0, Diagnostic{Pointer: jsonPointer(FormatVersionKey), Err: fmt.Errorf("format version must be a non-negative integer, got %v", rawVersion)}
+		}
+		version = int(f)
+	}
+	if version > current {
+		return nil, 0, Diagnostic{Pointer: jsonPointer(FormatVersionKey), Err: fmt.Errorf("format version %d is newer than the supported version %d", version, current)}
+	}
+	for i := version; i < current; i++ {
+		if err := info.migrations[i].Migrate(obj); err != nil {
+			return nil, 0, fmt.Errorf("migrating from format version %d to %d (%s): %v", i, i+1, info.migrations[i].Description, err)
+		}
+	}
+	obj[FormatVersionKey] = float64(current)
+
+	if err := FileJSONSchema(kind).Validate(obj).Err(); err != nil {
+		// The pointers are still valid against the original content, as the migrations so far keep the structure.
+		return nil, 0, err
+	}
+	delete(obj, FormatVersionKey)
+	return obj, version, nil
+}
+
+// versionedContent marshals the content as a JSON object, whose first key is the FormatVersion.
+type versionedContent struct {
+	version int
+	content interface{}
+}
+
+func (c versionedContent) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(c.content)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	if len(b) < 2 || b[0] != '{' {
+		return nil, fmt.Errorf("expected the content to be encoded as a JSON object, got %s", b)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{%q:%d", FormatVersionKey, c.version)
+	if rest := bytes.TrimSpace(b[1:]); len(rest) != 0 && rest[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(b[1:])
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeFile(t *testing.T) {
+	expect := TFSchema{
+		Name:        "res1",
+		SwaggerSpec: "foo.json",
+		PropertyLinks: TFSchemaPropertyLinks{
+			"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("def_a:prop_primitive")}},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		format  FileFormat
+		content string
+	}{
+		{
+			name:    "legacy",
+			format:  FileFormatJSON,
+			content: `{"Name": "res1", "swagger": "foo.json", "PropertyLinks": {"p1": [{"prop": "def_a:prop_primitive"}]}}`,
+		},
+		{
+			name:    "current",
+			format:  FileFormatJSON,
+			content: `{"FormatVersion": 1, "Name": "res1", "swagger": "foo.json", "PropertyLinks": {"p1": [{"prop": "def_a:prop_primitive"}]}}`,
+		},
+		{
+			name:   "yaml",
+			format: FileFormatYAML,
+			content: `FormatVersion: 1
+Name: res1
+swagger: foo.json
+PropertyLinks:
+  p1:
+    - prop: def_a:prop_primitive
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var actual TFSchema
+			require.NoError(t, DecodeFile(FileKindTFSchema, c.format, []byte(c.content), &actual))
+			require.Equal(t, expect, actual)
+		})
+	}
+}
+
+func TestDecodeFile_Invalid(t *testing.T) {
+	content := []byte(`FormatVersion: 1
+Name: res1
+swagger: foo.json
+PropertyLinks:
+  p1:
+    - prop: def_a:prop_primitive
+      transfrom: flatten
+  p2: foo
+`)
+	err := DecodeFile(FileKindTFSchema, FileFormatYAML, content, &TFSchema{})
+	diags, ok := err.(Diagnostics)
+	require.True(t, ok)
+	diags = diags.WithFile("res1.yaml", content)
+	require.Len(t, diags, 2)
+	require.Equal(t, "/PropertyLinks/p1/0/transfrom", diags[0].Pointer)
+	require.Equal(t, 7, diags[0].Line)
+	require.Contains(t, diags[0].Error(), `unknown property "transfrom"`)
+	require.Equal(t, "/PropertyLinks/p2", diags[1].Pointer)
+	require.Contains(t, diags[1].Error(), "expected array, got string")
+
+	err = DecodeFile(FileKindSWGPatternGrant, FileFormatJSON, []byte(`{"FormatVersion": 2, "etag": "not needed"}`), &map[string]SWGPropertyGrant{})
+	diag, ok := err.(Diagnostic)
+	require.True(t, ok)
+	require.Equal(t, "/FormatVersion", diag.Pointer)
+	require.Contains(t, diag.Error(), "newer than the supported version 1")
+}
+
+func TestMigrateFile(t *testing.T) {
+	content := []byte(`# The directory grant.
+Comment: the classic service is deprecated # why
+Category: deprecated
+`)
+	out, version, err := MigrateFile(FileKindSWGDirectoryGrant, FileFormatYAML, content)
+	require.NoError(t, err)
+	require.Equal(t, 0, version)
+	require.Equal(t, `FormatVersion: 1
+# The directory grant.
+Comment: the classic service is deprecated # why
+Category: deprecated
+`, string(out))
+
+	_, version, err = MigrateFile(FileKindSWGDirectoryGrant, FileFormatYAML, out)
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+}
+
+func TestMigrateFile_KeyOrder(t *testing.T) {
+	content := []byte(`{
+  "type": {
+    "Comment": "implied",
+    "Category": "read_only_noise"
+  },
+  "etag": {
+    "Category": "read_only_noise",
+    "Comment": "not needed"
+  }
+}`)
+	out, version, err := MigrateFile(FileKindSWGPatternGrant, FileFormatJSON, content)
+	require.NoError(t, err)
+	require.Equal(t, 0, version)
+	require.Equal(t, `{
+  "FormatVersion": 1,
+  "type": {
+    "Comment": "implied",
+    "Category": "read_only_noise"
+  },
+  "etag": {
+    "Category": "read_only_noise",
+    "Comment": "not needed"
+  }
+}`, string(out))
+
+	content = []byte(`Subnet:
+  Comment: not a candidate
+  Category: by_design
+VirtualNetwork:
+  Properties:
+    properties.b: granted
+    properties.a: granted
+`)
+	out, _, err = MigrateFile(FileKindSWGGrant, FileFormatYAML, content)
+	require.NoError(t, err)
+	require.Equal(t, `FormatVersion: 1
+Subnet:
+  Comment: not a candidate
+  Category: by_design
+VirtualNetwork:
+  Properties:
+    properties.b: granted
+    properties.a: granted
+`, string(out))
+}
+
+func TestFileJSONSchema_UpToDate(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range FileKinds {
+		b, err := json.MarshalIndent(FileJSONSchema(kind), "", "  ")
+		require.NoError(t, err)
+		published, err := ioutil.ReadFile(filepath.Join(pwd, "..", "..", "doc", "schema", string(kind)+".schema.json"))
+		require.NoError(t, err)
+		require.Equal(t, strings.TrimSpace(string(published)), string(b), "the JSON Schema of %s is out of date, regenerate it via cmd/file_schema", kind)
+	}
+}
+
+func TestKnowledgeBaseFormat(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kbDir := filepath.Join(pwd, "..", "..", "azure_knowledgebase")
+
+	files, err := LoadTFSchemaFiles(filepath.Join(kbDir, KnowledgeBaseTerraformSchemaDir))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, f := range files {
+		_, version, err := MigrateFile(FileKindTFSchema, f.Format, f.Content)
+		require.NoError(t, err)
+		require.Equal(t, CurrentFormatVersion(FileKindTFSchema), version, "%s is not migrated", f.Path)
+	}
+
+	_, err = NewSWGGrantFromFiles(filepath.Join(kbDir, KnowledgeBaseSwaggerGrantDir))
+	require.NoError(t, err)
+}
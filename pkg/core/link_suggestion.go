@@ -0,0 +1,303 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// swaggerPropertyEnvelope is the name of the property that Azure resources use to wrap the resource specific properties,
+// which has no counterpart in Terraform.
+const swaggerPropertyEnvelope = "properties"
+
+// wordAbbreviations maps the abbreviations (or other variants) of a word commonly used in either Terraform or Swagger to its canonical form.
+var wordAbbreviations = map[string]string{
+	"addr":     "address",
+	"auth":     "authentication",
+	"cert":     "certificate",
+	"certs":    "certificate",
+	"config":   "configuration",
+	"configs":  "configuration",
+	"desc":     "description",
+	"disabled": "disable",
+	"enabled":  "enable",
+	"env":      "environment",
+	"ips":      "ip",
+	"max":      "maximum",
+	"min":      "minimum",
+	"num":      "number",
+	"pwd":      "password",
+}
+
+// LinkSuggestion is a candidate swagger property to be linked by a terraform property.
+type LinkSuggestion struct {
+	TFProp  string                           `json:"tf_prop"`
+	SWGProp propertyaddr.SwaggerPropertyAddr `json:"prop"`
+
+	// The confidence of the suggestion, which ranges in (0, 1].
+	Score float64 `json:"score"`
+
+	// Where the suggestion is inferred from, which is empty for the suggestions made by matching the property addresses.
+	Origin string `json:"origin,omitempty"`
+}
+
+type LinkSuggestOptions struct {
+	// The minimum score of a suggestion to be proposed.
+	MinScore float64
+
+	// The maximum amount of suggestions proposed for each terraform property, 0 means no limit.
+	MaxCandidates int
+
+	// The maximum depth of the swagger properties to be considered.
+	MaxDepth int
+
+	// The block of the terraform resource from the provider schema, which is used to check the type compatibility. Optional.
+	Block       *TerraformBlock
+	Conversions []TypeConversion
+}
+
+// SuggestLinks proposes the candidate swagger properties of the root swagger schema for each terraform property
+// that has no link yet (and is not marked as not applicable), by matching their addresses (e.g. "ip_configuration.subnet_id" and
+// "properties.ipConfigurations.properties.subnet.id") and their types.
+// The SWGSchema will be expanded during the process.
+func SuggestLinks(tfschema TFSchema, swgschema *SWGSchema, opts LinkSuggestOptions) ([]LinkSuggestion, error) {
+	if err := swgschema.ExpandAll(opts.MaxDepth); err != nil {
+		return nil, fmt.Errorf("expanding swagger schema %s: %w", swgschema.Name, err)
+	}
+
+	swgProps := make([]string, 0, len(swgschema.Properties))
+	for raddr := range swgschema.Properties {
+		if raddr == "" {
+			continue
+		}
+		swgProps = append(swgProps, raddr)
+	}
+	sort.Strings(swgProps)
+
+	var suggestions []LinkSuggestion
+	for _, tfProp := range tfschema.PropertyLinks.sortedKeys() {
+		if tfschema.PropertyLinks.Status(tfProp) != TFPropertyUnmapped {
+			continue
+		}
+		tfPropAddr := propertyaddr.NewTerraformPropertyAddr(tfschema.Name, tfProp)
+
+		var tfType *TFPropertyType
+		if opts.Block != nil {
+			tfType, _ = opts.Block.FindPropertyType(tfPropAddr.PropertyAddr)
+		}
+
+		var candidates []LinkSuggestion
+		for _, swgProp := range swgProps {
+			swgPropAddr := propertyaddr.MustNewSwaggerPropertyAddr(swgschema.Name, swgProp)
+			score := addrSimilarity(tfPropAddr.PropertyAddr, swgPropAddr.PropertyAddr)
+			if score == 0 {
+				continue
+			}
+			if tfType != nil {
+				if swgType, err := swgschema.FindPropertyType(swgPropAddr); err == nil && !IsTypeCompatible(*tfType, *swgType, opts.Conversions) {
+					score /= 2
+				}
+			}
+			if score < opts.MinScore {
+				continue
+			}
+			candidates = append(candidates, LinkSuggestion{
+				TFProp:  tfProp,
+				SWGProp: swgPropAddr,
+				Score:   score,
+			})
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Score > candidates[j].Score
+		})
+		if opts.MaxCandidates != 0 && len(candidates) > opts.MaxCandidates {
+			candidates = candidates[:opts.MaxCandidates]
+		}
+		suggestions = append(suggestions, candidates...)
+	}
+	return suggestions, nil
+}
+
+// GuessRootSchema guesses the root swagger schema of the TFSchema, which is the schema linked most by the properties
+// that reside in the swagger spec of the TFSchema.
+func (schema TFSchema) GuessRootSchema() (string, bool) {
+	count := map[string]int{}
+	for _, links := range schema.PropertyLinks {
+		for _, link := range links {
+			if link.IsNotApplicable() || (link.Spec != nil && *link.Spec != schema.SwaggerSpec) {
+				continue
+			}
+			count[link.SchemaProp.Schema]++
+		}
+	}
+	var root string
+	for name, n := range count {
+		if n > count[root] || (n == count[root] && name < root) {
+			root = name
+		}
+	}
+	return root, root != ""
+}
+
+// ApplyLinkSuggestions returns a draft of the TFSchema, where each terraform property without link is linked to the
+// swagger property of its best suggestion. The suggested links are annotated with a note, to be confirmed by a human.
+func (schema TFSchema) ApplyLinkSuggestions(suggestions []LinkSuggestion) *TFSchema {
+	draft := &TFSchema{
+		Name:          schema.Name,
+		SwaggerSpec:   schema.SwaggerSpec,
+		PropertyLinks: TFSchemaPropertyLinks{},
+	}
+	for k, v := range schema.PropertyLinks {
+		draft.PropertyLinks[k] = v
+	}
+
+	best := map[string]LinkSuggestion{}
+	for _, suggestion := range suggestions {
+		if b, ok := best[suggestion.TFProp]; ok && b.Score >= suggestion.Score {
+			continue
+		}
+		best[suggestion.TFProp] = suggestion
+	}
+	for tfProp, suggestion := range best {
+		if draft.PropertyLinks.Status(tfProp) != TFPropertyUnmapped {
+			continue
+		}
+		note := fmt.Sprintf("suggested (score: %.2f), to be confirmed", suggestion.Score)
+		if suggestion.Origin != "" {
+			note = fmt.Sprintf("inferred from %s, to be confirmed", suggestion.Origin)
+		}
+		draft.PropertyLinks[tfProp] = []SwaggerLink{
+			{
+				SchemaProp: suggestion.SWGProp,
+				Note:       note,
+			},
+		}
+	}
+	return draft
+}
+
+// addrSimilarity scores the similarity between a terraform property address and a swagger property address, based on
+// the normalized words of both addresses. The last segment is weighted more than the whole address.
+func addrSimilarity(tfAddr propertyaddr.TerraformRelativeAddrs, swgAddr propertyaddr.SwaggerRelPropertyAddr) float64 {
+	var tfSegments, swgSegments [][]string
+	for _, segment := range tfAddr {
+		tfSegments = append(tfSegments, normalizeWords(splitWords(segment)))
+	}
+	for _, segment := range swgAddr {
+		name := segment.Name()
+		if name == swaggerPropertyEnvelope || name == "" {
+			continue
+		}
+		swgSegments = append(swgSegments, normalizeWords(splitWords(name)))
+	}
+	if len(tfSegments) == 0 || len(swgSegments) == 0 {
+		return 0
+	}
+
+	tfWords, swgWords := flattenWords(tfSegments), flattenWords(swgSegments)
+	if strings.Join(tfWords, " ") == strings.Join(swgWords, " ") {
+		return 1
+	}
+	leafScore := diceCoefficient(tfSegments[len(tfSegments)-1], swgSegments[len(swgSegments)-1])
+	if leafScore == 0 {
+		return 0
+	}
+	return 0.7*leafScore + 0.3*diceCoefficient(tfWords, swgWords)
+}
+
+// splitWords splits a snake_case or camelCase name into lower case words (e.g. "DNSServers" into "dns" and "servers").
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(name)
+	flush := func() {
+		if len(word) != 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// normalizeWords turns each word into its canonical form, by expanding the abbreviations and singularizing the plurals.
+func normalizeWords(words []string) []string {
+	out := make([]string, 0, len(words))
+	for _, word := range words {
+		if canonical, ok := wordAbbreviations[word]; ok {
+			word = canonical
+		}
+		out = append(out, singularize(word))
+	}
+	return out
+}
+
+func singularize(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"),
+		strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") &&
+		!strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") &&
+		!strings.HasSuffix(word, "is"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func flattenWords(segments [][]string) []string {
+	var out []string
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return out
+}
+
+// diceCoefficient measures the similarity of two word sets.
+func diceCoefficient(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	setA := map[string]bool{}
+	for _, w := range a {
+		setA[w] = true
+	}
+	setB := map[string]bool{}
+	for _, w := range b {
+		setB[w] = true
+	}
+	var common int
+	for w := range setA {
+		if setB[w] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(setA)+len(setB))
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+func TestSplitWords(t *testing.T) {
+	cases := []struct {
+		input  string
+		expect []string
+	}{
+		{"ddos_protection_plan", []string{"ddos", "protection", "plan"}},
+		{"ddosProtectionPlan", []string{"ddos", "protection", "plan"}},
+		{"DNSServers", []string{"dns", "servers"}},
+		{"ipV6Address", []string{"ip", "v6", "address"}},
+		{"id", []string{"id"}},
+	}
+	for idx, c := range cases {
+		require.Equal(t, c.expect, splitWords(c.input), idx)
+	}
+}
+
+func TestAddrSimilarity(t *testing.T) {
+	cases := []struct {
+		tfAddr  string
+		swgAddr string
+		expect  float64
+	}{
+		{"dns_servers", "properties.dhcpOptions.dnsServers", 0.7 + 0.3*2*2/float64(2+4)},
+		{"ip_configuration.subnet_id", "properties.ipConfigurations.properties.subnet.id", 1},
+		{"max_size", "properties.maximumSize", 1},
+		{"location", "properties.dhcpOptions.dnsServers", 0},
+	}
+	for idx, c := range cases {
+		actual := addrSimilarity(propertyaddr.NewTerraformPropertyAddr("", c.tfAddr).PropertyAddr, propertyaddr.MustParseSwaggerPropertyAddr(c.swgAddr).PropertyAddr)
+		require.InDelta(t, c.expect, actual, 1e-9, idx)
+	}
+}
+
+func TestSuggestLinks(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger")
+
+	blockInput := []byte(`
+{
+  "attributes": {
+    "name": {"type": "string"},
+    "names": {"type": ["list", "string"]},
+    "count": {"type": "number"},
+    "tags": {"type": ["map", "string"]}
+  }
+}`)
+	var block TerraformBlock
+	require.NoError(t, json.Unmarshal(blockInput, &block))
+
+	tfschema := TFSchema{
+		Name:        "res1",
+		SwaggerSpec: "types.json",
+		PropertyLinks: map[string][]SwaggerLink{
+			"name":  {},
+			"names": {},
+			"count": {},
+			"tags":  {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:tags")}},
+		},
+	}
+
+	swgschema, err := NewSWGSchema(specBasePath, "types.json", "Resource")
+	require.NoError(t, err)
+
+	suggestions, err := SuggestLinks(tfschema, swgschema, LinkSuggestOptions{
+		MinScore:      0.5,
+		MaxCandidates: 2,
+		MaxDepth:      10,
+		Block:         &block,
+		Conversions:   DefaultTypeConversions,
+	})
+	require.NoError(t, err)
+
+	expect := []LinkSuggestion{
+		{TFProp: "count", SWGProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:count"), Score: 1},
+		{TFProp: "name", SWGProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:name"), Score: 1},
+		{TFProp: "name", SWGProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:names"), Score: 0.5},
+		{TFProp: "names", SWGProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:names"), Score: 1},
+		{TFProp: "names", SWGProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:name"), Score: 0.5},
+	}
+	require.Equal(t, expect, suggestions)
+
+	draft := tfschema.ApplyLinkSuggestions(suggestions)
+	require.Equal(t, []SwaggerLink{
+		{
+			SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Resource:names"),
+			Note:       "suggested (score: 1.00), to be confirmed",
+		},
+	}, draft.PropertyLinks["names"])
+	require.Equal(t, tfschema.PropertyLinks["tags"], draft.PropertyLinks["tags"])
+	require.Empty(t, tfschema.PropertyLinks["names"])
+}
@@ -0,0 +1,391 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// MappingNodeStatus is the status of a leaf node in the MappingDiagram, which is used to highlight the node.
+type MappingNodeStatus string
+
+const (
+	// The terraform property is linked, or the swagger property is linked by any terraform property.
+	MappingNodeCovered MappingNodeStatus = "covered"
+	// The terraform property is neither linked nor marked as not applicable, or the swagger property is neither linked nor granted.
+	MappingNodeUncovered MappingNodeStatus = "uncovered"
+	// The swagger property, or its schema as a whole, is granted not to be supported in Terraform.
+	MappingNodeGranted MappingNodeStatus = "granted"
+	// The terraform property is marked as not applicable.
+	MappingNodeNotApplicable MappingNodeStatus = "not_applicable"
+)
+
+// MappingDiagramNode is a node of either the terraform property tree or the swagger property tree. The root of the terraform
+// property tree is the resource, while the root of the swagger property tree is the schema.
+type MappingDiagramNode struct {
+	ID    string
+	Label string
+
+	// The ID of the parent node, empty for the root node.
+	Parent string
+
+	// The status of the node, empty for the non-leaf node.
+	Status MappingNodeStatus
+}
+
+// MappingDiagramEdge is a TFLink from a terraform property node to a swagger property node.
+type MappingDiagramEdge struct {
+	From      string
+	To        string
+	Transform LinkTransform
+}
+
+// MappingDiagram is the terraform property tree, the expanded swagger property tree, and the TFLinks in between, of either a
+// terraform resource or a swagger schema.
+type MappingDiagram struct {
+	TFNodes  []MappingDiagramNode
+	SWGNodes []MappingDiagramNode
+	Edges    []MappingDiagramEdge
+}
+
+// NewTFResourceMappingDiagram builds the MappingDiagram of the terraform resource, whose swagger side consists of every schema
+// that has any property linked by the resource. The tfschema of the resource is optional, if specified, the terraform side
+// consists of all the terraform properties of it, otherwise, only the linked terraform properties.
+func NewTFResourceMappingDiagram(resourceName string, tfschema *TFSchema, schemas map[SWGSchemaAddr]*SWGSchema) MappingDiagram {
+	linkedSchemas := map[SWGSchemaAddr]*SWGSchema{}
+	for addr, schema := range schemas {
+	outer:
+		for _, prop := range schema.Properties {
+			for _, link := range prop.TFLinks {
+				if link.Prop.ResourceName == resourceName {
+					linkedSchemas[addr] = schema
+					break outer
+				}
+			}
+		}
+	}
+	var tfschemas []TFSchema
+	if tfschema != nil {
+		tfschemas = append(tfschemas, *tfschema)
+	}
+	return newMappingDiagram(tfschemas, linkedSchemas, func(link TFLink) bool {
+		return link.Prop.ResourceName == resourceName
+	})
+}
+
+// NewSWGSchemaMappingDiagram builds the MappingDiagram of the swagger schema, whose terraform side consists of the terraform
+// properties linked to the schema.
+func NewSWGSchemaMappingDiagram(addr SWGSchemaAddr, schema *SWGSchema) MappingDiagram {
+	return newMappingDiagram(nil, map[SWGSchemaAddr]*SWGSchema{addr: schema}, func(TFLink) bool {
+		return true
+	})
+}
+
+func newMappingDiagram(tfschemas []TFSchema, schemas map[SWGSchemaAddr]*SWGSchema, linkFilter func(TFLink) bool) MappingDiagram {
+	tfTree := newMappingTree("tf")
+	for _, tfschema := range tfschemas {
+		tfTree.addRoot(tfschema.Name, tfschema.Name)
+		for _, tfProp := range tfschema.PropertyLinks.sortedKeys() {
+			var status MappingNodeStatus
+			switch tfschema.PropertyLinks.Status(tfProp) {
+			case TFPropertyLinked:
+				status = MappingNodeCovered
+			case TFPropertyNotApplicable:
+				status = MappingNodeNotApplicable
+			default:
+				status = MappingNodeUncovered
+			}
+			tfTree.addLeaf(tfschema.Name, strings.Split(tfProp, "."), status)
+		}
+	}
+
+	swgTree := newMappingTree("swg")
+	type link struct {
+		tfRoot    string
+		tfAddr    []string
+		swgRoot   string
+		swgAddr   []string
+		transform LinkTransform
+	}
+	var links []link
+	for _, schemaAddr := range sortedSWGSchemaAddrs(schemas) {
+		schema := schemas[schemaAddr]
+		swgRoot := string(schemaAddr)
+		swgTree.addRoot(swgRoot, fmt.Sprintf("%s (%s)", schemaAddr.SchemaName(), schemaAddr.SwaggerRelPath()))
+
+		propAddrs := make([]string, 0, len(schema.Properties))
+		for propAddr := range schema.Properties {
+			propAddrs = append(propAddrs, propAddr)
+		}
+		sort.Strings(propAddrs)
+
+		for _, propAddr := range propAddrs {
+			prop := schema.Properties[propAddr]
+			status := MappingNodeUncovered
+			switch {
+			case prop.IsGranted || schema.IsGranted:
+				status = MappingNodeGranted
+			case len(prop.TFLinks) != 0:
+				status = MappingNodeCovered
+			}
+			var segments []string
+			relAddr := propertyaddr.MustParseSwaggerPropertyAddr(propAddr).PropertyAddr
+			for _, segment := range relAddr {
+				segments = append(segments, segment.String())
+			}
+			swgTree.addLeaf(swgRoot, segments, status)
+
+			for _, tfLink := range prop.TFLinks {
+				if !linkFilter(tfLink) {
+					continue
+				}
+				links = append(links, link{
+					tfRoot:    tfLink.Prop.ResourceName,
+					tfAddr:    tfLink.Prop.PropertyAddr,
+					swgRoot:   swgRoot,
+					swgAddr:   segments,
+					transform: tfLink.Transform,
+				})
+			}
+		}
+	}
+
+	// The linked terraform properties that are not found in the tfschemas (e.g. no tfschema is specified) are added as covered.
+	for _, l := range links {
+		if !tfTree.hasRoot(l.tfRoot) {
+			tfTree.addRoot(l.tfRoot, l.tfRoot)
+		}
+		if _, ok := tfTree.lookup(l.tfRoot, l.tfAddr); !ok {
+			tfTree.addLeaf(l.tfRoot, l.tfAddr, MappingNodeCovered)
+		}
+	}
+
+	var edges []MappingDiagramEdge
+	for _, l := range links {
+		from, _ := tfTree.lookup(l.tfRoot, l.tfAddr)
+		to, _ := swgTree.lookup(l.swgRoot, l.swgAddr)
+		edges = append(edges, MappingDiagramEdge{From: from, To: to, Transform: l.transform})
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return tfTree.order[edges[i].From] < tfTree.order[edges[j].From]
+		}
+		return swgTree.order[edges[i].To] < swgTree.order[edges[j].To]
+	})
+
+	return MappingDiagram{
+		TFNodes:  tfTree.nodes,
+		SWGNodes: swgTree.nodes,
+		Edges:    edges,
+	}
+}
+
+// mappingTree builds the nodes of a property tree, the nodes are ordered as they are added, with the parent node before the
+// child nodes.
+type mappingTree struct {
+	idPrefix string
+	nodes    []MappingDiagramNode
+
+	// The node ID, keyed by the root key and the path to the node.
+	ids map[string]string
+	// The index of each node in the nodes, keyed by the node ID.
+	order map[string]int
+}
+
+func newMappingTree(idPrefix string) *mappingTree {
+	return &mappingTree{
+		idPrefix: idPrefix,
+		ids:      map[string]string{},
+		order:    map[string]int{},
+	}
+}
+
+func mappingTreeKey(root string, path []string) string {
+	return strings.Join(append([]string{root}, path...), "\x00")
+}
+
+func (t *mappingTree) add(key, label, parent string, status MappingNodeStatus) string {
+	id := fmt.Sprintf("%s%d", t.idPrefix, len(t.nodes))
+	t.ids[key] = id
+	t.order[id] = len(t.nodes)
+	t.nodes = append(t.nodes, MappingDiagramNode{ID: id, Label: label, Parent: parent, Status: status})
+	return id
+}
+
+func (t *mappingTree) hasRoot(root string) bool {
+	_, ok := t.ids[mappingTreeKey(root, nil)]
+	return ok
+}
+
+func (t *mappingTree) addRoot(root, label string) {
+	if t.hasRoot(root) {
+		return
+	}
+	t.add(mappingTreeKey(root, nil), label, "", "")
+}
+
+// addLeaf adds the leaf node, together with the missing intermediate nodes along the path.
+func (t *mappingTree) addLeaf(root string, path []string, status MappingNodeStatus) {
+	parent := t.ids[mappingTreeKey(root, nil)]
+	if len(path) == 0 {
+		// e.g. the swagger schema that has no property
+		t.nodes[t.order[parent]].Status = status
+		return
+	}
+	for i := range path {
+		key := mappingTreeKey(root, path[:i+1])
+		id, ok := t.ids[key]
+		if !ok {
+			id = t.add(key, path[i], parent, "")
+		}
+		parent = id
+	}
+	t.nodes[t.order[parent]].Status = status
+}
+
+func (t *mappingTree) lookup(root string, path []string) (string, bool) {
+	id, ok := t.ids[mappingTreeKey(root, path)]
+	return id, ok
+}
+
+func sortedSWGSchemaAddrs(schemas map[SWGSchemaAddr]*SWGSchema) []SWGSchemaAddr {
+	addrs := make([]SWGSchemaAddr, 0, len(schemas))
+	for addr := range schemas {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+	return addrs
+}
+
+// WriteMermaid writes the MappingDiagram as a Mermaid flowchart, with the terraform tree and the swagger tree in two subgraphs.
+func (d MappingDiagram) WriteMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	writeTree := func(name, label string, nodes []MappingDiagramNode) {
+		fmt.Fprintf(&sb, "  subgraph %s[%q]\n", name, label)
+		for _, node := range nodes {
+			fmt.Fprintf(&sb, "    %s[\"%s\"]\n", node.ID, mermaidEscape(node.Label))
+		}
+		for _, node := range nodes {
+			if node.Parent != "" {
+				fmt.Fprintf(&sb, "    %s --- %s\n", node.Parent, node.ID)
+			}
+		}
+		sb.WriteString("  end\n")
+	}
+	writeTree("terraform", "Terraform", d.TFNodes)
+	writeTree("swagger", "Swagger", d.SWGNodes)
+
+	for _, edge := range d.Edges {
+		if edge.Transform != "" {
+			fmt.Fprintf(&sb, "  %s ==>|%s| %s\n", edge.From, mermaidEscape(string(edge.Transform)), edge.To)
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s ==> %s\n", edge.From, edge.To)
+	}
+
+	nodesByStatus := d.nodesByStatus()
+	for _, status := range mappingNodeStatuses {
+		if len(nodesByStatus[status]) == 0 {
+			continue
+		}
+		style := mappingNodeStyles[status]
+		fmt.Fprintf(&sb, "  classDef %s fill:%s,stroke:%s", status, style.fill, style.stroke)
+		if style.dashed {
+			sb.WriteString(",stroke-dasharray:5 5")
+		}
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "  class %s %s\n", strings.Join(nodesByStatus[status], ","), status)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteDOT writes the MappingDiagram as a Graphviz digraph, with the terraform tree and the swagger tree in two clusters.
+func (d MappingDiagram) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph mapping {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+	writeTree := func(name, label string, nodes []MappingDiagramNode) {
+		fmt.Fprintf(&sb, "  subgraph cluster_%s {\n", name)
+		fmt.Fprintf(&sb, "    label=%s;\n", dotQuote(label))
+		for _, node := range nodes {
+			attrs := []string{"label=" + dotQuote(node.Label)}
+			if style, ok := mappingNodeStyles[node.Status]; ok {
+				attrs = append(attrs, "fillcolor="+dotQuote(style.fill), "color="+dotQuote(style.stroke))
+				if style.dashed {
+					attrs = append(attrs, `style="rounded,filled,dashed"`)
+				}
+			}
+			fmt.Fprintf(&sb, "    %s [%s];\n", node.ID, strings.Join(attrs, ", "))
+		}
+		for _, node := range nodes {
+			if node.Parent != "" {
+				fmt.Fprintf(&sb, "    %s -> %s [arrowhead=none];\n", node.Parent, node.ID)
+			}
+		}
+		sb.WriteString("  }\n")
+	}
+	writeTree("terraform", "Terraform", d.TFNodes)
+	writeTree("swagger", "Swagger", d.SWGNodes)
+
+	for _, edge := range d.Edges {
+		attrs := []string{`color="#1f6feb"`, "constraint=false"}
+		if edge.Transform != "" {
+			attrs = append(attrs, "label="+dotQuote(string(edge.Transform)))
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (d MappingDiagram) nodesByStatus() map[MappingNodeStatus][]string {
+	out := map[MappingNodeStatus][]string{}
+	for _, nodes := range [][]MappingDiagramNode{d.TFNodes, d.SWGNodes} {
+		for _, node := range nodes {
+			if node.Status != "" {
+				out[node.Status] = append(out[node.Status], node.ID)
+			}
+		}
+	}
+	return out
+}
+
+var mappingNodeStatuses = []MappingNodeStatus{
+	MappingNodeCovered,
+	MappingNodeUncovered,
+	MappingNodeGranted,
+	MappingNodeNotApplicable,
+}
+
+type mappingNodeStyle struct {
+	fill   string
+	stroke string
+	dashed bool
+}
+
+var mappingNodeStyles = map[MappingNodeStatus]mappingNodeStyle{
+	MappingNodeCovered:       {fill: "#d4edda", stroke: "#28a745"},
+	MappingNodeUncovered:     {fill: "#f8d7da", stroke: "#dc3545"},
+	MappingNodeGranted:       {fill: "#e2e3e5", stroke: "#6c757d", dashed: true},
+	MappingNodeNotApplicable: {fill: "#fff3cd", stroke: "#ffc107", dashed: true},
+}
+
+// mermaidEscape escapes the text to be put inside the quoted Mermaid node label or the edge label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTFResourceMappingDiagram(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	rel := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+
+	foo, err := NewSWGSchema(specBasePath, rel, "Foo")
+	require.NoError(t, err)
+	require.NoError(t, foo.ExpandAll(2))
+	foo.Properties["id"].TFLinks = TFLinks{{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:id")}}
+	foo.Properties["properties.p1"].TFLinks = TFLinks{
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:config.p1"), Transform: LinkTransformBoolToEnum},
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo_other:p1")},
+	}
+	foo.Properties["etag"].grant(GrantInfo{Comment: "read only"}, "foo.json", "")
+
+	bar, err := NewSWGSchema(specBasePath, rel, "Bar")
+	require.NoError(t, err)
+	bar.Properties["name"].TFLinks = TFLinks{{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_bar:name")}}
+
+	schemas := map[SWGSchemaAddr]*SWGSchema{
+		NewSWGSchemaAddr(rel, "Foo"): foo,
+		NewSWGSchemaAddr(rel, "Bar"): bar,
+	}
+	tfschema := &TFSchema{
+		Name:        "azurerm_foo",
+		SwaggerSpec: rel,
+		PropertyLinks: TFSchemaPropertyLinks{
+			"id":                  {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Foo:id")}},
+			"config.p1":           {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Foo:properties.p1")}},
+			"config.p2":           {},
+			"resource_group_name": {NewNotApplicableLink("part of the resource id")},
+		},
+	}
+
+	diagram := NewTFResourceMappingDiagram("azurerm_foo", tfschema, schemas)
+	require.Equal(t, []MappingDiagramNode{
+		{ID: "tf0", Label: "azurerm_foo"},
+		{ID: "tf1", Label: "config", Parent: "tf0"},
+		{ID: "tf2", Label: "p1", Parent: "tf1", Status: MappingNodeCovered},
+		{ID: "tf3", Label: "p2", Parent: "tf1", Status: MappingNodeUncovered},
+		{ID: "tf4", Label: "id", Parent: "tf0", Status: MappingNodeCovered},
+		{ID: "tf5", Label: "resource_group_name", Parent: "tf0", Status: MappingNodeNotApplicable},
+	}, diagram.TFNodes)
+	require.Equal(t, []MappingDiagramNode{
+		{ID: "swg0", Label: "Foo (" + rel + ")"},
+		{ID: "swg1", Label: "etag", Parent: "swg0", Status: MappingNodeGranted},
+		{ID: "swg2", Label: "id", Parent: "swg0", Status: MappingNodeCovered},
+		{ID: "swg3", Label: "old", Parent: "swg0", Status: MappingNodeUncovered},
+		{ID: "swg4", Label: "properties", Parent: "swg0"},
+		{ID: "swg5", Label: "p1", Parent: "swg4", Status: MappingNodeCovered},
+		{ID: "swg6", Label: "p2", Parent: "swg4", Status: MappingNodeUncovered},
+	}, diagram.SWGNodes)
+	require.Equal(t, []MappingDiagramEdge{
+		{From: "tf2", To: "swg5", Transform: LinkTransformBoolToEnum},
+		{From: "tf4", To: "swg2"},
+	}, diagram.Edges)
+
+	var sb strings.Builder
+	require.NoError(t, diagram.WriteMermaid(&sb))
+	require.Contains(t, sb.String(), "  tf2 ==>|bool_to_enum| swg5\n")
+	require.Contains(t, sb.String(), "  class tf3,swg3,swg6 uncovered\n")
+
+	sb.Reset()
+	require.NoError(t, diagram.WriteDOT(&sb))
+	require.Contains(t, sb.String(), `  tf2 -> swg5 [color="#1f6feb", constraint=false, label="bool_to_enum"];`)
+}
+
+func TestNewSWGSchemaMappingDiagram(t *testing.T) {
+	addr := NewSWGSchemaAddr("foo.json", "Foo")
+	schema := &SWGSchema{
+		Name: "Foo",
+		Properties: SWGSchemaProperties{
+			"a.b": {TFLinks: TFLinks{
+				{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:b")},
+				{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_bar:x.b")},
+			}},
+			"c": {},
+		},
+		IsGranted: true,
+	}
+
+	diagram := NewSWGSchemaMappingDiagram(addr, schema)
+	require.Equal(t, []MappingDiagramNode{
+		{ID: "tf0", Label: "azurerm_foo"},
+		{ID: "tf1", Label: "b", Parent: "tf0", Status: MappingNodeCovered},
+		{ID: "tf2", Label: "azurerm_bar"},
+		{ID: "tf3", Label: "x", Parent: "tf2"},
+		{ID: "tf4", Label: "b", Parent: "tf3", Status: MappingNodeCovered},
+	}, diagram.TFNodes)
+	require.Equal(t, []MappingDiagramNode{
+		{ID: "swg0", Label: "Foo (foo.json)"},
+		{ID: "swg1", Label: "a", Parent: "swg0"},
+		{ID: "swg2", Label: "b", Parent: "swg1", Status: MappingNodeGranted},
+		{ID: "swg3", Label: "c", Parent: "swg0", Status: MappingNodeGranted},
+	}, diagram.SWGNodes)
+	require.Equal(t, []MappingDiagramEdge{
+		{From: "tf1", To: "swg2"},
+		{From: "tf4", To: "swg2"},
+	}, diagram.Edges)
+}
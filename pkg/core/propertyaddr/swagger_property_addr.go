@@ -220,6 +220,16 @@ func NewSwaggerPropertyAddrSegment(name string, discriminator *string) SwaggerPr
 	}
 }
 
+// Name returns the property name of the segment, without the discriminator value.
+func (prop SwaggerPropertyAddrSegment) Name() string {
+	return prop.name
+}
+
+// DiscriminatorValue returns the discriminator value of the segment, which is nil if the property is not a derived model.
+func (prop SwaggerPropertyAddrSegment) DiscriminatorValue() *string {
+	return prop.discriminatorValue
+}
+
 func (prop SwaggerPropertyAddrSegment) String() string {
 	v := prop.name
 	if prop.discriminatorValue != nil {
@@ -0,0 +1,769 @@
+// Package providersrc infers the links between the terraform properties and the swagger properties from the Go source code
+// of the provider, by following how each terraform property is expanded into (or flattened from) the fields of the SDK model
+// in the CRUD functions of a resource.
+//
+// The analysis is purely syntactic (i.e. based on go/ast), so that it works on a checkout of the provider without building it.
+// As a consequence, the result is a best effort and is meant to be reviewed by a human.
+package providersrc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxCallDepth limits the depth of the calls among the package functions followed by the analysis.
+const maxCallDepth = 8
+
+// maxValueDepth limits the depth of walking a value, to avoid looping forever on a value that (indirectly) contains itself.
+const maxValueDepth = 16
+
+// resourceFuncKeys are the keys of the schema.Resource whose functions convert between the terraform properties and the SDK model.
+var resourceFuncKeys = map[string]bool{
+	"Create":        true,
+	"Read":          true,
+	"Update":        true,
+	"CreateContext": true,
+	"ReadContext":   true,
+	"UpdateContext": true,
+}
+
+// resourceDataGetters are the methods of the schema.ResourceData that read a terraform property.
+var resourceDataGetters = map[string]bool{
+	"Get":         true,
+	"GetOk":       true,
+	"GetOkExists": true,
+	"GetChange":   true,
+}
+
+// SDKLink is a link between a terraform property (e.g. "subnet.address_prefix") and a field of the SDK model
+// (e.g. "VirtualNetworkPropertiesFormat.Subnets.SubnetPropertiesFormat.AddressPrefix").
+type SDKLink struct {
+	TFProp   string
+	SDKField string
+}
+
+// Package is a parsed Go package of the provider, which contains the implementation of one or more resources.
+type Package struct {
+	funcs   map[string]*ast.FuncDecl
+	imports map[string]bool
+	files   []*ast.File
+}
+
+// ParseDir parses the (non-test) Go source files in the directory.
+func ParseDir(dir string) (*Package, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Go package in %s: %w", dir, err)
+	}
+
+	p := &Package{
+		funcs:   map[string]*ast.FuncDecl{},
+		imports: map[string]bool{},
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			p.files = append(p.files, f)
+			for _, imp := range f.Imports {
+				path, _ := strconv.Unquote(imp.Path.Value)
+				name := path[strings.LastIndex(path, "/")+1:]
+				if imp.Name != nil {
+					name = imp.Name.Name
+				}
+				p.imports[name] = true
+			}
+			for _, decl := range f.Decls {
+				if fdecl, ok := decl.(*ast.FuncDecl); ok && fdecl.Recv == nil {
+					p.funcs[fdecl.Name.Name] = fdecl
+				}
+			}
+		}
+	}
+	if len(p.files) == 0 {
+		return nil, fmt.Errorf("no Go source file found in %s", dir)
+	}
+	return p, nil
+}
+
+// ResourceFuncs returns the names of the create, read and update functions of the terraform resource, which are found via
+// the resource registration (e.g. `"azurerm_virtual_network": resourceArmVirtualNetwork()`) in the package.
+func (p *Package) ResourceFuncs(resourceName string) ([]string, error) {
+	var resourceFunc string
+	for _, f := range p.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			kv, ok := n.(*ast.KeyValueExpr)
+			if !ok || resourceFunc != "" {
+				return resourceFunc == ""
+			}
+			if key, ok := stringLit(kv.Key); !ok || key != resourceName {
+				return true
+			}
+			if call, ok := kv.Value.(*ast.CallExpr); ok {
+				if id, ok := call.Fun.(*ast.Ident); ok {
+					resourceFunc = id.Name
+				}
+			}
+			return true
+		})
+	}
+	if resourceFunc == "" {
+		return nil, fmt.Errorf("registration of %s is not found", resourceName)
+	}
+	decl, ok := p.funcs[resourceFunc]
+	if !ok {
+		return nil, fmt.Errorf("function %s of %s is not found", resourceFunc, resourceName)
+	}
+
+	var funcs []string
+	ast.Inspect(decl, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || !resourceFuncKeys[key.Name] {
+			return true
+		}
+		if id, ok := kv.Value.(*ast.Ident); ok {
+			if _, ok := p.funcs[id.Name]; ok {
+				funcs = appendUnique(funcs, id.Name)
+			}
+		}
+		return true
+	})
+	if len(funcs) == 0 {
+		return nil, fmt.Errorf("no CRUD function of %s is found in %s", resourceName, resourceFunc)
+	}
+	sort.Strings(funcs)
+	return funcs, nil
+}
+
+// InferSDKLinks infers the links between the terraform properties of the resource and the fields of its SDK model, whose type
+// name is modelName (e.g. "VirtualNetwork").
+//
+// In the expand direction, it follows the terraform properties read via "d.Get()" (and the like) into the SDK model literals.
+// In the flatten direction, it follows the SDK model fields into the terraform properties set via "d.Set()". Both directions
+// go through the local variables and the functions of the package (e.g. "expandXXX()" and "flattenXXX()").
+func (p *Package) InferSDKLinks(resourceName, modelName string) ([]SDKLink, error) {
+	funcs, err := p.ResourceFuncs(resourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &evaluator{
+		pkg:   p,
+		model: modelName,
+		stack: map[*ast.FuncDecl]bool{},
+		links: map[SDKLink]bool{},
+	}
+	for _, name := range funcs {
+		e.evalFunc(p.funcs[name], nil)
+	}
+	for _, model := range e.models {
+		e.linkExpanded("", model, 0)
+	}
+
+	links := make([]SDKLink, 0, len(e.links))
+	for link := range e.links {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].TFProp != links[j].TFProp {
+			return links[i].TFProp < links[j].TFProp
+		}
+		return links[i].SDKField < links[j].SDKField
+	})
+	return links, nil
+}
+
+// value is the abstract value of a Go expression, which records where it is derived from.
+type value struct {
+	// The terraform properties that the value is derived from (e.g. via "d.Get()").
+	tfProps []string
+
+	// The SDK model fields that the value is derived from, relative to the SDK model.
+	sdkFields []string
+
+	// The fields of a SDK model struct literal.
+	fields map[string]*value
+
+	// The keys of a terraform block built by a map literal (e.g. map[string]interface{}{"name": xxx}).
+	keys map[string]*value
+}
+
+// field returns the value of the field of a struct value. A value that is not derived from any SDK model field, is regarded as
+// the SDK model itself (e.g. the response of an API call).
+func (v *value) field(name string) *value {
+	if v != nil {
+		if f, ok := v.fields[name]; ok {
+			return f
+		}
+		if len(v.fields) != 0 || len(v.keys) != 0 || len(v.tfProps) != 0 {
+			return nil
+		}
+	}
+	if v == nil || len(v.sdkFields) == 0 {
+		return &value{sdkFields: []string{name}}
+	}
+	out := &value{}
+	for _, f := range v.sdkFields {
+		out.sdkFields = appendUnique(out.sdkFields, f+"."+name)
+	}
+	return out
+}
+
+// key returns the value of the key of a terraform block value.
+func (v *value) key(name string) *value {
+	if v == nil {
+		return nil
+	}
+	if k, ok := v.keys[name]; ok {
+		return k
+	}
+	if len(v.tfProps) == 0 {
+		return nil
+	}
+	out := &value{}
+	for _, p := range v.tfProps {
+		out.tfProps = appendUnique(out.tfProps, p+"."+name)
+	}
+	return out
+}
+
+// absorb merges the other value into this value in place.
+func (v *value) absorb(o *value) {
+	v.absorbDepth(o, 0)
+}
+
+func (v *value) absorbDepth(o *value, depth int) {
+	if o == nil || v == o || depth > maxValueDepth {
+		return
+	}
+	for _, p := range o.tfProps {
+		v.tfProps = appendUnique(v.tfProps, p)
+	}
+	for _, f := range o.sdkFields {
+		v.sdkFields = appendUnique(v.sdkFields, f)
+	}
+	for name, f := range o.fields {
+		if v.fields == nil {
+			v.fields = map[string]*value{}
+		}
+		if v.fields[name] == nil {
+			v.fields[name] = &value{}
+		}
+		v.fields[name].absorbDepth(f, depth+1)
+	}
+	for name, k := range o.keys {
+		if v.keys == nil {
+			v.keys = map[string]*value{}
+		}
+		if v.keys[name] == nil {
+			v.keys[name] = &value{}
+		}
+		v.keys[name].absorbDepth(k, depth+1)
+	}
+}
+
+// merge returns a value derived from both values.
+func merge(a, b *value) *value {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	out := &value{}
+	out.absorb(a)
+	out.absorb(b)
+	return out
+}
+
+type scope map[string]*value
+
+type evaluator struct {
+	pkg   *Package
+	model string
+	stack map[*ast.FuncDecl]bool
+
+	// The values of the SDK model literals.
+	models []*value
+
+	links map[SDKLink]bool
+}
+
+func (e *evaluator) evalFunc(decl *ast.FuncDecl, args []*value) *value {
+	if decl.Body == nil || e.stack[decl] || len(e.stack) >= maxCallDepth {
+		return nil
+	}
+	e.stack[decl] = true
+	defer delete(e.stack, decl)
+
+	env := scope{}
+	var idx int
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			v := &value{}
+			if idx < len(args) && args[idx] != nil {
+				v = args[idx]
+			}
+			env[name.Name] = v
+			idx++
+		}
+	}
+
+	var ret *value
+	e.execBlock(decl.Body.List, env, &ret)
+	return ret
+}
+
+func (e *evaluator) execBlock(stmts []ast.Stmt, env scope, ret **value) {
+	for _, stmt := range stmts {
+		e.exec(stmt, env, ret)
+	}
+}
+
+func (e *evaluator) exec(stmt ast.Stmt, env scope, ret **value) {
+	switch stmt := stmt.(type) {
+	case *ast.BlockStmt:
+		e.execBlock(stmt.List, env, ret)
+	case *ast.ExprStmt:
+		e.eval(stmt.X, env)
+	case *ast.AssignStmt:
+		e.assign(stmt.Lhs, stmt.Rhs, env)
+	case *ast.DeclStmt:
+		gdecl, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok {
+			return
+		}
+		for _, spec := range gdecl.Specs {
+			vspec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			lhs := make([]ast.Expr, 0, len(vspec.Names))
+			for _, name := range vspec.Names {
+				lhs = append(lhs, name)
+			}
+			if len(vspec.Values) != 0 {
+				e.assign(lhs, vspec.Values, env)
+				continue
+			}
+			for _, name := range vspec.Names {
+				v := &value{}
+				if typeName(vspec.Type) == e.model {
+					e.models = append(e.models, v)
+				}
+				env[name.Name] = v
+			}
+		}
+	case *ast.IfStmt:
+		if stmt.Init != nil {
+			e.exec(stmt.Init, env, ret)
+		}
+		e.eval(stmt.Cond, env)
+		e.exec(stmt.Body, env, ret)
+		if stmt.Else != nil {
+			e.exec(stmt.Else, env, ret)
+		}
+	case *ast.ForStmt:
+		if stmt.Init != nil {
+			e.exec(stmt.Init, env, ret)
+		}
+		e.exec(stmt.Body, env, ret)
+	case *ast.RangeStmt:
+		x := e.eval(stmt.X, env)
+		if id, ok := stmt.Value.(*ast.Ident); ok {
+			env[id.Name] = x
+		}
+		if id, ok := stmt.Key.(*ast.Ident); ok {
+			env[id.Name] = nil
+		}
+		e.exec(stmt.Body, env, ret)
+	case *ast.SwitchStmt:
+		if stmt.Init != nil {
+			e.exec(stmt.Init, env, ret)
+		}
+		if stmt.Tag != nil {
+			e.eval(stmt.Tag, env)
+		}
+		e.exec(stmt.Body, env, ret)
+	case *ast.TypeSwitchStmt:
+		if stmt.Init != nil {
+			e.exec(stmt.Init, env, ret)
+		}
+		e.exec(stmt.Assign, env, ret)
+		e.exec(stmt.Body, env, ret)
+	case *ast.CaseClause:
+		e.execBlock(stmt.Body, env, ret)
+	case *ast.LabeledStmt:
+		e.exec(stmt.Stmt, env, ret)
+	case *ast.ReturnStmt:
+		if len(stmt.Results) != 0 {
+			*ret = merge(*ret, e.eval(stmt.Results[0], env))
+		}
+	}
+}
+
+func (e *evaluator) assign(lhs, rhs []ast.Expr, env scope) {
+	if len(lhs) == len(rhs) {
+		values := make([]*value, len(rhs))
+		for i, expr := range rhs {
+			values[i] = e.eval(expr, env)
+		}
+		for i, expr := range lhs {
+			e.assignTo(expr, values[i], env)
+		}
+		return
+	}
+	if len(rhs) != 1 {
+		return
+	}
+
+	// A multi-value call outside of the package (e.g. "resp, err := client.Get(...)") is regarded as returning a new object,
+	// rather than a value derived from its arguments.
+	v := e.eval(rhs[0], env)
+	if call, ok := rhs[0].(*ast.CallExpr); ok && !e.isPackageCall(call) && !isResourceDataGet(call) {
+		v = &value{}
+	}
+	e.assignTo(lhs[0], v, env)
+}
+
+func (e *evaluator) assignTo(lhs ast.Expr, v *value, env scope) {
+	switch lhs := lhs.(type) {
+	case *ast.Ident:
+		if lhs.Name != "_" {
+			env[lhs.Name] = v
+		}
+	case *ast.ParenExpr:
+		e.assignTo(lhs.X, v, env)
+	case *ast.StarExpr:
+		e.assignTo(lhs.X, v, env)
+	case *ast.SelectorExpr:
+		target := e.lvalue(lhs.X, env)
+		if target == nil {
+			return
+		}
+		if target.fields == nil {
+			target.fields = map[string]*value{}
+		}
+		target.fields[lhs.Sel.Name] = v
+	case *ast.IndexExpr:
+		target := e.lvalue(lhs.X, env)
+		if target == nil {
+			return
+		}
+		key, ok := stringLit(lhs.Index)
+		if !ok {
+			target.absorb(v)
+			return
+		}
+		if target.keys == nil {
+			target.keys = map[string]*value{}
+		}
+		target.keys[key] = v
+	}
+}
+
+// lvalue returns the value to be updated by an assignment, which is created if absent.
+func (e *evaluator) lvalue(expr ast.Expr, env scope) *value {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if e.isPackageRef(expr, env) {
+			return nil
+		}
+		if env[expr.Name] == nil {
+			env[expr.Name] = &value{}
+		}
+		return env[expr.Name]
+	case *ast.ParenExpr:
+		return e.lvalue(expr.X, env)
+	case *ast.StarExpr:
+		return e.lvalue(expr.X, env)
+	case *ast.SelectorExpr:
+		target := e.lvalue(expr.X, env)
+		if target == nil {
+			return nil
+		}
+		if target.fields == nil {
+			target.fields = map[string]*value{}
+		}
+		if target.fields[expr.Sel.Name] == nil {
+			target.fields[expr.Sel.Name] = &value{}
+		}
+		return target.fields[expr.Sel.Name]
+	case *ast.IndexExpr:
+		target := e.lvalue(expr.X, env)
+		if target == nil {
+			return nil
+		}
+		key, ok := stringLit(expr.Index)
+		if !ok {
+			return target
+		}
+		if target.keys == nil {
+			target.keys = map[string]*value{}
+		}
+		if target.keys[key] == nil {
+			target.keys[key] = &value{}
+		}
+		return target.keys[key]
+	}
+	return nil
+}
+
+func (e *evaluator) eval(expr ast.Expr, env scope) *value {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return e.eval(expr.X, env)
+	case *ast.StarExpr:
+		return e.eval(expr.X, env)
+	case *ast.UnaryExpr:
+		return e.eval(expr.X, env)
+	case *ast.TypeAssertExpr:
+		return e.eval(expr.X, env)
+	case *ast.SliceExpr:
+		return e.eval(expr.X, env)
+	case *ast.BinaryExpr:
+		return merge(e.eval(expr.X, env), e.eval(expr.Y, env))
+	case *ast.Ident:
+		return env[expr.Name]
+	case *ast.SelectorExpr:
+		if e.isPackageRef(expr.X, env) {
+			return nil
+		}
+		return e.eval(expr.X, env).field(expr.Sel.Name)
+	case *ast.IndexExpr:
+		x := e.eval(expr.X, env)
+		if key, ok := stringLit(expr.Index); ok {
+			return x.key(key)
+		}
+		return x
+	case *ast.CompositeLit:
+		return e.evalCompositeLit(expr, expr.Type, env)
+	case *ast.CallExpr:
+		return e.evalCall(expr, env)
+	}
+	return nil
+}
+
+func (e *evaluator) evalCompositeLit(lit *ast.CompositeLit, typ ast.Expr, env scope) *value {
+	if lit.Type != nil {
+		typ = lit.Type
+	}
+	for {
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+			continue
+		}
+		break
+	}
+
+	var eltType ast.Expr
+	switch typ := typ.(type) {
+	case *ast.ArrayType:
+		eltType = typ.Elt
+	case *ast.MapType:
+		eltType = typ.Value
+	}
+
+	v := &value{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			v.absorb(e.evalElement(elt, eltType, env))
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && eltType == nil {
+			if v.fields == nil {
+				v.fields = map[string]*value{}
+			}
+			v.fields[key.Name] = e.evalElement(kv.Value, nil, env)
+			continue
+		}
+		if key, ok := stringLit(kv.Key); ok {
+			if v.keys == nil {
+				v.keys = map[string]*value{}
+			}
+			v.keys[key] = e.evalElement(kv.Value, eltType, env)
+			continue
+		}
+		v.absorb(e.evalElement(kv.Value, eltType, env))
+	}
+
+	if typeName(typ) == e.model {
+		e.models = append(e.models, v)
+	}
+	return v
+}
+
+// evalElement evaluates an element of a composite literal, whose type might be elided.
+func (e *evaluator) evalElement(expr ast.Expr, typ ast.Expr, env scope) *value {
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		return e.evalCompositeLit(lit, typ, env)
+	}
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		if lit, ok := unary.X.(*ast.CompositeLit); ok {
+			return e.evalCompositeLit(lit, typ, env)
+		}
+	}
+	return e.eval(expr, env)
+}
+
+func (e *evaluator) evalCall(call *ast.CallExpr, env scope) *value {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if name, ok := resourceDataProp(call); ok {
+			switch {
+			case resourceDataGetters[fun.Sel.Name]:
+				return &value{tfProps: []string{name}}
+			case fun.Sel.Name == "Set" && len(call.Args) == 2:
+				e.linkFlattened(name, e.eval(call.Args[1], env), 0)
+				return nil
+			}
+		}
+	case *ast.Ident:
+		if decl, ok := e.pkg.funcs[fun.Name]; ok && env[fun.Name] == nil {
+			args := make([]*value, 0, len(call.Args))
+			for _, arg := range call.Args {
+				args = append(args, e.eval(arg, env))
+			}
+			return e.evalFunc(decl, args)
+		}
+		if fun.Name == "make" || fun.Name == "new" {
+			return &value{}
+		}
+	case *ast.FuncLit:
+		return nil
+	}
+
+	// For any other function (e.g. "utils.String()" or "azure.NormalizeLocation()"), the result is regarded as derived from
+	// its arguments, as well as its receiver (e.g. "d.Get("xxx").(*schema.Set).List()").
+	var args *value
+	for _, arg := range call.Args {
+		args = merge(args, e.eval(arg, env))
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || e.isPackageRef(sel.X, env) {
+		return args
+	}
+	recv := e.eval(sel.X, env)
+	// The arguments passed to a method of a variable (e.g. "set.Add(item)") flow into the variable.
+	if id, ok := sel.X.(*ast.Ident); ok && recv != nil && args != nil {
+		env[id.Name].absorb(args)
+	}
+	return merge(recv, args)
+}
+
+// linkFlattened records the links between the terraform property (and its nested properties) and the SDK model fields
+// that the value set to it is derived from.
+func (e *evaluator) linkFlattened(tfProp string, v *value, depth int) {
+	if v == nil || depth > maxValueDepth {
+		return
+	}
+	for _, f := range v.sdkFields {
+		e.links[SDKLink{TFProp: tfProp, SDKField: f}] = true
+	}
+	for name, k := range v.keys {
+		e.linkFlattened(tfProp+"."+name, k, depth+1)
+	}
+}
+
+// linkExpanded records the links between the SDK model field (and its nested fields) and the terraform properties that
+// the value of the field is derived from.
+func (e *evaluator) linkExpanded(sdkField string, v *value, depth int) {
+	if v == nil || depth > maxValueDepth {
+		return
+	}
+	if sdkField != "" {
+		for _, p := range v.tfProps {
+			e.links[SDKLink{TFProp: p, SDKField: sdkField}] = true
+		}
+	}
+	for name, f := range v.fields {
+		if sdkField != "" {
+			name = sdkField + "." + name
+		}
+		e.linkExpanded(name, f, depth+1)
+	}
+}
+
+// isPackageRef tells whether the expression refers to an imported package (e.g. "utils" in "utils.String()").
+func (e *evaluator) isPackageRef(expr ast.Expr, env scope) bool {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, isVar := env[id.Name]
+	return !isVar && e.pkg.imports[id.Name]
+}
+
+// isPackageCall tells whether the call is a call to a function of the package being analyzed.
+func (e *evaluator) isPackageCall(call *ast.CallExpr) bool {
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = e.pkg.funcs[id.Name]
+	return ok
+}
+
+// resourceDataProp returns the terraform property name if the call is a method call with the property name as the first
+// argument (e.g. "d.Get("name")").
+func resourceDataProp(call *ast.CallExpr) (string, bool) {
+	if _, ok := call.Fun.(*ast.SelectorExpr); !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	return stringLit(call.Args[0])
+}
+
+func isResourceDataGet(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !resourceDataGetters[sel.Sel.Name] {
+		return false
+	}
+	_, ok = resourceDataProp(call)
+	return ok
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// typeName returns the name of a (possibly qualified or pointer) named type, e.g. "VirtualNetwork" for "*network.VirtualNetwork".
+func typeName(typ ast.Expr) string {
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		return typ.Name
+	case *ast.SelectorExpr:
+		return typ.Sel.Name
+	case *ast.StarExpr:
+		return typeName(typ.X)
+	}
+	return ""
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,142 @@
+package providersrc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+func TestResourceFuncs(t *testing.T) {
+	pkg, err := ParseDir(filepath.Join("testdata", "provider", "network"))
+	require.NoError(t, err)
+
+	funcs, err := pkg.ResourceFuncs("azurerm_virtual_network")
+	require.NoError(t, err)
+	require.Equal(t, []string{"resourceArmVirtualNetworkCreateUpdate", "resourceArmVirtualNetworkRead"}, funcs)
+
+	_, err = pkg.ResourceFuncs("azurerm_subnet")
+	require.Error(t, err)
+}
+
+func TestInferSDKLinks(t *testing.T) {
+	pkg, err := ParseDir(filepath.Join("testdata", "provider", "network"))
+	require.NoError(t, err)
+
+	links, err := pkg.InferSDKLinks("azurerm_virtual_network", "VirtualNetwork")
+	require.NoError(t, err)
+
+	expect := []SDKLink{
+		{"address_space", "VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes"},
+		{"ddos_protection_plan.enable", "VirtualNetworkPropertiesFormat.EnableDdosProtection"},
+		{"ddos_protection_plan.id", "VirtualNetworkPropertiesFormat.DdosProtectionPlan.ID"},
+		{"dns_servers", "VirtualNetworkPropertiesFormat.DhcpOptions.DNSServers"},
+		{"location", "Location"},
+		{"name", "Name"},
+		{"resource_group_name", "ResourceGroup"},
+		{"subnet.address_prefix", "VirtualNetworkPropertiesFormat.Subnets.SubnetPropertiesFormat.AddressPrefix"},
+		{"subnet.id", "VirtualNetworkPropertiesFormat.Subnets.ID"},
+		{"subnet.name", "VirtualNetworkPropertiesFormat.Subnets.Name"},
+		{"tags", "Tags"},
+	}
+	require.Equal(t, expect, links)
+}
+
+func TestInferLinks(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger")
+
+	tfschema := core.TFSchema{
+		Name:        "azurerm_virtual_network",
+		SwaggerSpec: "virtualNetwork.json",
+		PropertyLinks: map[string][]core.SwaggerLink{
+			"name":                        {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("VirtualNetwork:name")}},
+			"resource_group_name":         {},
+			"location":                    {},
+			"address_space":               {},
+			"dns_servers":                 {},
+			"ddos_protection_plan.id":     {},
+			"ddos_protection_plan.enable": {},
+			"subnet.id":                   {},
+			"subnet.name":                 {},
+			"subnet.address_prefix":       {},
+			"tags":                        {},
+		},
+	}
+
+	swgschema, err := core.NewSWGSchema(specBasePath, "virtualNetwork.json", "VirtualNetwork")
+	require.NoError(t, err)
+
+	suggestions, err := InferLinks(filepath.Join(pwd, "testdata", "provider", "network"), tfschema, swgschema, 10)
+	require.NoError(t, err)
+
+	var actual []string
+	for _, suggestion := range suggestions {
+		require.Equal(t, float64(1), suggestion.Score)
+		require.Equal(t, sdkLinkOrigin, suggestion.Origin)
+		actual = append(actual, suggestion.TFProp+" -> "+suggestion.SWGProp.String())
+	}
+	expect := []string{
+		"address_space -> VirtualNetwork:properties.addressSpace.addressPrefixes",
+		"ddos_protection_plan.enable -> VirtualNetwork:properties.enableDdosProtection",
+		"ddos_protection_plan.id -> VirtualNetwork:properties.ddosProtectionPlan.id",
+		"dns_servers -> VirtualNetwork:properties.dhcpOptions.dnsServers",
+		"location -> VirtualNetwork:location",
+		"subnet.address_prefix -> VirtualNetwork:properties.subnets.properties.addressPrefix",
+		"subnet.id -> VirtualNetwork:properties.subnets.id",
+		"subnet.name -> VirtualNetwork:properties.subnets.name",
+		"tags -> VirtualNetwork:tags",
+	}
+	require.Equal(t, expect, actual)
+
+	draft := tfschema.ApplyLinkSuggestions(suggestions)
+	require.Equal(t, []core.SwaggerLink{
+		{
+			SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("VirtualNetwork:properties.subnets.properties.addressPrefix"),
+			Note:       "inferred from provider source, to be confirmed",
+		},
+	}, draft.PropertyLinks["subnet.address_prefix"])
+	require.Empty(t, draft.PropertyLinks["resource_group_name"])
+}
+
+func TestSwaggerTreeResolve(t *testing.T) {
+	swgschema := &core.SWGSchema{
+		Name: "Foo",
+		Properties: core.SWGSchemaProperties{
+			"properties.dnsServers":  nil,
+			"properties.ipConfig.id": nil,
+			"propertiesOnly":         nil,
+			"variant{A}.name":        nil,
+		},
+	}
+	tree := newSwaggerTree(swgschema)
+
+	cases := []struct {
+		sdkField string
+		expect   string
+		ok       bool
+	}{
+		{"FooPropertiesFormat.DNSServers", "properties.dnsServers", true},
+		{"FooProperties.IPConfig", "properties.ipConfig", true},
+		{"FooProperties.IPConfig.ID", "properties.ipConfig.id", true},
+		{"PropertiesOnly", "propertiesOnly", true},
+		{"Variant.Name", "variant{A}.name", true},
+		{"FooProperties.NotExist", "", false},
+		{"Bar", "", false},
+	}
+	for idx, c := range cases {
+		addr, ok := tree.resolve(strings.Split(c.sdkField, "."))
+		require.Equal(t, c.ok, ok, idx)
+		if ok {
+			require.Equal(t, c.expect, addr.String(), idx)
+		}
+	}
+}
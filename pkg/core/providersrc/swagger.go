@@ -0,0 +1,117 @@
+package providersrc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core"
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// swaggerPropertyEnvelope is the swagger property that is flattened into the SDK model via "x-ms-client-flatten", whose SDK
+// field is named after its schema (e.g. "VirtualNetworkPropertiesFormat").
+const swaggerPropertyEnvelope = "properties"
+
+// sdkLinkOrigin annotates the link suggestions inferred from the provider source code.
+const sdkLinkOrigin = "provider source"
+
+// InferLinks infers the swagger properties linked by the terraform properties of the TFSchema that are unmapped yet, by
+// analyzing the Go package of the provider in providerSrcDir, which implements the resource. The root swagger schema is
+// expected to be the SDK model of the resource (e.g. "VirtualNetwork").
+// The SWGSchema will be expanded during the process.
+func InferLinks(providerSrcDir string, tfschema core.TFSchema, swgschema *core.SWGSchema, maxDepth int) ([]core.LinkSuggestion, error) {
+	pkg, err := ParseDir(providerSrcDir)
+	if err != nil {
+		return nil, err
+	}
+	sdkLinks, err := pkg.InferSDKLinks(tfschema.Name, swgschema.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := swgschema.ExpandAll(maxDepth); err != nil {
+		return nil, fmt.Errorf("expanding swagger schema %s: %w", swgschema.Name, err)
+	}
+
+	tree := newSwaggerTree(swgschema)
+	seen := map[string]bool{}
+	var suggestions []core.LinkSuggestion
+	for _, link := range sdkLinks {
+		if _, ok := tfschema.PropertyLinks[link.TFProp]; !ok || tfschema.PropertyLinks.Status(link.TFProp) != core.TFPropertyUnmapped {
+			continue
+		}
+		raddr, ok := tree.resolve(strings.Split(link.SDKField, "."))
+		if !ok {
+			continue
+		}
+		swgPropAddr := propertyaddr.MustNewSwaggerPropertyAddr(swgschema.Name, raddr.String())
+		if seen[link.TFProp+" "+swgPropAddr.String()] {
+			continue
+		}
+		seen[link.TFProp+" "+swgPropAddr.String()] = true
+		suggestions = append(suggestions, core.LinkSuggestion{
+			TFProp:  link.TFProp,
+			SWGProp: swgPropAddr,
+			Score:   1,
+			Origin:  sdkLinkOrigin,
+		})
+	}
+	return suggestions, nil
+}
+
+// swaggerTree indexes the properties of an expanded SWGSchema by their parent properties.
+type swaggerTree map[string][]propertyaddr.SwaggerPropertyAddrSegment
+
+func newSwaggerTree(swgschema *core.SWGSchema) swaggerTree {
+	tree := swaggerTree{}
+	seen := map[string]bool{}
+	for raddr := range swgschema.Properties {
+		addr := propertyaddr.MustNewSwaggerPropertyAddr(swgschema.Name, raddr).PropertyAddr
+		for i := range addr {
+			child := addr[:i+1].String()
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			parent := addr[:i].String()
+			tree[parent] = append(tree[parent], addr[i])
+		}
+	}
+	for _, children := range tree {
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].String() < children[j].String()
+		})
+	}
+	return tree
+}
+
+// resolve resolves the SDK model field path to the swagger property. The SDK field names are matched to the swagger property
+// names case insensitively, as the SDK field names are the exported form of the swagger property names (e.g. "DNSServers"
+// for "dnsServers").
+func (tree swaggerTree) resolve(sdkField []string) (propertyaddr.SwaggerRelPropertyAddr, bool) {
+	var addr propertyaddr.SwaggerRelPropertyAddr
+	for _, name := range sdkField {
+		children := tree[addr.String()]
+		var (
+			matched  *propertyaddr.SwaggerPropertyAddrSegment
+			envelope *propertyaddr.SwaggerPropertyAddrSegment
+		)
+		for i := range children {
+			if strings.EqualFold(children[i].Name(), name) {
+				matched = &children[i]
+				break
+			}
+			if children[i].Name() == swaggerPropertyEnvelope && envelope == nil {
+				envelope = &children[i]
+			}
+		}
+		if matched == nil && envelope != nil && strings.Contains(name, "Properties") {
+			matched = envelope
+		}
+		if matched == nil {
+			return nil, false
+		}
+		addr = append(addr, *matched)
+	}
+	return addr, len(addr) != 0
+}
@@ -0,0 +1,201 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/azure"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/clients"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/internal/tags"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func resourceArmVirtualNetwork() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmVirtualNetworkCreateUpdate,
+		Read:   resourceArmVirtualNetworkRead,
+		Update: resourceArmVirtualNetworkCreateUpdate,
+		Delete: resourceArmVirtualNetworkDelete,
+
+		Schema: map[string]*schema.Schema{},
+	}
+}
+
+func resourceArmVirtualNetworkCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Network.VnetClient
+	ctx := meta.(*clients.Client).StopContext
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+	location := azure.NormalizeLocation(d.Get("location").(string))
+	t := d.Get("tags").(map[string]interface{})
+
+	vnetProperties, err := expandVirtualNetworkProperties(d)
+	if err != nil {
+		return err
+	}
+
+	vnet := network.VirtualNetwork{
+		Name:                           &name,
+		Location:                       &location,
+		VirtualNetworkPropertiesFormat: vnetProperties,
+		Tags:                           tags.Expand(t),
+	}
+
+	future, err := client.CreateOrUpdate(ctx, resGroup, name, vnet)
+	if err != nil {
+		return fmt.Errorf("creating/updating Virtual Network %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+	if err = future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return err
+	}
+
+	return resourceArmVirtualNetworkRead(d, meta)
+}
+
+func resourceArmVirtualNetworkRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*clients.Client).Network.VnetClient
+	ctx := meta.(*clients.Client).StopContext
+
+	id, err := azure.ParseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+
+	resp, err := client.Get(ctx, resGroup, id.Path["virtualNetworks"], "")
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+	if location := resp.Location; location != nil {
+		d.Set("location", azure.NormalizeLocation(*location))
+	}
+
+	if props := resp.VirtualNetworkPropertiesFormat; props != nil {
+		if space := props.AddressSpace; space != nil {
+			d.Set("address_space", utils.FlattenStringSlice(space.AddressPrefixes))
+		}
+
+		if err := d.Set("ddos_protection_plan", flattenVirtualNetworkDDoSProtectionPlan(props)); err != nil {
+			return fmt.Errorf("setting `ddos_protection_plan`: %+v", err)
+		}
+
+		subnets := flattenVirtualNetworkSubnets(props.Subnets)
+		if err := d.Set("subnet", subnets); err != nil {
+			return fmt.Errorf("setting `subnets`: %+v", err)
+		}
+	}
+
+	return tags.FlattenAndSet(d, resp.Tags)
+}
+
+func resourceArmVirtualNetworkDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func expandVirtualNetworkProperties(d *schema.ResourceData) (*network.VirtualNetworkPropertiesFormat, error) {
+	subnets := make([]network.Subnet, 0)
+	for _, subnetRaw := range d.Get("subnet").(*schema.Set).List() {
+		subnet := subnetRaw.(map[string]interface{})
+
+		name := subnet["name"].(string)
+		prefix := subnet["address_prefix"].(string)
+
+		subnetObj := network.Subnet{
+			Name: &name,
+			SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+				AddressPrefix: &prefix,
+			},
+		}
+		subnets = append(subnets, subnetObj)
+	}
+
+	properties := &network.VirtualNetworkPropertiesFormat{
+		AddressSpace: &network.AddressSpace{
+			AddressPrefixes: utils.ExpandStringSlice(d.Get("address_space").([]interface{})),
+		},
+		DhcpOptions: &network.DhcpOptions{
+			DNSServers: utils.ExpandStringSlice(d.Get("dns_servers").([]interface{})),
+		},
+		Subnets: &subnets,
+	}
+
+	if v, ok := d.GetOk("ddos_protection_plan"); ok {
+		rawList := v.([]interface{})
+
+		var ddosPPlan map[string]interface{}
+		if len(rawList) > 0 {
+			ddosPPlan = rawList[0].(map[string]interface{})
+		}
+
+		if v, ok := ddosPPlan["id"]; ok {
+			id := v.(string)
+			properties.DdosProtectionPlan = &network.SubResource{
+				ID: &id,
+			}
+		}
+
+		if v, ok := ddosPPlan["enable"]; ok {
+			enable := v.(bool)
+			properties.EnableDdosProtection = &enable
+		}
+	}
+
+	return properties, nil
+}
+
+func flattenVirtualNetworkDDoSProtectionPlan(input *network.VirtualNetworkPropertiesFormat) []interface{} {
+	if input == nil || input.DdosProtectionPlan == nil || input.DdosProtectionPlan.ID == nil {
+		return []interface{}{}
+	}
+
+	enabled := false
+	if input.EnableDdosProtection != nil {
+		enabled = *input.EnableDdosProtection
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"id":     *input.DdosProtectionPlan.ID,
+			"enable": enabled,
+		},
+	}
+}
+
+func flattenVirtualNetworkSubnets(input *[]network.Subnet) *schema.Set {
+	results := &schema.Set{
+		F: resourceAzureSubnetHash,
+	}
+
+	if subnets := input; subnets != nil {
+		for _, subnet := range *input {
+			output := make(map[string]interface{})
+
+			if id := subnet.ID; id != nil {
+				output["id"] = *id
+			}
+
+			if name := subnet.Name; name != nil {
+				output["name"] = *name
+			}
+
+			if props := subnet.SubnetPropertiesFormat; props != nil {
+				if prefix := props.AddressPrefix; prefix != nil {
+					output["address_prefix"] = *prefix
+				}
+			}
+
+			results.Add(output)
+		}
+	}
+
+	return results
+}
+
+func resourceAzureSubnetHash(v interface{}) int {
+	return 0
+}
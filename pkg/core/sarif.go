@@ -0,0 +1,219 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SARIFVersion   = "2.1.0"
+	SARIFSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// SARIFSourceRootBaseID is the base of the relative artifact URIs, which is the base directory of the SARIF log.
+	SARIFSourceRootBaseID = "%SRCROOT%"
+
+	sarifToolName           = "terraform-provider-azurerm-insight"
+	sarifToolInformationURI = "https://github.com/magodo/terraform-provider-azurerm-insight"
+)
+
+// SARIFLevel is the level of a SARIF result.
+type SARIFLevel string
+
+const (
+	SARIFLevelError   SARIFLevel = "error"
+	SARIFLevelWarning SARIFLevel = "warning"
+)
+
+// DiagnosticRuleInfo describes a DiagnosticRule, which is reported as a SARIF rule.
+type DiagnosticRuleInfo struct {
+	Rule        DiagnosticRule
+	Description string
+	Level       SARIFLevel
+}
+
+// DiagnosticRules are all the DiagnosticRules, in the order of the SARIF rules.
+var DiagnosticRules = []DiagnosticRuleInfo{
+	{Rule: RuleTFSchemaInvalid, Description: "The terraform schema file fails to be decoded, or is invalid.", Level: SARIFLevelError},
+	{Rule: RuleTFSchemaLink, Description: "The terraform property fails to be linked to the swagger property.", Level: SARIFLevelError},
+	{Rule: RuleTFSchemaProvider, Description: "The terraform resource is not found in the provider schema.", Level: SARIFLevelError},
+	{Rule: RuleTFSchemaType, Description: "The terraform property is linked to a swagger property of incompatible type.", Level: SARIFLevelError},
+	{Rule: RuleGrantInvalid, Description: "The grant file fails to be decoded, or is invalid.", Level: SARIFLevelError},
+	{Rule: RuleGrantUnapplicable, Description: "The grant can't be applied onto the swagger schemas.", Level: SARIFLevelError},
+	{Rule: RuleGrantStaleInherited, Description: "The inherited property grant no longer applies to the newer API version.", Level: SARIFLevelWarning},
+	{Rule: RuleCoverage, Description: "The coverage of the swagger schema fails to be calculated.", Level: SARIFLevelError},
+	{Rule: RuleUncoveredRequiredProperty, Description: "The required swagger property is neither linked nor granted, while its parent is present (i.e. the parent is required, or any property under it is linked).", Level: SARIFLevelWarning},
+}
+
+// SARIFLog is a SARIF 2.1.0 log, which only contains the parts used to report the Diagnostics.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool               SARIFTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]SARIFArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []SARIFResult                    `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFToolComponent `json:"driver"`
+}
+
+type SARIFToolComponent struct {
+	Name           string                     `json:"name"`
+	InformationURI string                     `json:"informationUri,omitempty"`
+	Rules          []SARIFReportingDescriptor `json:"rules"`
+}
+
+type SARIFReportingDescriptor struct {
+	ID                   string                      `json:"id"`
+	ShortDescription     SARIFMessage                `json:"shortDescription"`
+	DefaultConfiguration SARIFReportingConfiguration `json:"defaultConfiguration"`
+}
+
+type SARIFReportingConfiguration struct {
+	Level SARIFLevel `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	RuleIndex *int            `json:"ruleIndex,omitempty"`
+	Level     SARIFLevel      `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SARIFLogicalLocation records the JSON pointer of the offending entry inside the file.
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// NewSARIFLog builds a SARIF log from the Diagnostics, each Diagnostic is a result of the rule of the Diagnostic.
+// The file of each Diagnostic is reported relative to the baseDir (e.g. the root of the repository), unless it is out of
+// the baseDir, in which case it is reported as an absolute file URI. The Diagnostic without a rule is reported as an error
+// without a rule ID.
+func NewSARIFLog(diags Diagnostics, baseDir string) (*SARIFLog, error) {
+	absBaseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	driver := SARIFToolComponent{
+		Name:           sarifToolName,
+		InformationURI: sarifToolInformationURI,
+		Rules:          []SARIFReportingDescriptor{},
+	}
+	ruleIndexes := map[DiagnosticRule]int{}
+	ruleLevels := map[DiagnosticRule]SARIFLevel{}
+	for idx, info := range DiagnosticRules {
+		driver.Rules = append(driver.Rules, SARIFReportingDescriptor{
+			ID:                   string(info.Rule),
+			ShortDescription:     SARIFMessage{Text: info.Description},
+			DefaultConfiguration: SARIFReportingConfiguration{Level: info.Level},
+		})
+		ruleIndexes[info.Rule] = idx
+		ruleLevels[info.Rule] = info.Level
+	}
+
+	results := []SARIFResult{}
+	for _, diag := range diags {
+		result := SARIFResult{
+			Level:   SARIFLevelError,
+			Message: SARIFMessage{Text: diag.Err.Error()},
+		}
+		if idx, ok := ruleIndexes[diag.Rule]; ok {
+			idx := idx
+			result.RuleID, result.RuleIndex, result.Level = string(diag.Rule), &idx, ruleLevels[diag.Rule]
+		} else if diag.Rule != "" {
+			result.RuleID = string(diag.Rule)
+		}
+
+		var loc SARIFLocation
+		if diag.File != "" {
+			loc.PhysicalLocation = &SARIFPhysicalLocation{ArtifactLocation: sarifArtifactLocation(absBaseDir, diag.File)}
+			if diag.Line != 0 {
+				loc.PhysicalLocation.Region = &SARIFRegion{StartLine: diag.Line, StartColumn: diag.Column}
+			}
+		}
+		if diag.Pointer != "" {
+			loc.LogicalLocations = []SARIFLogicalLocation{{FullyQualifiedName: diag.Pointer, Kind: "member"}}
+		}
+		if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+			result.Locations = []SARIFLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	return &SARIFLog{
+		Schema:  SARIFSchemaURI,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{Driver: driver},
+				OriginalURIBaseIDs: map[string]SARIFArtifactLocation{
+					SARIFSourceRootBaseID: {URI: fileURI(absBaseDir) + "/"},
+				},
+				Results: results,
+			},
+		},
+	}, nil
+}
+
+// WriteJSON writes the SARIF log as indented JSON.
+func (l *SARIFLog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+func sarifArtifactLocation(absBaseDir, file string) SARIFArtifactLocation {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return SARIFArtifactLocation{URI: filepath.ToSlash(file)}
+	}
+	rel, err := filepath.Rel(absBaseDir, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return SARIFArtifactLocation{URI: fileURI(absFile)}
+	}
+	return SARIFArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(rel)}).String(), URIBaseID: SARIFSourceRootBaseID}
+}
+
+// fileURI returns the file URI of the absolute path, without the trailing slash.
+func fileURI(absPath string) string {
+	p := filepath.ToSlash(absPath)
+	if !strings.HasPrefix(p, "/") {
+		// e.g. the Windows path "C:/foo"
+		p = "/" + p
+	}
+	return strings.TrimSuffix((&url.URL{Scheme: "file", Path: p}).String(), "/")
+}
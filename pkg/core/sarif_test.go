@@ -0,0 +1,145 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUncoveredRequiredProperties(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseDir := filepath.Join(pwd, "testdata", "swagger_required")
+	tfSchemaDir := filepath.Join(baseDir, "terraform_schema")
+
+	swgschemas, err := NewSWGSchemasFromTerraformSchema(baseDir, tfSchemaDir, filepath.Join(baseDir, "swagger_grants"), SWGGrantOptions{})
+	require.NoError(t, err)
+	require.Contains(t, swgschemas.SWGGrant.Schemas, NewSWGSchemaAddr("foo.json", "Foo"))
+
+	schema := swgschemas.Get(NewSWGSchemaAddr("foo.json", "Foo"))
+	require.NotNil(t, schema)
+	// Expand the "other" envelope, which nothing links to, so that its required properties are not reported.
+	require.NoError(t, schema.ExpandAll(2))
+	required := map[string]bool{}
+	for propAddr, prop := range schema.Properties {
+		required[propAddr] = prop.Required()
+	}
+	require.Equal(t, map[string]bool{
+		"id":            true,
+		"location":      true,
+		"tags":          false,
+		"properties.p1": true,
+		"properties.p2": true,
+		"properties.p3": true,
+		"properties.p4": false,
+		"extra.p1":      true,
+		"extra.p2":      true,
+		"extra.p3":      true,
+		"extra.p4":      false,
+		"other.p1":      true,
+		"other.p2":      true,
+		"other.p3":      true,
+		"other.p4":      false,
+	}, required)
+
+	files, err := LoadTFSchemaFiles(tfSchemaDir)
+	require.NoError(t, err)
+
+	type finding struct {
+		file    string
+		pointer string
+		line    int
+		rule    DiagnosticRule
+		msg     string
+	}
+	actual := []finding{}
+	for _, diag := range FindUncoveredRequiredProperties(swgschemas, files) {
+		actual = append(actual, finding{diag.File, diag.Pointer, diag.Line, diag.Rule, diag.Err.Error()})
+	}
+	file := filepath.Join(tfSchemaDir, "azurerm_foo.json")
+	require.Equal(t, []finding{
+		{file, "/PropertyLinks", 4, RuleUncoveredRequiredProperty, "required swagger property Foo:extra.p2 (foo.json) is neither linked nor granted"},
+		{file, "/PropertyLinks", 4, RuleUncoveredRequiredProperty, "required swagger property Foo:extra.p3 (foo.json) is neither linked nor granted"},
+		{file, "/PropertyLinks", 4, RuleUncoveredRequiredProperty, "required swagger property Foo:location (foo.json) is neither linked nor granted"},
+		{file, "/PropertyLinks", 4, RuleUncoveredRequiredProperty, "required swagger property Foo:properties.p3 (foo.json) is neither linked nor granted"},
+	}, actual)
+}
+
+func TestNewSARIFLog(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := Diagnostics{
+		{
+			File:    filepath.Join(pwd, "testdata", "terraform_schema_invalid", "res1.json"),
+			Pointer: "/PropertyLinks/p2/0/prop",
+			Line:    12,
+			Column:  17,
+			Rule:    RuleTFSchemaLink,
+			Err:     errors.New("linking swgschema: not found"),
+		},
+		{
+			File: filepath.Join(filepath.Dir(pwd), "outside.json"),
+			Rule: RuleUncoveredRequiredProperty,
+			Err:  errors.New("required swagger property Foo:location (foo.json) is neither linked nor granted"),
+		},
+		{
+			Err: errors.New("unknown problem"),
+		},
+	}
+
+	sarif, err := NewSARIFLog(diags, pwd)
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	require.NoError(t, sarif.WriteJSON(&sb))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &decoded))
+	require.Equal(t, SARIFVersion, decoded["version"])
+
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, len(DiagnosticRules))
+	require.Equal(t, fileURI(pwd)+"/", run.OriginalURIBaseIDs[SARIFSourceRootBaseID].URI)
+
+	require.Len(t, run.Results, 3)
+
+	result := run.Results[0]
+	require.Equal(t, string(RuleTFSchemaLink), result.RuleID)
+	require.Equal(t, string(RuleTFSchemaLink), run.Tool.Driver.Rules[*result.RuleIndex].ID)
+	require.Equal(t, SARIFLevelError, result.Level)
+	require.Equal(t, []SARIFLocation{
+		{
+			PhysicalLocation: &SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: "testdata/terraform_schema_invalid/res1.json", URIBaseID: SARIFSourceRootBaseID},
+				Region:           &SARIFRegion{StartLine: 12, StartColumn: 17},
+			},
+			LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: "/PropertyLinks/p2/0/prop", Kind: "member"}},
+		},
+	}, result.Locations)
+
+	result = run.Results[1]
+	require.Equal(t, string(RuleUncoveredRequiredProperty), result.RuleID)
+	require.Equal(t, SARIFLevelWarning, result.Level)
+	require.Equal(t, []SARIFLocation{
+		{
+			PhysicalLocation: &SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: fileURI(filepath.Join(filepath.Dir(pwd), "outside.json"))},
+			},
+		},
+	}, result.Locations)
+
+	result = run.Results[2]
+	require.Empty(t, result.RuleID)
+	require.Nil(t, result.RuleIndex)
+	require.Equal(t, SARIFLevelError, result.Level)
+	require.Nil(t, result.Locations)
+}
@@ -3,10 +3,8 @@ package core
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -18,26 +16,52 @@ const swaggerExtensionMSDiscriminatorValue = "x-ms-discriminator-value"
 
 type TFLink struct {
 	Prop propertyaddr.TerraformPropertyAddr
+
+	// The annotations of the link, see SwaggerLink.
+	Transform LinkTransform
+	Note      string
+}
+
+// tfLinkAnnotated is the marshalled form of an annotated TFLink.
+type tfLinkAnnotated struct {
+	Prop      propertyaddr.TerraformPropertyAddr `json:"prop"`
+	Transform LinkTransform                      `json:"transform,omitempty"`
+	Note      string                             `json:"note,omitempty"`
 }
 
 type TFLinks []TFLink
 
+// MarshalJSON marshals each TFLink into its terraform property address, unless the link is annotated, in which case
+// it is marshalled into an object containing the address and the annotations.
 func (links TFLinks) MarshalJSON() ([]byte, error) {
-	addrs := []string{}
+	out := []interface{}{}
 	for _, link := range links {
-		addrs = append(addrs, link.Prop.String())
+		if link.Transform == "" && link.Note == "" {
+			out = append(out, link.Prop.String())
+			continue
+		}
+		out = append(out, tfLinkAnnotated{Prop: link.Prop, Transform: link.Transform, Note: link.Note})
 	}
-	return json.Marshal(addrs)
+	return json.Marshal(out)
 }
 
 func (links *TFLinks) UnmarshalJSON(b []byte) error {
-	var addrs []string
-	if err := json.Unmarshal(b, &addrs); err != nil {
+	var rawLinks []json.RawMessage
+	if err := json.Unmarshal(b, &rawLinks); err != nil {
 		return err
 	}
 	*links = []TFLink{}
-	for _, addr := range addrs {
-		*links = append(*links, TFLink{*propertyaddr.ParseTerraformPropertyAddr(addr)})
+	for _, rawLink := range rawLinks {
+		var addr string
+		if err := json.Unmarshal(rawLink, &addr); err == nil {
+			*links = append(*links, TFLink{Prop: *propertyaddr.ParseTerraformPropertyAddr(addr)})
+			continue
+		}
+		var link tfLinkAnnotated
+		if err := json.Unmarshal(rawLink, &link); err != nil {
+			return err
+		}
+		*links = append(*links, TFLink{Prop: link.Prop, Transform: link.Transform, Note: link.Note})
 	}
 	return nil
 }
@@ -47,8 +71,18 @@ type SWGSchemaProperty struct {
 	TFLinks TFLinks `json:",omitempty"`
 
 	// Whether this property is granted to be not to implement in Terraform
-	IsGranted    bool   `json:",omitempty"`
-	GrantComment string `json:",omitempty"`
+	IsGranted     bool          `json:",omitempty"`
+	GrantComment  string        `json:",omitempty"`
+	GrantCategory GrantCategory `json:",omitempty"`
+	GrantIssue    string        `json:",omitempty"`
+	GrantOwner    string        `json:",omitempty"`
+	GrantReviewBy string        `json:",omitempty"`
+
+	// The grant file (relative to the grant base directory) where the grant comes from.
+	GrantSource string `json:",omitempty"`
+
+	// The address pattern of the pattern grant that grants this property, empty if the property is granted explicitly.
+	GrantPattern string `json:",omitempty"`
 
 	// The schemas of this swagger schemas property
 	schema openapispec.Schema
@@ -58,6 +92,76 @@ type SWGSchemaProperty struct {
 
 	// The URI of the schema file
 	swaggerURL string
+
+	// The amount of the consecutive levels, from this property up to its ancestors, that are required by their parent schemas
+	// (e.g. 2 for "properties.sku.name", if both "name" and "sku" are required, while "properties" is not).
+	requiredDepth int
+
+	// Whether any ancestor of this property is read only, which makes this property read only as well.
+	ancestorReadOnly bool
+}
+
+// GrantInfo returns the metadata of the grant, which is only meaningful when the property is granted.
+func (p *SWGSchemaProperty) GrantInfo() GrantInfo {
+	return GrantInfo{
+		Comment:  p.GrantComment,
+		Category: p.GrantCategory,
+		Issue:    p.GrantIssue,
+		Owner:    p.GrantOwner,
+		ReviewBy: p.GrantReviewBy,
+	}
+}
+
+// Description returns the description of the property in the swagger spec, which is empty if the property is loaded from
+// the SWGSchemaReport.
+func (p *SWGSchemaProperty) Description() string {
+	return p.schema.Description
+}
+
+// Type returns the type of the property in the swagger spec (e.g. "string"), which is empty if the property is loaded from
+// the SWGSchemaReport.
+func (p *SWGSchemaProperty) Type() string {
+	return strings.Join(p.schema.Type, "|")
+}
+
+// ReadOnly tells whether the property is read only in the swagger spec, which is false if the property is loaded from
+// the SWGSchemaReport.
+func (p *SWGSchemaProperty) ReadOnly() bool {
+	return p.schema.ReadOnly
+}
+
+// writable tells whether the property is writable, i.e. neither itself nor any of its ancestors is read only.
+func (p *SWGSchemaProperty) writable() bool {
+	return !p.schema.ReadOnly && !p.ancestorReadOnly
+}
+
+// Required tells whether the property is required in the swagger spec relative to its parent, i.e. it is listed in the
+// "required" of its parent schema, so it is required whenever its parent is present. It is false if the property is loaded
+// from the SWGSchemaReport.
+func (p *SWGSchemaProperty) Required() bool {
+	return p.requiredDepth > 0
+}
+
+// requiredAncestor returns the address of the closest ancestor of the property, that the property is required relative to
+// (e.g. "properties" of "properties.sku.name", if both "name" and "sku" are required, while "properties" is not). It is
+// the root (i.e. "") if the property is required all the way up to the root.
+func (p *SWGSchemaProperty) requiredAncestor(addr propertyaddr.SwaggerPropertyAddr) string {
+	n := len(addr.PropertyAddr) - p.requiredDepth
+	if n <= 0 {
+		return ""
+	}
+	return addr.PropertyAddr[:n].String()
+}
+
+func (p *SWGSchemaProperty) grant(info GrantInfo, source, pattern string) {
+	p.IsGranted = true
+	p.GrantSource = source
+	p.GrantPattern = pattern
+	p.GrantComment = info.Comment
+	p.GrantCategory = info.Category
+	p.GrantIssue = info.Issue
+	p.GrantOwner = info.Owner
+	p.GrantReviewBy = info.ReviewBy
 }
 
 func NewSWGSchemaProperty(schema openapispec.Schema, tflinks []TFLink, resolvedRefs map[string]interface{}, schemaURI string) *SWGSchemaProperty {
@@ -99,14 +203,43 @@ type SWGSchema struct {
 	Properties     SWGSchemaProperties
 
 	// Whether this property is granted to be not to implement in Terraform
-	IsGranted    bool   `json:",omitempty"`
-	GrantComment string `json:",omitempty"`
+	IsGranted     bool          `json:",omitempty"`
+	GrantComment  string        `json:",omitempty"`
+	GrantCategory GrantCategory `json:",omitempty"`
+	GrantIssue    string        `json:",omitempty"`
+	GrantOwner    string        `json:",omitempty"`
+	GrantReviewBy string        `json:",omitempty"`
+
+	// The grant file (relative to the grant base directory) where the grant comes from.
+	GrantSource string `json:",omitempty"`
 
 	swaggerURL    string
 	swagger       *openapispec.Swagger
 	coverageStore SWGPropertyCoverageStore
 }
 
+// GrantInfo returns the metadata of the grant, which is only meaningful when the schema is granted.
+func (s *SWGSchema) GrantInfo() GrantInfo {
+	return GrantInfo{
+		Comment:  s.GrantComment,
+		Category: s.GrantCategory,
+		Issue:    s.GrantIssue,
+		Owner:    s.GrantOwner,
+		ReviewBy: s.GrantReviewBy,
+	}
+}
+
+// Grant grants the schema as a whole, the source is the grant file (relative to the grant base directory) where the grant comes from.
+func (s *SWGSchema) Grant(info GrantInfo, source string) {
+	s.IsGranted = true
+	s.GrantSource = source
+	s.GrantComment = info.Comment
+	s.GrantCategory = info.Category
+	s.GrantIssue = info.Issue
+	s.GrantOwner = info.Owner
+	s.GrantReviewBy = info.ReviewBy
+}
+
 func NewSWGSchema(swaggerBaseURL, swaggerRelPath string, schemaName string) (*SWGSchema, error) {
 	swaggerURI := swaggerBaseURL + "/" + swaggerRelPath
 	swagger, err := LoadSwagger(swaggerURI)
@@ -193,6 +326,9 @@ func (s *SWGSchema) ExpandPropertyOneLevelDeep(addr propertyaddr.SwaggerProperty
 		return fmt.Errorf("property %s does not exist in SWGSchema %s (%s)", addr, s.Name, s.swaggerURL)
 	}
 
+	// The read only of the property is taken before it is dereferenced, as it is a sibling of the "$ref".
+	readOnly := !prop.writable()
+
 	isCyclic, err := s.expandRefPropertyInPlace(prop)
 	if err != nil {
 		return fmt.Errorf("dereferencing property %s in SWGSchema %s (%s): %w", addr, s.Name, s.swaggerURL, err)
@@ -217,6 +353,9 @@ func (s *SWGSchema) ExpandPropertyOneLevelDeep(addr propertyaddr.SwaggerProperty
 
 	levelSWGProperties.Add(directTopSWGProperties)
 	levelSWGProperties.Add(allOfSWGProperties)
+	for _, p := range levelSWGProperties {
+		p.ancestorReadOnly = readOnly
+	}
 
 	discriminator := prop.schema.Discriminator
 	if discriminator == "" {
@@ -269,6 +408,8 @@ func (s *SWGSchema) ExpandPropertyOneLevelDeep(addr propertyaddr.SwaggerProperty
 			resolvedRefs[normalizePaths("#/definitions/"+dscSchemaName, s.swaggerURL)] = struct{}{}
 
 			p := NewSWGSchemaProperty(s.swagger.Definitions[dscSchemaName], prop.TFLinks, resolvedRefs, prop.swaggerURL)
+			p.requiredDepth = prop.requiredDepth
+			p.ancestorReadOnly = readOnly
 			addr := addr.AsVariant(variant)
 			s.addProperty(addr, *p)
 			continue outLoop
@@ -282,14 +423,26 @@ func (s *SWGSchema) ExpandPropertyOneLevelDeep(addr propertyaddr.SwaggerProperty
 // Especially, if the property is an array to object, it will expand to the sub-properties of the object item instead.
 func (s *SWGSchema) expandSubProperties(addr propertyaddr.SwaggerPropertyAddr, prop *SWGSchemaProperty) SWGSchemaProperties {
 	output := NewSWGSchemaProperties()
-	var properties map[string]openapispec.Schema
+	var (
+		properties map[string]openapispec.Schema
+		required   []string
+	)
 	if prop.schema.Items != nil {
 		properties = prop.schema.Items.Schema.Properties
+		required = prop.schema.Items.Schema.Required
 	} else {
 		properties = prop.schema.Properties
+		required = prop.schema.Required
+	}
+	requiredSet := map[string]bool{}
+	for _, name := range required {
+		requiredSet[name] = true
 	}
 	for propK, propV := range properties {
 		p := NewSWGSchemaProperty(propV, prop.TFLinks, prop.resolvedRefs, prop.swaggerURL)
+		if requiredSet[propK] {
+			p.requiredDepth = prop.requiredDepth + 1
+		}
 		addr, _ := addr.Append(propK)
 		output[addr.PropertyAddr.String()] = p
 	}
@@ -304,6 +457,7 @@ func (s *SWGSchema) expandAllOfProperties(addr propertyaddr.SwaggerPropertyAddr,
 		// We construct a temp SWGSchemaProperty here (as it has no object/property related) to expand it into a concrete schemas.
 		// Then we will iterate that schemas's property which by concept is the top level property of this parent property.
 		tmpProp := NewSWGSchemaProperty(schema, prop.TFLinks, prop.resolvedRefs, prop.swaggerURL)
+		tmpProp.requiredDepth = prop.requiredDepth
 
 		// AllOf contains refs, then need to expand the reference properties first.
 		if tmpProp.schema.Ref.String() != "" {
@@ -390,12 +544,40 @@ func (s *SWGSchema) expandRefPropertyInPlace(prop *SWGSchemaProperty) (isCyclic
 	return s.expandRefPropertyInPlace(prop)
 }
 
+// ExpandAll expands all the properties of the SWGSchema recursively, until either the property is a leaf property,
+// or a cyclic reference is hit, or the property is maxDepth levels deep.
+func (s *SWGSchema) ExpandAll(maxDepth int) error {
+	expanded := map[string]bool{}
+	for {
+		var toExpand []string
+		for raddr := range s.Properties {
+			if expanded[raddr] {
+				continue
+			}
+			if len(propertyaddr.MustParseSwaggerPropertyAddr(raddr).PropertyAddr) >= maxDepth {
+				continue
+			}
+			toExpand = append(toExpand, raddr)
+		}
+		if len(toExpand) == 0 {
+			return nil
+		}
+		sort.Strings(toExpand)
+		for _, raddr := range toExpand {
+			expanded[raddr] = true
+			if err := s.ExpandPropertyOneLevelDeep(propertyaddr.MustNewSwaggerPropertyAddr(s.Name, raddr)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // addProperty adds a new SWGSchemaProperty to the SWGSchema.
 func (s *SWGSchema) addProperty(addr propertyaddr.SwaggerPropertyAddr, prop SWGSchemaProperty) {
 	s.Properties[addr.PropertyAddr.String()] = &prop
 }
 
-func (s *SWGSchema) AddTFLink(swgPropAddr propertyaddr.SwaggerPropertyAddr, tfPropAddr propertyaddr.TerraformPropertyAddr) error {
+func (s *SWGSchema) AddTFLink(swgPropAddr propertyaddr.SwaggerPropertyAddr, tfLink TFLink) error {
 	var isExpandToChildProperties bool
 	for raddr, prop := range s.Properties {
 		addr := propertyaddr.MustNewSwaggerPropertyAddr(s.Name, raddr)
@@ -406,12 +588,12 @@ func (s *SWGSchema) AddTFLink(swgPropAddr propertyaddr.SwaggerPropertyAddr, tfPr
 
 		if swgPropAddr.Contains(addr) {
 			isExpandToChildProperties = true
-			prop.TFLinks = append(prop.TFLinks, TFLink{Prop: tfPropAddr})
+			prop.TFLinks = append(prop.TFLinks, tfLink)
 			continue
 		}
 
 		if addr.Equals(swgPropAddr) {
-			prop.TFLinks = append(prop.TFLinks, TFLink{Prop: tfPropAddr})
+			prop.TFLinks = append(prop.TFLinks, tfLink)
 			return nil
 		}
 
@@ -419,7 +601,7 @@ func (s *SWGSchema) AddTFLink(swgPropAddr propertyaddr.SwaggerPropertyAddr, tfPr
 		if err := s.ExpandPropertyOneLevelDeep(addr); err != nil {
 			return fmt.Errorf("expanding top level property for %s: %w", addr, err)
 		}
-		return s.AddTFLink(swgPropAddr, tfPropAddr)
+		return s.AddTFLink(swgPropAddr, tfLink)
 	}
 	if isExpandToChildProperties {
 		return nil
@@ -427,6 +609,102 @@ func (s *SWGSchema) AddTFLink(swgPropAddr propertyaddr.SwaggerPropertyAddr, tfPr
 	return fmt.Errorf("property %s doesn't belong to schemas %s (%s)", swgPropAddr, s.Name, s.swaggerURL)
 }
 
+// lookupProperty looks up the property by its address, the properties along the way are expanded on demand.
+func (s *SWGSchema) lookupProperty(swgPropAddr propertyaddr.SwaggerPropertyAddr) (*SWGSchemaProperty, bool, error) {
+	expanded := map[string]bool{}
+	for {
+		if prop, ok := s.Properties[swgPropAddr.PropertyAddr.String()]; ok {
+			return prop, true, nil
+		}
+		var parent *propertyaddr.SwaggerPropertyAddr
+		for raddr := range s.Properties {
+			addr := propertyaddr.MustNewSwaggerPropertyAddr(s.Name, raddr)
+			if addr.Contains(swgPropAddr) && !expanded[raddr] {
+				parent = &addr
+				break
+			}
+		}
+		if parent == nil {
+			return nil, false, nil
+		}
+		expanded[parent.PropertyAddr.String()] = true
+		if err := s.ExpandPropertyOneLevelDeep(*parent); err != nil {
+			return nil, false, fmt.Errorf("expanding property %s: %w", parent, err)
+		}
+	}
+}
+
+// FindPropertyDescription finds the description of the property by its relative address in the swagger spec, the properties
+// along the way are expanded on demand. It returns an empty string if the property doesn't exist, or has no description.
+func (s *SWGSchema) FindPropertyDescription(propertyAddr string) (string, error) {
+	addr, err := propertyaddr.NewSwaggerPropertyAddr(s.Name, propertyAddr)
+	if err != nil {
+		return "", err
+	}
+	prop, ok, err := s.lookupProperty(addr)
+	if err != nil || !ok {
+		return "", err
+	}
+	if prop.schema.Description == "" {
+		// The description might reside in the referred schema.
+		if _, err := s.expandRefPropertyInPlace(prop); err != nil {
+			return "", err
+		}
+	}
+	return prop.schema.Description, nil
+}
+
+// lookupProperties looks up the property by its relative address, the properties along the way are expanded on demand.
+// If the property has been expanded, its expanded properties are returned instead. It returns nothing if the property doesn't exist.
+func (s *SWGSchema) lookupProperties(propertyAddr string) ([]*SWGSchemaProperty, error) {
+	addr, err := propertyaddr.NewSwaggerPropertyAddr(s.Name, propertyAddr)
+	if err != nil {
+		return nil, err
+	}
+	prop, ok, err := s.lookupProperty(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return []*SWGSchemaProperty{prop}, nil
+	}
+	var props []*SWGSchemaProperty
+	for raddr, prop := range s.Properties {
+		if addr.Contains(propertyaddr.MustNewSwaggerPropertyAddr(s.Name, raddr)) {
+			props = append(props, prop)
+		}
+	}
+	return props, nil
+}
+
+// propertyShape describes the shape of the property, i.e. its type, format, and the names of its direct properties.
+// The property is dereferenced in place beforehand, so that the shapes of the properties are comparable regardless of
+// whether they are expanded.
+func (s *SWGSchema) propertyShape(prop *SWGSchemaProperty) (string, error) {
+	if _, err := s.expandRefPropertyInPlace(prop); err != nil {
+		return "", err
+	}
+	schema := prop.schema
+	shape := fmt.Sprintf("%s(%s)", strings.Join(schema.Type, "|"), schema.Format)
+	if schema.Items != nil && schema.Items.Schema != nil {
+		item := schema.Items.Schema
+		if item.Ref.String() != "" {
+			shape += fmt.Sprintf("[%s]", item.Ref.String()[strings.LastIndex(item.Ref.String(), "/")+1:])
+		} else {
+			shape += fmt.Sprintf("[%s(%s)]", strings.Join(item.Type, "|"), item.Format)
+		}
+	}
+	if len(schema.Properties) != 0 {
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		shape += "{" + strings.Join(names, ",") + "}"
+	}
+	return shape, nil
+}
+
 // CalcCoverage calculates the property coverage (<=1) of the schema/property, and fill in the SWGSchema.
 // Those granted properties are not counted during the calculation.
 func (s *SWGSchema) CalcCoverage() error {
@@ -464,12 +742,27 @@ func (addr SWGSchemaAddr) SchemaName() string {
 	return strings.Split(string(addr), swgSchemaAddrSep)[1]
 }
 
+// ResourceProvider returns the resource provider directory of the swagger schema, which is the first segment of the swagger
+// relative path (e.g. "network").
+func (addr SWGSchemaAddr) ResourceProvider() string {
+	return strings.Split(filepath.ToSlash(addr.SwaggerRelPath()), "/")[0]
+}
+
 // SWGSchemas caches the SWGSchema using swagger + schemas as key.
 // During each link operation from terraform schemas to swagger schemas, it will manipulate one of
 // the SWGSchema. Afterwards, this type contains all the mapping info from swagger to terraform.
 type SWGSchemas struct {
 	sync.Mutex
 	m map[SWGSchemaAddr]*SWGSchema
+
+	// StaleInheritedGrants records the inherited property grants that no longer apply to the newer API version, either the
+	// property no longer exists, or its shape has changed. They are skipped and meant to be reported as warnings, rather than
+	// failing the whole knowledge base.
+	StaleInheritedGrants Diagnostics
+
+	// SWGGrant is the grant loaded from the swagger grant directory that is granted onto the schemas, which is empty if there
+	// is no swagger grant directory. It is kept so that the callers don't load the grant directory twice.
+	SWGGrant SWGGrant
 }
 
 func (c *SWGSchemas) Lock() {
@@ -499,58 +792,57 @@ func NewSGWSchemas() *SWGSchemas {
 // and the Swagger specs (which resides in the swaggerBaseDir, can be either a local path or an http URI)
 // Optionally, users can specify the swaggerGrantDir which contains the grants for those non-terraform
 // appropriate swagger schema/properties.
-func NewSWGSchemasFromTerraformSchema(swaggerBasePath, tfSchemaDir, swaggerGrantBaseDir string) (*SWGSchemas, error) {
+// It doesn't stop at the first problem, instead, the returned error (if any) is a Diagnostics that records
+// every problem found in the knowledge base files. In this case, the returned SWGSchemas still contains all
+// the links that succeeded.
+func NewSWGSchemasFromTerraformSchema(swaggerBasePath, tfSchemaDir, swaggerGrantBaseDir string, grantOpts SWGGrantOptions) (*SWGSchemas, error) {
+	var diags Diagnostics
+	files, err := LoadTFSchemaFiles(tfSchemaDir)
+	diags.appendWithRule(err, RuleTFSchemaInvalid)
+	swgschemas, err := NewSWGSchemasFromTFSchemaFiles(swaggerBasePath, files, swaggerGrantBaseDir, grantOpts)
+	diags.Append(err)
+	diags.Sort()
+	return swgschemas, diags.Err()
+}
+
+// NewSWGSchemasFromTFSchemaFiles is similar to the NewSWGSchemasFromTerraformSchema, except it builds the SWGSchemas from the
+// TFSchemaFiles that are already loaded, so that the callers that also need the files don't load them twice.
+func NewSWGSchemasFromTFSchemaFiles(swaggerBasePath string, files []TFSchemaFile, swaggerGrantBaseDir string, grantOpts SWGGrantOptions) (*SWGSchemas, error) {
 	swgschemas := NewSGWSchemas()
-	err := filepath.Walk(tfSchemaDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.Mode().IsRegular() {
-			return nil
-		}
-		b, err := ioutil.ReadFile(path)
-		if err != nil {
-			return err
-		}
-		var tfschema TFSchema
-		if err := json.Unmarshal(b, &tfschema); err != nil {
-			return err
-		}
-		if err := tfschema.Validate(); err != nil {
-			return fmt.Errorf("validating tf schema %s: %v", tfschema.Name, err)
-		}
+	var diags Diagnostics
 
-		if err := tfschema.LinkSwagger(swgschemas, swaggerBasePath); err != nil {
-			return fmt.Errorf("Linking swagger failed in file %s: %v", info.Name(), err)
+	for _, f := range files {
+		if err := f.LinkSwagger(swgschemas, swaggerBasePath); err != nil {
+			diags = append(diags, f.Locate(err).WithRule(RuleTFSchemaLink)...)
 		}
-
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error walking the terraform schema directory %q: %v\n", tfSchemaDir, err)
 	}
 
 	// grant swagger schemas
 	if swaggerGrantBaseDir != "" {
 		swggrant, err := NewSWGGrantFromFiles(swaggerGrantBaseDir)
-		if err != nil {
-			log.Fatal(err)
+		diags.appendWithRule(err, RuleGrantInvalid)
+		swgschemas.SWGGrant = swggrant
+		if err := swgschemas.Grant(swaggerBasePath, swggrant, grantOpts); err != nil {
+			diags = append(diags, err.(Diagnostics).resolveFiles(swaggerGrantBaseDir).WithRule(RuleGrantUnapplicable)...)
 		}
-		if err := swgschemas.Grant(swggrant); err != nil {
-			return nil, err
+		if len(swgschemas.StaleInheritedGrants) != 0 {
+			swgschemas.StaleInheritedGrants = swgschemas.StaleInheritedGrants.resolveFiles(swaggerGrantBaseDir).WithRule(RuleGrantStaleInherited)
+			swgschemas.StaleInheritedGrants.Sort()
 		}
 	}
 
 	// calculate swagger property coverage
 	for schemaAddr, schema := range swgschemas.GetAll() {
 		if err := schema.CalcCoverage(); err != nil {
-			log.Fatalf("calculating coverage for %q: %v", schemaAddr, err)
+			diags.appendWithRule(fmt.Errorf("calculating coverage for %q: %v", schemaAddr, err), RuleCoverage)
 		}
 	}
-	return swgschemas, nil
+
+	diags.Sort()
+	return swgschemas, diags.Err()
 }
 
-func (c *SWGSchemas) LinkSWGSchema(swaggerBasePath, swaggerRelPath string, swgPropAddr propertyaddr.SwaggerPropertyAddr, tfPropAddr propertyaddr.TerraformPropertyAddr) error {
+func (c *SWGSchemas) LinkSWGSchema(swaggerBasePath, swaggerRelPath string, swgPropAddr propertyaddr.SwaggerPropertyAddr, tfLink TFLink) error {
 	c.Lock()
 	defer c.Unlock()
 
@@ -565,35 +857,209 @@ func (c *SWGSchemas) LinkSWGSchema(swaggerBasePath, swaggerRelPath string, swgPr
 
 	defer c.Set(NewSWGSchemaAddr(swaggerRelPath, swgPropAddr.Schema), swgSchema)
 
-	return swgSchema.AddTFLink(swgPropAddr, tfPropAddr)
+	return swgSchema.AddTFLink(swgPropAddr, tfLink)
 }
 
-// Grant inquiries the SWGGrant to add the granting information onto the SWGSchemas
-func (c *SWGSchemas) Grant(grant SWGGrant) error {
+// Grant inquiries the SWGGrant to add the granting information onto the SWGSchemas.
+// The schemas are expanded on demand to reach the properties to be granted, and a property that has been expanded is granted
+// by granting all its expanded properties. The schemas that are granted as a whole are loaded from the swaggerBasePath if they
+// are not loaded yet.
+// The schema grants might be inherited from the older API versions, as is tuned by the opts. An inherited property grant only
+// applies to the property that still exists with the same shape.
+// The pattern grants apply to the properties of every schema underneath their directories, unless the properties are granted
+// explicitly by the schema grants. While the directory grants grant every schema underneath their directories as a whole,
+// regardless of the other grants.
+// The returned error (if any) is a Diagnostics that records every grant that can't be applied, whose file is the grant file
+// path relative to the grant base directory. While the inherited grants that no longer match anything are recorded in the
+// StaleInheritedGrants instead, in the same form.
+func (c *SWGSchemas) Grant(swaggerBasePath string, grant SWGGrant, opts SWGGrantOptions) error {
 	c.Lock()
 	defer c.Unlock()
-	for schemaAddr, schemaGrant := range grant {
-		schema, ok := c.m[schemaAddr]
-		if !ok {
+	var diags Diagnostics
+
+	// Load the schemas granted as a whole, so that they are recorded as granted.
+	for schemaAddr := range grant.Schemas {
+		if _, ok := c.m[schemaAddr]; ok {
 			continue
 		}
+		if schemaGrant, ok := grant.resolve(schemaAddr, opts); !ok || !schemaGrant.IsSchemaGranted() {
+			continue
+		}
+		schema, err := NewSWGSchema(swaggerBasePath, schemaAddr.SwaggerRelPath(), schemaAddr.SchemaName())
+		if err != nil {
+			diags = append(diags, Diagnostic{
+				File:    grant.Schemas[schemaAddr].file,
+				Pointer: jsonPointer(schemaAddr.SchemaName()),
+				Err:     fmt.Errorf("loading Swagger schema %s to be granted: %v", schemaAddr, err),
+			})
+			continue
+		}
+		c.m[schemaAddr] = schema
+	}
+
+	olderSchemas := map[SWGSchemaAddr]*SWGSchema{}
+	for schemaAddr, schema := range c.m {
+		if dirGrant, ok := grant.DirectoryGrant(filepath.Dir(schemaAddr.SwaggerRelPath())); ok {
+			schema.Grant(dirGrant.GrantInfo, dirGrant.file)
+			continue
+		}
+
+		schemaGrant, ok := grant.resolve(schemaAddr, opts)
+
+		file := schemaGrant.file
+		if file == "" {
+			file = schemaAddr.SwaggerRelPath()
+		}
 
-		if schemaGrant.IsSchemaGranted() {
-			schema.IsGranted = true
-			schema.GrantComment = schemaGrant.Comment
+		if ok && schemaGrant.IsSchemaGranted() {
+			schema.Grant(schemaGrant.GrantInfo, file)
 			continue
 		}
 
-		for propertyAddr, propertyGrantComment := range schemaGrant.Properties {
-			property, ok := schema.Properties[propertyAddr]
+		// Look up (and expand to) the properties to be granted explicitly beforehand, so that the pattern grants also apply to
+		// the expanded properties, and are then overridden by the explicit property grants.
+		type propertyGrantTarget struct {
+			propertyAddr string
+			properties   []*SWGSchemaProperty
+			grant        SWGPropertyGrant
+			source       string
+		}
+		var targets []propertyGrantTarget
+		for _, propertyAddr := range sortedGrantProperties(schemaGrant.Properties) {
+			propertyGrant := schemaGrant.Properties[propertyAddr]
+			if propertyGrant.inheritedFrom != "" {
+				olderSchema, ok := olderSchemas[propertyGrant.inheritedFrom]
+				if !ok {
+					var err error
+					olderSchema, err = NewSWGSchema(strings.TrimSuffix(schema.swaggerURL, "/"+schema.SwaggerRelPath), propertyGrant.inheritedFrom.SwaggerRelPath(), schemaAddr.SchemaName())
+					if err != nil {
+						c.StaleInheritedGrants = append(c.StaleInheritedGrants, Diagnostic{
+							File:    propertyGrant.file,
+							Pointer: jsonPointer(schemaAddr.SchemaName()),
+							Err:     fmt.Errorf("loading Swagger schema %s to inherit its grants: %v", propertyGrant.inheritedFrom, err),
+						})
+					}
+					// The failure is only reported once per older schema.
+					olderSchemas[propertyGrant.inheritedFrom] = olderSchema
+				}
+				if olderSchema == nil {
+					continue
+				}
+				property, err := checkInheritedPropertyGrant(schema, olderSchema, propertyAddr)
+				if err != nil {
+					c.StaleInheritedGrants = append(c.StaleInheritedGrants, Diagnostic{
+						File:    propertyGrant.file,
+						Pointer: jsonPointer(schemaAddr.SchemaName(), "Properties", propertyAddr),
+						Err:     fmt.Errorf(`grant of property "%s" inherited by Swagger schema %s: %v`, propertyAddr, schemaAddr, err),
+					})
+					continue
+				}
+				targets = append(targets, propertyGrantTarget{propertyAddr, []*SWGSchemaProperty{property}, propertyGrant, propertyGrant.file})
+				continue
+			}
+
+			properties, err := schema.lookupProperties(propertyAddr)
+			if err == nil && len(properties) == 0 {
+				err = fmt.Errorf(`property to be granted: "%s" doesn't exist in Swagger schema: %s'`, propertyAddr, schemaAddr)
+			}
+			if err != nil {
+				diags = append(diags, Diagnostic{
+					File:    file,
+					Pointer: jsonPointer(schemaAddr.SchemaName(), "Properties", propertyAddr),
+					Err:     err,
+				})
+				continue
+			}
+			targets = append(targets, propertyGrantTarget{propertyAddr, properties, propertyGrant, file})
+		}
+
+		// Expand the properties that are the literal prefix of the pattern grants (e.g. "systemData" of "systemData.*") one level
+		// deep, so that the pattern grants also apply to their properties that are not expanded yet.
+		var prefixExpanded bool
+		for _, patternGrant := range grant.Patterns {
+			if !patternGrant.appliesTo(schemaAddr) {
+				continue
+			}
+			prefix, ok := patternGrant.literalPrefix()
 			if !ok {
-				return fmt.Errorf(`property to be granted: "%s" doesn't exist in Swagger schema: %s'`, propertyAddr, schemaAddr)
+				continue
+			}
+			// The linked properties are kept as is, so that their links are not lost.
+			if prop, ok := schema.Properties[prefix]; !ok || len(prop.TFLinks) != 0 {
+				continue
+			}
+			if err := schema.ExpandPropertyOneLevelDeep(propertyaddr.MustNewSwaggerPropertyAddr(schemaAddr.SchemaName(), prefix)); err != nil {
+				diags = append(diags, Diagnostic{
+					File:    patternGrant.file,
+					Pointer: jsonPointer(patternGrant.Pattern),
+					Err:     fmt.Errorf(`expanding property "%s" of Swagger schema %s for the pattern: %v`, prefix, schemaAddr, err),
+				})
+				continue
+			}
+			prefixExpanded = true
+		}
+
+		// The expanded prefix property is replaced by its properties, which are granted instead if it is to be granted explicitly.
+		if prefixExpanded {
+			for i, target := range targets {
+				if properties, err := schema.lookupProperties(target.propertyAddr); err == nil && len(properties) != 0 {
+					targets[i].properties = properties
+				}
+			}
+		}
+
+		for propertyAddr, property := range schema.Properties {
+			if patternGrant, ok := grant.matchPatternGrant(schemaAddr, propertyAddr); ok {
+				property.grant(patternGrant.GrantInfo, patternGrant.file, patternGrant.Pattern)
+			}
+		}
+
+		for _, target := range targets {
+			for _, property := range target.properties {
+				property.grant(target.grant.GrantInfo, target.source, "")
 			}
-			property.IsGranted = true
-			property.GrantComment = propertyGrantComment
 		}
 	}
-	return nil
+	return diags.Err()
+}
+
+// checkInheritedPropertyGrant checks whether the property granted in the older schema still exists in the schema with the same
+// shape, and returns the property.
+func checkInheritedPropertyGrant(schema, olderSchema *SWGSchema, propertyAddr string) (*SWGSchemaProperty, error) {
+	addr, err := propertyaddr.NewSwaggerPropertyAddr(schema.Name, propertyAddr)
+	if err != nil {
+		return nil, err
+	}
+	property, ok, err := schema.lookupProperty(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("the property no longer exists")
+	}
+	olderAddr, err := propertyaddr.NewSwaggerPropertyAddr(olderSchema.Name, propertyAddr)
+	if err != nil {
+		return nil, err
+	}
+	olderProperty, ok, err := olderSchema.lookupProperty(olderAddr)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("the property doesn't exist in the API version granting it")
+	}
+	shape, err := schema.propertyShape(property)
+	if err != nil {
+		return nil, err
+	}
+	olderShape, err := olderSchema.propertyShape(olderProperty)
+	if err != nil {
+		return nil, err
+	}
+	if shape != olderShape {
+		return nil, fmt.Errorf("the shape of the property has changed from %s to %s", olderShape, shape)
+	}
+	return property, nil
 }
 
 // GetSWGSchema get all SWGSchema from cache.
@@ -0,0 +1,300 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// SWGAPIVersionOf returns the API version of the swagger spec, which is the parent directory of the swagger spec
+// (e.g. "2020-05-01" of "network/resource-manager/Microsoft.Network/stable/2020-05-01/virtualNetwork.json").
+func SWGAPIVersionOf(swaggerRelPath string) string {
+	return filepath.Base(filepath.Dir(swaggerRelPath))
+}
+
+// NewerSWGAPIVersions finds the API versions newer than the one of the swagger spec, that have the same swagger file, in
+// ascending order. The API versions are the sibling directories of the API version directory of the swagger spec, so the
+// swagger spec directory is required to be a local path.
+func NewerSWGAPIVersions(swaggerBasePath, swaggerRelPath string) ([]string, error) {
+	if strings.HasPrefix(swaggerBasePath, "http://") || strings.HasPrefix(swaggerBasePath, "https://") {
+		return nil, fmt.Errorf("listing the API versions requires a local swagger spec directory, got %q", swaggerBasePath)
+	}
+	versionDir, fileName := filepath.Split(swaggerRelPath)
+	baseDir, version := filepath.Split(filepath.Clean(versionDir))
+
+	entries, err := ioutil.ReadDir(filepath.Join(swaggerBasePath, baseDir))
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() <= version {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(swaggerBasePath, baseDir, entry.Name(), fileName)); err != nil {
+			continue
+		}
+		versions = append(versions, entry.Name())
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// SWGAPIVersionPropertyDiff records how a swagger property changes from the linked API version to the latest one.
+type SWGAPIVersionPropertyDiff struct {
+	// The swagger schema relative property address.
+	Property string        `json:"property"`
+	Status   SWGDiffStatus `json:"status"`
+
+	// The type and whether the property is read only, in the latest API version (or in the linked API version if it is removed).
+	Type     string `json:"type"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+
+	// The changes of the changed property (e.g. "type: integer -> string").
+	Changes []string `json:"changes,omitempty"`
+}
+
+// SWGAPIVersionSchemaDiff records how a swagger schema linked by a terraform resource changes from the linked API version to
+// the latest one.
+type SWGAPIVersionSchemaDiff struct {
+	// The linked swagger schema.
+	Schema           SWGSchemaAddr `json:"schema"`
+	APIVersion       string        `json:"api_version"`
+	NewerAPIVersions []string      `json:"newer_api_versions"`
+
+	// The same swagger schema in the latest API version, which is empty if the schema no longer exists.
+	LatestSchema SWGSchemaAddr `json:"latest_schema,omitempty"`
+
+	// The amount of the new leaf properties that are writable (i.e. neither they nor their ancestors are read only), which are
+	// the candidates of the missed features.
+	NewWritableProperties int `json:"new_writable_properties"`
+
+	// The added, removed and changed properties, ordered by their addresses.
+	Properties []SWGAPIVersionPropertyDiff `json:"properties,omitempty"`
+}
+
+// TFResourceAPIVersionDiff records how the swagger schemas linked by a terraform resource change in the newer API versions.
+type TFResourceAPIVersionDiff struct {
+	Resource              string                    `json:"resource"`
+	NewWritableProperties int                       `json:"new_writable_properties"`
+	Schemas               []SWGAPIVersionSchemaDiff `json:"schemas"`
+}
+
+// NewTFResourceAPIVersionDiffs diffs each swagger schema linked by the TFSchemas against the same schema in the latest API
+// version of the same swagger file, both are expanded up to maxDepth levels deep. Only the resources linking to a swagger
+// schema that has a newer API version are reported, ordered by the amount of the new writable properties (descending),
+// then by the resource names.
+func NewTFResourceAPIVersionDiffs(swaggerBasePath string, tfschemas []TFSchema, maxDepth int) ([]TFResourceAPIVersionDiff, error) {
+	var diffs []TFResourceAPIVersionDiff
+	for _, tfschema := range tfschemas {
+		resourceDiff := TFResourceAPIVersionDiff{Resource: tfschema.Name}
+		for _, addr := range tfschema.linkedSWGSchemas() {
+			schemaDiff, ok, err := newSWGAPIVersionSchemaDiff(swaggerBasePath, addr, maxDepth)
+			if err != nil {
+				return nil, fmt.Errorf("diffing swagger schema %s linked by %s: %v", addr, tfschema.Name, err)
+			}
+			if !ok {
+				continue
+			}
+			resourceDiff.Schemas = append(resourceDiff.Schemas, schemaDiff)
+			resourceDiff.NewWritableProperties += schemaDiff.NewWritableProperties
+		}
+		if len(resourceDiff.Schemas) != 0 {
+			diffs = append(diffs, resourceDiff)
+		}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].NewWritableProperties != diffs[j].NewWritableProperties {
+			return diffs[i].NewWritableProperties > diffs[j].NewWritableProperties
+		}
+		return diffs[i].Resource < diffs[j].Resource
+	})
+	return diffs, nil
+}
+
+// linkedSWGSchemas returns the swagger schemas linked by the TFSchema, in order.
+func (schema TFSchema) linkedSWGSchemas() []SWGSchemaAddr {
+	set := map[SWGSchemaAddr]bool{}
+	for _, links := range schema.PropertyLinks {
+		for _, link := range links {
+			if link.IsNotApplicable() {
+				continue
+			}
+			swaggerRelPath := schema.SwaggerSpec
+			if link.Spec != nil {
+				swaggerRelPath = *link.Spec
+			}
+			set[NewSWGSchemaAddr(swaggerRelPath, link.SchemaProp.Schema)] = true
+		}
+	}
+	addrs := make([]SWGSchemaAddr, 0, len(set))
+	for addr := range set {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+	return addrs
+}
+
+// newSWGAPIVersionSchemaDiff diffs the swagger schema against the same schema in the latest API version. It returns false if
+// there is no newer API version.
+func newSWGAPIVersionSchemaDiff(swaggerBasePath string, addr SWGSchemaAddr, maxDepth int) (SWGAPIVersionSchemaDiff, bool, error) {
+	swaggerRelPath := addr.SwaggerRelPath()
+	versions, err := NewerSWGAPIVersions(swaggerBasePath, swaggerRelPath)
+	if err != nil {
+		return SWGAPIVersionSchemaDiff{}, false, err
+	}
+	if len(versions) == 0 {
+		return SWGAPIVersionSchemaDiff{}, false, nil
+	}
+	diff := SWGAPIVersionSchemaDiff{
+		Schema:           addr,
+		APIVersion:       SWGAPIVersionOf(swaggerRelPath),
+		NewerAPIVersions: versions,
+	}
+
+	schema, err := NewSWGSchema(swaggerBasePath, swaggerRelPath, addr.SchemaName())
+	if err != nil {
+		return diff, false, err
+	}
+	if err := schema.ExpandAll(maxDepth); err != nil {
+		return diff, false, err
+	}
+
+	versionDir, fileName := filepath.Split(swaggerRelPath)
+	latestRelPath := filepath.Join(filepath.Dir(filepath.Clean(versionDir)), versions[len(versions)-1], fileName)
+	latestSwagger, err := LoadSwagger(swaggerBasePath + "/" + filepath.ToSlash(latestRelPath))
+	if err != nil {
+		return diff, false, err
+	}
+	if _, ok := latestSwagger.Definitions[addr.SchemaName()]; !ok {
+		// The schema might be renamed or removed in the latest API version.
+		return diff, true, nil
+	}
+	latestSchema, err := NewSWGSchema(swaggerBasePath, latestRelPath, addr.SchemaName())
+	if err != nil {
+		return diff, false, err
+	}
+	if err := latestSchema.ExpandAll(maxDepth); err != nil {
+		return diff, false, err
+	}
+	diff.LatestSchema = NewSWGSchemaAddr(latestRelPath, addr.SchemaName())
+
+	propSet := map[string]bool{}
+	for raddr := range schema.Properties {
+		propSet[raddr] = true
+	}
+	for raddr := range latestSchema.Properties {
+		propSet[raddr] = true
+	}
+	props := make([]string, 0, len(propSet))
+	for raddr := range propSet {
+		props = append(props, raddr)
+	}
+	sort.Strings(props)
+
+	for _, raddr := range props {
+		prop, inOld := schema.Properties[raddr]
+		latestProp, inLatest := latestSchema.Properties[raddr]
+		switch {
+		case !inOld:
+			diff.Properties = append(diff.Properties, SWGAPIVersionPropertyDiff{
+				Property: raddr,
+				Status:   SWGDiffAdded,
+				Type:     swgPropertyTypeString(latestSchema, raddr),
+				ReadOnly: !latestProp.writable(),
+			})
+			if latestProp.writable() && swgPropertyIsLeaf(latestSchema, raddr) {
+				diff.NewWritableProperties++
+			}
+		case !inLatest:
+			diff.Properties = append(diff.Properties, SWGAPIVersionPropertyDiff{
+				Property: raddr,
+				Status:   SWGDiffRemoved,
+				Type:     swgPropertyTypeString(schema, raddr),
+				ReadOnly: !prop.writable(),
+			})
+		default:
+			oldType, latestType := swgPropertyTypeString(schema, raddr), swgPropertyTypeString(latestSchema, raddr)
+			var changes []string
+			if oldType != latestType {
+				changes = append(changes, fmt.Sprintf("type: %s -> %s", oldType, latestType))
+			}
+			if prop.ReadOnly() != latestProp.ReadOnly() {
+				changes = append(changes, fmt.Sprintf("read only: %t -> %t", prop.ReadOnly(), latestProp.ReadOnly()))
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			diff.Properties = append(diff.Properties, SWGAPIVersionPropertyDiff{
+				Property: raddr,
+				Status:   SWGDiffChanged,
+				Type:     latestType,
+				ReadOnly: latestProp.ReadOnly(),
+				Changes:  changes,
+			})
+		}
+	}
+	return diff, true, nil
+}
+
+// swgPropertyIsLeaf tells whether the property of the swagger schema has no sub-properties, i.e. it is not an object (or an
+// array of objects) left unexpanded (e.g. due to the max depth).
+func swgPropertyIsLeaf(schema *SWGSchema, raddr string) bool {
+	t, err := schema.FindPropertyType(propertyaddr.MustNewSwaggerPropertyAddr(schema.Name, raddr))
+	if err != nil {
+		return true
+	}
+	return t.Type != "object" || len(t.Properties) == 0
+}
+
+// swgPropertyTypeString describes the type of the property of the swagger schema, which is "unknown" if the type can't be resolved.
+func swgPropertyTypeString(schema *SWGSchema, raddr string) string {
+	t, err := schema.FindPropertyType(propertyaddr.MustNewSwaggerPropertyAddr(schema.Name, raddr))
+	if err != nil {
+		return "unknown"
+	}
+	return t.String()
+}
+
+// WriteTFResourceAPIVersionDiffsMarkdown writes the TFResourceAPIVersionDiffs in Markdown, where each resource has its own section.
+func WriteTFResourceAPIVersionDiffsMarkdown(w io.Writer, diffs []TFResourceAPIVersionDiff) error {
+	var sb strings.Builder
+	sb.WriteString("# New Swagger Properties\n")
+	for _, resourceDiff := range diffs {
+		fmt.Fprintf(&sb, "\n## `%s`\n\n", resourceDiff.Resource)
+		fmt.Fprintf(&sb, "New writable properties: %d\n", resourceDiff.NewWritableProperties)
+		for _, schemaDiff := range resourceDiff.Schemas {
+			fmt.Fprintf(&sb, "\n### `%s`\n\n", schemaDiff.Schema)
+			fmt.Fprintf(&sb, "- API version: %s\n", schemaDiff.APIVersion)
+			fmt.Fprintf(&sb, "- Newer API versions: %s\n", strings.Join(schemaDiff.NewerAPIVersions, ", "))
+			if schemaDiff.LatestSchema == "" {
+				sb.WriteString("- The schema doesn't exist in the latest API version\n")
+				continue
+			}
+			fmt.Fprintf(&sb, "- New writable properties: %d\n", schemaDiff.NewWritableProperties)
+			if len(schemaDiff.Properties) == 0 {
+				continue
+			}
+			sb.WriteString("\n| Property | Status | Type | Read Only | Changes |\n|---|---|---|---|---|\n")
+			for _, propDiff := range schemaDiff.Properties {
+				fmt.Fprintf(&sb, "| `%s` | %s | %s | %t | %s |\n",
+					propDiff.Property,
+					propDiff.Status,
+					markdownEscapeTableCell(propDiff.Type),
+					propDiff.ReadOnly,
+					markdownEscapeTableCell(strings.Join(propDiff.Changes, "<br>")),
+				)
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTFResourceAPIVersionDiffs(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_api_versions")
+	fooRelPath := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+	barRelPath := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "bar.json")
+	latestFooRelPath := filepath.Join("Microsoft.Foo", "stable", "2021-01-01", "foo.json")
+
+	versions, err := NewerSWGAPIVersions(specBasePath, fooRelPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2020-06-01", "2021-01-01"}, versions)
+
+	tfschemas := []TFSchema{
+		{
+			Name:        "azurerm_bar",
+			SwaggerSpec: barRelPath,
+			PropertyLinks: TFSchemaPropertyLinks{
+				"name": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Bar:name")}},
+			},
+		},
+		{
+			Name:        "azurerm_baz",
+			SwaggerSpec: fooRelPath,
+			PropertyLinks: TFSchemaPropertyLinks{
+				"name": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Baz:name")}},
+			},
+		},
+		{
+			Name:        "azurerm_foo",
+			SwaggerSpec: fooRelPath,
+			PropertyLinks: TFSchemaPropertyLinks{
+				"p1":                  {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Foo:properties.p1")}},
+				"resource_group_name": {NewNotApplicableLink("part of the resource id")},
+			},
+		},
+	}
+
+	// The cyclic "properties.node.child" of Foo is not a leaf, and its "properties.state.code" is read only as its parent, so
+	// neither of them is a new writable property.
+	diffs, err := NewTFResourceAPIVersionDiffs(specBasePath, tfschemas, 5)
+	require.NoError(t, err)
+	require.Equal(t, []TFResourceAPIVersionDiff{
+		{
+			Resource:              "azurerm_foo",
+			NewWritableProperties: 2,
+			Schemas: []SWGAPIVersionSchemaDiff{
+				{
+					Schema:                NewSWGSchemaAddr(fooRelPath, "Foo"),
+					APIVersion:            "2020-01-01",
+					NewerAPIVersions:      []string{"2020-06-01", "2021-01-01"},
+					LatestSchema:          NewSWGSchemaAddr(latestFooRelPath, "Foo"),
+					NewWritableProperties: 2,
+					Properties: []SWGAPIVersionPropertyDiff{
+						{Property: "etag", Status: SWGDiffChanged, Type: "string", Changes: []string{"read only: true -> false"}},
+						{Property: "old", Status: SWGDiffRemoved, Type: "string"},
+						{Property: "properties.node.child", Status: SWGDiffAdded, Type: "object {child, name}"},
+						{Property: "properties.node.name", Status: SWGDiffAdded, Type: "string"},
+						{Property: "properties.p2", Status: SWGDiffChanged, Type: "string", Changes: []string{"type: integer -> string"}},
+						{Property: "properties.p3", Status: SWGDiffAdded, Type: "boolean"},
+						{Property: "properties.state.code", Status: SWGDiffAdded, Type: "string", ReadOnly: true},
+						{Property: "properties.status", Status: SWGDiffAdded, Type: "string", ReadOnly: true},
+					},
+				},
+			},
+		},
+		{
+			Resource: "azurerm_baz",
+			Schemas: []SWGAPIVersionSchemaDiff{
+				{
+					Schema:           NewSWGSchemaAddr(fooRelPath, "Baz"),
+					APIVersion:       "2020-01-01",
+					NewerAPIVersions: []string{"2020-06-01", "2021-01-01"},
+				},
+			},
+		},
+	}, diffs)
+
+	var sb strings.Builder
+	require.NoError(t, WriteTFResourceAPIVersionDiffsMarkdown(&sb, diffs))
+	require.Contains(t, sb.String(), "| `properties.p3` | added | boolean | false |  |\n")
+	require.Contains(t, sb.String(), "- The schema doesn't exist in the latest API version\n")
+}
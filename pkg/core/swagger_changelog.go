@@ -0,0 +1,518 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	openapispec "github.com/go-openapi/spec"
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// SWGChangelogDefinition is a swagger definition of an API version.
+type SWGChangelogDefinition struct {
+	// The swagger file name, relative to the API version directory.
+	File string `json:"file"`
+	Name string `json:"name"`
+}
+
+// SWGChangelogOperation is an operation of an API version.
+type SWGChangelogOperation struct {
+	// The swagger file name, relative to the API version directory.
+	File        string `json:"file"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id,omitempty"`
+}
+
+// SWGChangelogProperty records how a property of a PUT body schema changes.
+type SWGChangelogProperty struct {
+	// The swagger schema relative property address.
+	Property string `json:"property"`
+
+	// The type of the property, in the new API version (or in the old API version if it is removed).
+	Type string `json:"type,omitempty"`
+
+	// The type of the retyped property in the old API version.
+	OldType string `json:"old_type,omitempty"`
+
+	// The enum values that are new in the new API version.
+	NewEnumValues []string `json:"new_enum_values,omitempty"`
+}
+
+// SWGChangelogSchema records how a PUT body schema, which exists in both API versions, changes.
+type SWGChangelogSchema struct {
+	SWGChangelogDefinition
+
+	AddedProperties   []SWGChangelogProperty `json:"added_properties,omitempty"`
+	RemovedProperties []SWGChangelogProperty `json:"removed_properties,omitempty"`
+	RetypedProperties []SWGChangelogProperty `json:"retyped_properties,omitempty"`
+	NewEnumValues     []SWGChangelogProperty `json:"new_enum_values,omitempty"`
+}
+
+// SWGChangelogDiscriminator records the new variants of a discriminator base schema, which exists in both API versions.
+type SWGChangelogDiscriminator struct {
+	SWGChangelogDefinition
+
+	// The discriminator values of the new variants.
+	NewVariants []string `json:"new_variants"`
+}
+
+// SWGChangelog is the structural diff between two API versions of a resource provider, which is independent of terraform.
+// Each list is ordered, and only the changes are recorded.
+type SWGChangelog struct {
+	// The directory that contains the API version directories (e.g. "network/resource-manager/Microsoft.Network/stable"),
+	// relative to the swagger spec directory.
+	ResourceProviderDir string `json:"resource_provider_dir"`
+	OldAPIVersion       string `json:"old_api_version"`
+	NewAPIVersion       string `json:"new_api_version"`
+
+	AddedOperations    []SWGChangelogOperation     `json:"added_operations,omitempty"`
+	RemovedOperations  []SWGChangelogOperation     `json:"removed_operations,omitempty"`
+	AddedDefinitions   []SWGChangelogDefinition    `json:"added_definitions,omitempty"`
+	RemovedDefinitions []SWGChangelogDefinition    `json:"removed_definitions,omitempty"`
+	Schemas            []SWGChangelogSchema        `json:"schemas,omitempty"`
+	Discriminators     []SWGChangelogDiscriminator `json:"discriminators,omitempty"`
+}
+
+// swgAPIVersionSpecs is the swagger files of an API version, keyed by the file name.
+type swgAPIVersionSpecs map[string]*openapispec.Swagger
+
+var swgDefinitionRefPattern = regexp.MustCompile(`^/definitions/([^/]+)$`)
+
+// LatestSWGAPIVersion finds the latest API version in the resource provider directory, which is required to be a local path.
+func LatestSWGAPIVersion(swaggerBasePath, rpDir string) (string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(swaggerBasePath, rpDir))
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() > latest {
+			latest = entry.Name()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no API version is found in %s", filepath.Join(swaggerBasePath, rpDir))
+	}
+	return latest, nil
+}
+
+// NewSWGChangelog diffs the new API version of the resource provider against the old one. The PUT body schemas existing in
+// both API versions are expanded up to maxDepth levels deep, to diff their properties. The swagger spec directory is required
+// to be a local path, as the swagger files of each API version are listed.
+func NewSWGChangelog(swaggerBasePath, rpDir, oldAPIVersion, newAPIVersion string, maxDepth int) (*SWGChangelog, error) {
+	if strings.HasPrefix(swaggerBasePath, "http://") || strings.HasPrefix(swaggerBasePath, "https://") {
+		return nil, fmt.Errorf("listing the swagger files requires a local swagger spec directory, got %q", swaggerBasePath)
+	}
+	oldSpecs, err := loadSWGAPIVersionSpecs(swaggerBasePath, filepath.Join(rpDir, oldAPIVersion))
+	if err != nil {
+		return nil, err
+	}
+	newSpecs, err := loadSWGAPIVersionSpecs(swaggerBasePath, filepath.Join(rpDir, newAPIVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	changelog := &SWGChangelog{
+		ResourceProviderDir: rpDir,
+		OldAPIVersion:       oldAPIVersion,
+		NewAPIVersion:       newAPIVersion,
+	}
+
+	oldOps, newOps := oldSpecs.operations(), newSpecs.operations()
+	for _, key := range sortedOperationKeys(newOps) {
+		if _, ok := oldOps[key]; !ok {
+			changelog.AddedOperations = append(changelog.AddedOperations, newOps[key])
+		}
+	}
+	for _, key := range sortedOperationKeys(oldOps) {
+		if _, ok := newOps[key]; !ok {
+			changelog.RemovedOperations = append(changelog.RemovedOperations, oldOps[key])
+		}
+	}
+
+	oldDefs, newDefs := oldSpecs.definitions(), newSpecs.definitions()
+	for _, def := range newDefs {
+		if !containsSWGChangelogDefinition(oldDefs, def) {
+			changelog.AddedDefinitions = append(changelog.AddedDefinitions, def)
+		}
+	}
+	for _, def := range oldDefs {
+		if !containsSWGChangelogDefinition(newDefs, def) {
+			changelog.RemovedDefinitions = append(changelog.RemovedDefinitions, def)
+		}
+	}
+
+	oldBodies, err := oldSpecs.putBodySchemas(swaggerBasePath, filepath.Join(rpDir, oldAPIVersion))
+	if err != nil {
+		return nil, err
+	}
+	newBodies, err := newSpecs.putBodySchemas(swaggerBasePath, filepath.Join(rpDir, newAPIVersion))
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range newBodies {
+		if !containsSWGChangelogDefinition(oldBodies, def) {
+			continue
+		}
+		schemaChange, changed, err := newSWGChangelogSchema(swaggerBasePath, rpDir, oldAPIVersion, newAPIVersion, def, maxDepth)
+		if err != nil {
+			return nil, fmt.Errorf("diffing PUT body schema %s (%s): %v", def.Name, def.File, err)
+		}
+		if changed {
+			changelog.Schemas = append(changelog.Schemas, schemaChange)
+		}
+	}
+
+	oldVariants, newVariants := oldSpecs.discriminatorVariants(), newSpecs.discriminatorVariants()
+	for _, base := range sortedSWGChangelogDefinitions(newVariants) {
+		old, ok := oldVariants[base]
+		if !ok {
+			continue
+		}
+		var added []string
+		for _, variant := range newVariants[base] {
+			if !containsString(old, variant) {
+				added = append(added, variant)
+			}
+		}
+		if len(added) != 0 {
+			changelog.Discriminators = append(changelog.Discriminators, SWGChangelogDiscriminator{SWGChangelogDefinition: base, NewVariants: added})
+		}
+	}
+
+	return changelog, nil
+}
+
+func loadSWGAPIVersionSpecs(swaggerBasePath, versionDir string) (swgAPIVersionSpecs, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(swaggerBasePath, versionDir))
+	if err != nil {
+		return nil, err
+	}
+	specs := swgAPIVersionSpecs{}
+	for _, entry := range entries {
+		// The sub-directories are skipped, e.g. the "examples".
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		swagger, err := LoadSwagger(swaggerBasePath + "/" + filepath.ToSlash(filepath.Join(versionDir, entry.Name())))
+		if err != nil {
+			return nil, err
+		}
+		specs[entry.Name()] = swagger
+	}
+	return specs, nil
+}
+
+// operations returns the operations of the API version, keyed by the method and the path.
+func (specs swgAPIVersionSpecs) operations() map[string]SWGChangelogOperation {
+	ops := map[string]SWGChangelogOperation{}
+	for file, swagger := range specs {
+		if swagger.Paths == nil {
+			continue
+		}
+		for path, item := range swagger.Paths.Paths {
+			for method, op := range map[string]*openapispec.Operation{
+				"GET":     item.Get,
+				"PUT":     item.Put,
+				"POST":    item.Post,
+				"PATCH":   item.Patch,
+				"DELETE":  item.Delete,
+				"HEAD":    item.Head,
+				"OPTIONS": item.Options,
+			} {
+				if op == nil {
+					continue
+				}
+				ops[method+" "+path] = SWGChangelogOperation{File: file, Method: method, Path: path, OperationID: op.ID}
+			}
+		}
+	}
+	return ops
+}
+
+func sortedOperationKeys(ops map[string]SWGChangelogOperation) []string {
+	keys := make([]string, 0, len(ops))
+	for key := range ops {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ops[keys[i]].Path != ops[keys[j]].Path {
+			return ops[keys[i]].Path < ops[keys[j]].Path
+		}
+		return ops[keys[i]].Method < ops[keys[j]].Method
+	})
+	return keys
+}
+
+// definitions returns the definitions of the API version, in order.
+func (specs swgAPIVersionSpecs) definitions() []SWGChangelogDefinition {
+	var defs []SWGChangelogDefinition
+	for file, swagger := range specs {
+		for name := range swagger.Definitions {
+			defs = append(defs, SWGChangelogDefinition{File: file, Name: name})
+		}
+	}
+	sortSWGChangelogDefinitions(defs)
+	return defs
+}
+
+// putBodySchemas returns the schemas of the body parameters of the PUT operations, in order. The parameter refs are resolved,
+// while the body schemas defined in other swagger files are skipped.
+func (specs swgAPIVersionSpecs) putBodySchemas(swaggerBasePath, versionDir string) ([]SWGChangelogDefinition, error) {
+	set := map[SWGChangelogDefinition]bool{}
+	for file, swagger := range specs {
+		if swagger.Paths == nil {
+			continue
+		}
+		swaggerURI := swaggerBasePath + "/" + filepath.ToSlash(filepath.Join(versionDir, file))
+		for _, item := range swagger.Paths.Paths {
+			if item.Put == nil {
+				continue
+			}
+			name, ok, err := swgBodySchemaName(swagger, swaggerURI, item.Put)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				set[SWGChangelogDefinition{File: file, Name: name}] = true
+			}
+		}
+	}
+	defs := make([]SWGChangelogDefinition, 0, len(set))
+	for def := range set {
+		defs = append(defs, def)
+	}
+	sortSWGChangelogDefinitions(defs)
+	return defs, nil
+}
+
+// discriminatorVariants returns the discriminator values of the variants of each discriminator base schema. A variant is a
+// schema in the same swagger file that refers to the base schema in its "allOf", whose discriminator value is either its
+// "x-ms-discriminator-value", or its name.
+func (specs swgAPIVersionSpecs) discriminatorVariants() map[SWGChangelogDefinition][]string {
+	variants := map[SWGChangelogDefinition][]string{}
+	for file, swagger := range specs {
+		for name, schema := range swagger.Definitions {
+			if schema.Discriminator != "" {
+				base := SWGChangelogDefinition{File: file, Name: name}
+				if _, ok := variants[base]; !ok {
+					variants[base] = []string{}
+				}
+			}
+			for _, allOf := range schema.AllOf {
+				if !allOf.Ref.HasFragmentOnly {
+					continue
+				}
+				matches := swgDefinitionRefPattern.FindStringSubmatch(allOf.Ref.GetPointer().String())
+				if len(matches) != 2 || swagger.Definitions[matches[1]].Discriminator == "" {
+					continue
+				}
+				value := name
+				if v, ok := schema.Extensions[swaggerExtensionMSDiscriminatorValue].(string); ok {
+					value = v
+				}
+				base := SWGChangelogDefinition{File: file, Name: matches[1]}
+				variants[base] = append(variants[base], value)
+			}
+		}
+	}
+	for _, values := range variants {
+		sort.Strings(values)
+	}
+	return variants
+}
+
+// newSWGChangelogSchema diffs the properties of the PUT body schema between the API versions. It returns false if nothing changes.
+func newSWGChangelogSchema(swaggerBasePath, rpDir, oldAPIVersion, newAPIVersion string, def SWGChangelogDefinition, maxDepth int) (SWGChangelogSchema, bool, error) {
+	schemaChange := SWGChangelogSchema{SWGChangelogDefinition: def}
+
+	oldSchema, err := NewSWGSchema(swaggerBasePath, filepath.ToSlash(filepath.Join(rpDir, oldAPIVersion, def.File)), def.Name)
+	if err != nil {
+		return schemaChange, false, err
+	}
+	if err := oldSchema.ExpandAll(maxDepth); err != nil {
+		return schemaChange, false, err
+	}
+	newSchema, err := NewSWGSchema(swaggerBasePath, filepath.ToSlash(filepath.Join(rpDir, newAPIVersion, def.File)), def.Name)
+	if err != nil {
+		return schemaChange, false, err
+	}
+	if err := newSchema.ExpandAll(maxDepth); err != nil {
+		return schemaChange, false, err
+	}
+
+	propSet := map[string]bool{}
+	for raddr := range oldSchema.Properties {
+		propSet[raddr] = true
+	}
+	for raddr := range newSchema.Properties {
+		propSet[raddr] = true
+	}
+	props := make([]string, 0, len(propSet))
+	for raddr := range propSet {
+		props = append(props, raddr)
+	}
+	sort.Strings(props)
+
+	for _, raddr := range props {
+		_, inOld := oldSchema.Properties[raddr]
+		_, inNew := newSchema.Properties[raddr]
+		switch {
+		case !inOld:
+			schemaChange.AddedProperties = append(schemaChange.AddedProperties, SWGChangelogProperty{
+				Property: raddr,
+				Type:     swgPropertyTypeString(newSchema, raddr),
+			})
+		case !inNew:
+			schemaChange.RemovedProperties = append(schemaChange.RemovedProperties, SWGChangelogProperty{
+				Property: raddr,
+				Type:     swgPropertyTypeString(oldSchema, raddr),
+			})
+		default:
+			oldType, err := oldSchema.FindPropertyType(propertyaddr.MustNewSwaggerPropertyAddr(oldSchema.Name, raddr))
+			if err != nil {
+				continue
+			}
+			newType, err := newSchema.FindPropertyType(propertyaddr.MustNewSwaggerPropertyAddr(newSchema.Name, raddr))
+			if err != nil {
+				continue
+			}
+			if oldShape, newShape := swgPropertyTypeShape(*oldType), swgPropertyTypeShape(*newType); oldShape != newShape {
+				schemaChange.RetypedProperties = append(schemaChange.RetypedProperties, SWGChangelogProperty{
+					Property: raddr,
+					Type:     newShape,
+					OldType:  oldShape,
+				})
+			}
+			oldValues := swgEnumValues(*oldType)
+			var added []string
+			for _, v := range swgEnumValues(*newType) {
+				if !containsString(oldValues, v) {
+					added = append(added, v)
+				}
+			}
+			if len(added) != 0 {
+				schemaChange.NewEnumValues = append(schemaChange.NewEnumValues, SWGChangelogProperty{
+					Property:      raddr,
+					NewEnumValues: added,
+				})
+			}
+		}
+	}
+
+	changed := len(schemaChange.AddedProperties) != 0 || len(schemaChange.RemovedProperties) != 0 ||
+		len(schemaChange.RetypedProperties) != 0 || len(schemaChange.NewEnumValues) != 0
+	return schemaChange, changed, nil
+}
+
+// swgPropertyTypeShape describes the type regardless of its enum values and its property names, which are diffed separately.
+func swgPropertyTypeShape(t SWGPropertyType) string {
+	t.Enum = nil
+	t.Properties = nil
+	return t.String()
+}
+
+func swgEnumValues(t SWGPropertyType) []string {
+	values := make([]string, 0, len(t.Enum))
+	for _, v := range t.Enum {
+		values = append(values, fmt.Sprintf("%v", v))
+	}
+	return values
+}
+
+func sortSWGChangelogDefinitions(defs []SWGChangelogDefinition) {
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].File != defs[j].File {
+			return defs[i].File < defs[j].File
+		}
+		return defs[i].Name < defs[j].Name
+	})
+}
+
+func sortedSWGChangelogDefinitions(m map[SWGChangelogDefinition][]string) []SWGChangelogDefinition {
+	defs := make([]SWGChangelogDefinition, 0, len(m))
+	for def := range m {
+		defs = append(defs, def)
+	}
+	sortSWGChangelogDefinitions(defs)
+	return defs
+}
+
+func containsSWGChangelogDefinition(defs []SWGChangelogDefinition, def SWGChangelogDefinition) bool {
+	for _, d := range defs {
+		if d == def {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMarkdown writes the SWGChangelog in Markdown, where each kind of change has its own section.
+func (changelog SWGChangelog) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Swagger Changelog: `%s` %s -> %s\n", changelog.ResourceProviderDir, changelog.OldAPIVersion, changelog.NewAPIVersion)
+
+	if len(changelog.AddedOperations) != 0 || len(changelog.RemovedOperations) != 0 {
+		sb.WriteString("\n## Operations\n\n| Change | Method | Path | Operation ID | File |\n|---|---|---|---|---|\n")
+		for _, op := range changelog.AddedOperations {
+			fmt.Fprintf(&sb, "| added | %s | `%s` | %s | %s |\n", op.Method, op.Path, op.OperationID, op.File)
+		}
+		for _, op := range changelog.RemovedOperations {
+			fmt.Fprintf(&sb, "| removed | %s | `%s` | %s | %s |\n", op.Method, op.Path, op.OperationID, op.File)
+		}
+	}
+
+	if len(changelog.AddedDefinitions) != 0 || len(changelog.RemovedDefinitions) != 0 {
+		sb.WriteString("\n## Definitions\n\n| Change | Definition | File |\n|---|---|---|\n")
+		for _, def := range changelog.AddedDefinitions {
+			fmt.Fprintf(&sb, "| added | `%s` | %s |\n", def.Name, def.File)
+		}
+		for _, def := range changelog.RemovedDefinitions {
+			fmt.Fprintf(&sb, "| removed | `%s` | %s |\n", def.Name, def.File)
+		}
+	}
+
+	if len(changelog.Schemas) != 0 {
+		sb.WriteString("\n## PUT Body Schemas\n")
+		for _, schema := range changelog.Schemas {
+			fmt.Fprintf(&sb, "\n### `%s` (%s)\n\n| Property | Change |\n|---|---|\n", schema.Name, schema.File)
+			for _, prop := range schema.AddedProperties {
+				fmt.Fprintf(&sb, "| `%s` | added: %s |\n", prop.Property, markdownEscapeTableCell(prop.Type))
+			}
+			for _, prop := range schema.RemovedProperties {
+				fmt.Fprintf(&sb, "| `%s` | removed: %s |\n", prop.Property, markdownEscapeTableCell(prop.Type))
+			}
+			for _, prop := range schema.RetypedProperties {
+				fmt.Fprintf(&sb, "| `%s` | retyped: %s -> %s |\n", prop.Property, markdownEscapeTableCell(prop.OldType), markdownEscapeTableCell(prop.Type))
+			}
+			for _, prop := range schema.NewEnumValues {
+				fmt.Fprintf(&sb, "| `%s` | new enum values: %s |\n", prop.Property, markdownEscapeTableCell(strings.Join(prop.NewEnumValues, ", ")))
+			}
+		}
+	}
+
+	if len(changelog.Discriminators) != 0 {
+		sb.WriteString("\n## Discriminator Variants\n\n| Base | File | New Variants |\n|---|---|---|\n")
+		for _, d := range changelog.Discriminators {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", d.Name, d.File, markdownEscapeTableCell(strings.Join(d.NewVariants, ", ")))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
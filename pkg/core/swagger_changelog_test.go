@@ -0,0 +1,71 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSWGChangelog(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_changelog")
+	rpDir := filepath.Join("Microsoft.Foo", "stable")
+
+	latest, err := LatestSWGAPIVersion(specBasePath, rpDir)
+	require.NoError(t, err)
+	require.Equal(t, "2021-01-01", latest)
+
+	changelog, err := NewSWGChangelog(specBasePath, rpDir, "2020-01-01", latest, 5)
+	require.NoError(t, err)
+	require.Equal(t, &SWGChangelog{
+		ResourceProviderDir: rpDir,
+		OldAPIVersion:       "2020-01-01",
+		NewAPIVersion:       "2021-01-01",
+		AddedOperations: []SWGChangelogOperation{
+			{File: "bar.json", Method: "PUT", Path: "/bars/{name}", OperationID: "Bars_CreateOrUpdate"},
+			{File: "foo.json", Method: "POST", Path: "/foos/{name}/restart", OperationID: "Foos_Restart"},
+		},
+		RemovedOperations: []SWGChangelogOperation{
+			{File: "foo.json", Method: "GET", Path: "/foos", OperationID: "Foos_List"},
+		},
+		AddedDefinitions: []SWGChangelogDefinition{
+			{File: "bar.json", Name: "Bar"},
+			{File: "foo.json", Name: "RuleB"},
+		},
+		RemovedDefinitions: []SWGChangelogDefinition{
+			{File: "foo.json", Name: "OldDef"},
+		},
+		Schemas: []SWGChangelogSchema{
+			{
+				SWGChangelogDefinition: SWGChangelogDefinition{File: "foo.json", Name: "Foo"},
+				AddedProperties: []SWGChangelogProperty{
+					{Property: "properties.zones", Type: "array of string"},
+				},
+				RemovedProperties: []SWGChangelogProperty{
+					{Property: "properties.legacy", Type: "string"},
+				},
+				RetypedProperties: []SWGChangelogProperty{
+					{Property: "properties.size", Type: "string", OldType: "integer"},
+				},
+				NewEnumValues: []SWGChangelogProperty{
+					{Property: "properties.sku", NewEnumValues: []string{"Premium"}},
+				},
+			},
+		},
+		Discriminators: []SWGChangelogDiscriminator{
+			{SWGChangelogDefinition: SWGChangelogDefinition{File: "foo.json", Name: "Rule"}, NewVariants: []string{"B"}},
+		},
+	}, changelog)
+
+	var sb strings.Builder
+	require.NoError(t, changelog.WriteMarkdown(&sb))
+	require.Contains(t, sb.String(), "| added | POST | `/foos/{name}/restart` | Foos_Restart | foo.json |\n")
+	require.Contains(t, sb.String(), "| `properties.size` | retyped: integer -> string |\n")
+	require.Contains(t, sb.String(), "| `Rule` | foo.json | B |\n")
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MetricsNamespace is the prefix of the names of the exported metrics.
+const MetricsNamespace = "azurerm_insight"
+
+// The swagger property statuses, which are the values of the "status" label of the property count metric.
+const (
+	SWGPropertyStatusCovered   = "covered"
+	SWGPropertyStatusUncovered = "uncovered"
+	SWGPropertyStatusGranted   = "granted"
+)
+
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string
+	value  float64
+}
+
+// WriteSWGCoverageMetrics writes the coverage figures of the swagger schemas in the OpenMetrics text format, which can also be
+// consumed as a Prometheus textfile (e.g. by the textfile collector of the node exporter). The metrics are:
+//
+//	azurerm_insight_swagger_coverage{rp, api_version, swagger_file, schema}: the coverage (<=1) of each swagger schema
+//	azurerm_insight_swagger_properties{rp, api_version, swagger_file, schema, status}: the amount of the covered, uncovered and granted properties of each swagger schema
+//	azurerm_insight_swagger_resource_provider_coverage{rp}: the coverage (<=1) of each resource provider
+//	azurerm_insight_swagger_overall_coverage: the coverage (<=1) of all the swagger schemas
+//
+// The coverage is the same as the one in the SWGCoverageSummary, while a property is counted as granted if either itself or
+// its schema is granted.
+func WriteSWGCoverageMetrics(w io.Writer, schemas map[SWGSchemaAddr]*SWGSchema) error {
+	summary := NewSWGCoverageSummary(schemas)
+
+	schemaCoverage := metricFamily{
+		name: MetricsNamespace + "_swagger_coverage",
+		help: "The property coverage of the swagger schema, the granted properties are not counted.",
+	}
+	schemaProperties := metricFamily{
+		name: MetricsNamespace + "_swagger_properties",
+		help: "The amount of the properties of the swagger schema, by status.",
+	}
+	rpCoverage := metricFamily{
+		name: MetricsNamespace + "_swagger_resource_provider_coverage",
+		help: "The property coverage of the resource provider, the granted properties are not counted.",
+	}
+	overallCoverage := metricFamily{
+		name:    MetricsNamespace + "_swagger_overall_coverage",
+		help:    "The property coverage of all the swagger schemas, the granted properties are not counted.",
+		samples: []metricSample{{value: summary.Coverage()}},
+	}
+
+	for _, rpSummary := range summary.ResourceProviders {
+		rpCoverage.samples = append(rpCoverage.samples, metricSample{
+			labels: [][2]string{{"rp", rpSummary.ResourceProvider}},
+			value:  rpSummary.Coverage(),
+		})
+		for _, apiSummary := range rpSummary.APIVersions {
+			for _, schemaSummary := range apiSummary.Schemas {
+				addr := schemaSummary.Schema
+				swaggerFile := filepath.ToSlash(addr.SwaggerRelPath())
+				labels := [][2]string{
+					{"rp", rpSummary.ResourceProvider},
+					{"api_version", path.Base(path.Dir(swaggerFile))},
+					{"swagger_file", swaggerFile},
+					{"schema", addr.SchemaName()},
+				}
+				schemaCoverage.samples = append(schemaCoverage.samples, metricSample{labels: labels, value: schemaSummary.Coverage()})
+
+				counts := swgPropertyStatusCounts(schemas[addr])
+				for _, status := range []string{SWGPropertyStatusCovered, SWGPropertyStatusUncovered, SWGPropertyStatusGranted} {
+					schemaProperties.samples = append(schemaProperties.samples, metricSample{
+						labels: append(append([][2]string{}, labels...), [2]string{"status", status}),
+						value:  float64(counts[status]),
+					})
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, family := range []metricFamily{schemaCoverage, schemaProperties, rpCoverage, overallCoverage} {
+		fmt.Fprintf(&sb, "# TYPE %s gauge\n", family.name)
+		fmt.Fprintf(&sb, "# HELP %s %s\n", family.name, family.help)
+		for _, sample := range family.samples {
+			sb.WriteString(family.name)
+			if len(sample.labels) != 0 {
+				pairs := make([]string, 0, len(sample.labels))
+				for _, label := range sample.labels {
+					pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label[0], escapeMetricLabelValue(label[1])))
+				}
+				sb.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			sb.WriteString(" " + strconv.FormatFloat(sample.value, 'g', -1, 64) + "\n")
+		}
+	}
+	sb.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func swgPropertyStatusCounts(schema *SWGSchema) map[string]int {
+	counts := map[string]int{}
+	for _, prop := range schema.Properties {
+		switch {
+		case schema.IsGranted || prop.IsGranted:
+			counts[SWGPropertyStatusGranted]++
+		case len(prop.TFLinks) != 0:
+			counts[SWGPropertyStatusCovered]++
+		default:
+			counts[SWGPropertyStatusUncovered]++
+		}
+	}
+	return counts
+}
+
+func escapeMetricLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSWGCoverageMetrics(t *testing.T) {
+	link := TFLinks{{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:p")}}
+	schemas := map[SWGSchemaAddr]*SWGSchema{
+		NewSWGSchemaAddr("Microsoft.Foo/stable/2020-01-01/foo.json", "Foo"): {
+			Name: "Foo",
+			Properties: SWGSchemaProperties{
+				"a": {TFLinks: link},
+				"b": {},
+				"c": {IsGranted: true},
+			},
+		},
+		NewSWGSchemaAddr(`Microsoft.Bar/stable/2021-01-01/b"ar.json`, "Bar"): {
+			Name:       "Bar",
+			IsGranted:  true,
+			Properties: SWGSchemaProperties{"x": {}},
+		},
+	}
+
+	var sb strings.Builder
+	require.NoError(t, WriteSWGCoverageMetrics(&sb, schemas))
+	require.Equal(t, `# TYPE azurerm_insight_swagger_coverage gauge
+# HELP azurerm_insight_swagger_coverage The property coverage of the swagger schema, the granted properties are not counted.
+azurerm_insight_swagger_coverage{rp="Microsoft.Bar",api_version="2021-01-01",swagger_file="Microsoft.Bar/stable/2021-01-01/b\"ar.json",schema="Bar"} 0
+azurerm_insight_swagger_coverage{rp="Microsoft.Foo",api_version="2020-01-01",swagger_file="Microsoft.Foo/stable/2020-01-01/foo.json",schema="Foo"} 0.5
+# TYPE azurerm_insight_swagger_properties gauge
+# HELP azurerm_insight_swagger_properties The amount of the properties of the swagger schema, by status.
+azurerm_insight_swagger_properties{rp="Microsoft.Bar",api_version="2021-01-01",swagger_file="Microsoft.Bar/stable/2021-01-01/b\"ar.json",schema="Bar",status="covered"} 0
+azurerm_insight_swagger_properties{rp="Microsoft.Bar",api_version="2021-01-01",swagger_file="Microsoft.Bar/stable/2021-01-01/b\"ar.json",schema="Bar",status="uncovered"} 0
+azurerm_insight_swagger_properties{rp="Microsoft.Bar",api_version="2021-01-01",swagger_file="Microsoft.Bar/stable/2021-01-01/b\"ar.json",schema="Bar",status="granted"} 1
+azurerm_insight_swagger_properties{rp="Microsoft.Foo",api_version="2020-01-01",swagger_file="Microsoft.Foo/stable/2020-01-01/foo.json",schema="Foo",status="covered"} 1
+azurerm_insight_swagger_properties{rp="Microsoft.Foo",api_version="2020-01-01",swagger_file="Microsoft.Foo/stable/2020-01-01/foo.json",schema="Foo",status="uncovered"} 1
+azurerm_insight_swagger_properties{rp="Microsoft.Foo",api_version="2020-01-01",swagger_file="Microsoft.Foo/stable/2020-01-01/foo.json",schema="Foo",status="granted"} 1
+# TYPE azurerm_insight_swagger_resource_provider_coverage gauge
+# HELP azurerm_insight_swagger_resource_provider_coverage The property coverage of the resource provider, the granted properties are not counted.
+azurerm_insight_swagger_resource_provider_coverage{rp="Microsoft.Bar"} 0
+azurerm_insight_swagger_resource_provider_coverage{rp="Microsoft.Foo"} 0.5
+# TYPE azurerm_insight_swagger_overall_coverage gauge
+# HELP azurerm_insight_swagger_overall_coverage The property coverage of all the swagger schemas, the granted properties are not counted.
+azurerm_insight_swagger_overall_coverage 0.3333333333333333
+# EOF
+`, sb.String())
+}
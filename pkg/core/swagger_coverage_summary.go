@@ -0,0 +1,232 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// TerraformResourceDocURLPrefix is the URL prefix of the documents of the AzureRM provider resources.
+	TerraformResourceDocURLPrefix = "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/"
+	// TerraformDataSourceDocURLPrefix is the URL prefix of the documents of the AzureRM provider data sources.
+	TerraformDataSourceDocURLPrefix = "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/data-sources/"
+)
+
+// TerraformResourceDocURL returns the document URL of the AzureRM provider resource (e.g. "azurerm_virtual_network"), or the
+// data source (e.g. "data_azurerm_virtual_network").
+func TerraformResourceDocURL(resourceName string) string {
+	if strings.HasPrefix(resourceName, "data_azurerm_") {
+		return TerraformDataSourceDocURLPrefix + strings.TrimPrefix(resourceName, "data_azurerm_")
+	}
+	return TerraformResourceDocURLPrefix + strings.TrimPrefix(resourceName, "azurerm_")
+}
+
+// coverageBarWidth is the number of the characters of the coverage bar.
+const coverageBarWidth = 20
+
+// SWGCoverageStat is the amount of the covered properties and the total properties, the granted properties are not counted.
+type SWGCoverageStat struct {
+	Covered int `json:"covered"`
+	Total   int `json:"total"`
+}
+
+// Coverage returns the coverage (<=1), which is 0 if there is no property.
+func (s SWGCoverageStat) Coverage() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Covered) / float64(s.Total)
+}
+
+func (s *SWGCoverageStat) add(o SWGCoverageStat) {
+	s.Covered += o.Covered
+	s.Total += o.Total
+}
+
+// Bar renders the coverage as a text bar followed by the percentage (e.g. "██████████░░░░░░░░░░ 50.00% (1/2)").
+func (s SWGCoverageStat) Bar() string {
+	filled := int(s.Coverage()*coverageBarWidth + 0.5)
+	return fmt.Sprintf("%s%s %.2f%% (%d/%d)", strings.Repeat("█", filled), strings.Repeat("░", coverageBarWidth-filled), s.Coverage()*100, s.Covered, s.Total)
+}
+
+// SWGUncoveredProperty is a property that is neither covered nor granted.
+type SWGUncoveredProperty struct {
+	Property    string `json:"property"`
+	Description string `json:"description,omitempty"`
+}
+
+// SWGSchemaCoverageSummary is the coverage summary of a swagger schema.
+type SWGSchemaCoverageSummary struct {
+	Schema SWGSchemaAddr `json:"schema"`
+	SWGCoverageStat
+
+	// Whether the schema is granted as a whole.
+	Granted bool `json:"granted,omitempty"`
+
+	// The terraform resources linked to the properties of the schema.
+	TFResources []string `json:"tf_resources,omitempty"`
+
+	// The properties that are neither covered nor granted, which are not recorded if the schema is granted as a whole.
+	Uncovered []SWGUncoveredProperty `json:"uncovered,omitempty"`
+}
+
+// SWGAPIVersionCoverageSummary is the coverage summary of an API version of a resource provider.
+type SWGAPIVersionCoverageSummary struct {
+	// The directory of the API version, relative to the resource provider directory (e.g. "resource-manager/Microsoft.Network/stable/2020-05-01").
+	APIVersion string `json:"api_version"`
+	SWGCoverageStat
+	Schemas []SWGSchemaCoverageSummary `json:"schemas"`
+}
+
+// SWGResourceProviderCoverageSummary is the coverage summary of a resource provider.
+type SWGResourceProviderCoverageSummary struct {
+	ResourceProvider string `json:"resource_provider"`
+	SWGCoverageStat
+	APIVersions []SWGAPIVersionCoverageSummary `json:"api_versions"`
+}
+
+// SWGCoverageSummary is the coverage summary of the swagger schemas, organized as resource providers, API versions and
+// swagger schemas, each ordered by its name.
+type SWGCoverageSummary struct {
+	SWGCoverageStat
+	ResourceProviders []SWGResourceProviderCoverageSummary `json:"resource_providers"`
+}
+
+// NewSWGCoverageSummary builds the SWGCoverageSummary from the swagger schemas.
+func NewSWGCoverageSummary(schemas map[SWGSchemaAddr]*SWGSchema) SWGCoverageSummary {
+	addrs := make([]SWGSchemaAddr, 0, len(schemas))
+	for addr := range schemas {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+
+	summary := SWGCoverageSummary{ResourceProviders: []SWGResourceProviderCoverageSummary{}}
+	for _, addr := range addrs {
+		schemaSummary := newSWGSchemaCoverageSummary(addr, schemas[addr])
+
+		rp := addr.ResourceProvider()
+		if n := len(summary.ResourceProviders); n == 0 || summary.ResourceProviders[n-1].ResourceProvider != rp {
+			summary.ResourceProviders = append(summary.ResourceProviders, SWGResourceProviderCoverageSummary{ResourceProvider: rp})
+		}
+		rpSummary := &summary.ResourceProviders[len(summary.ResourceProviders)-1]
+
+		apiVersion := strings.TrimPrefix(filepath.ToSlash(filepath.Dir(addr.SwaggerRelPath())), rp+"/")
+		if n := len(rpSummary.APIVersions); n == 0 || rpSummary.APIVersions[n-1].APIVersion != apiVersion {
+			rpSummary.APIVersions = append(rpSummary.APIVersions, SWGAPIVersionCoverageSummary{APIVersion: apiVersion})
+		}
+		apiSummary := &rpSummary.APIVersions[len(rpSummary.APIVersions)-1]
+
+		apiSummary.Schemas = append(apiSummary.Schemas, schemaSummary)
+		apiSummary.add(schemaSummary.SWGCoverageStat)
+		rpSummary.add(schemaSummary.SWGCoverageStat)
+		summary.add(schemaSummary.SWGCoverageStat)
+	}
+	return summary
+}
+
+func newSWGSchemaCoverageSummary(addr SWGSchemaAddr, schema *SWGSchema) SWGSchemaCoverageSummary {
+	summary := SWGSchemaCoverageSummary{
+		Schema:  addr,
+		Granted: schema.IsGranted,
+	}
+	summary.Covered, summary.Total = schema.propertyCoverage()
+
+	propAddrs := make([]string, 0, len(schema.Properties))
+	for propAddr := range schema.Properties {
+		propAddrs = append(propAddrs, propAddr)
+	}
+	sort.Strings(propAddrs)
+
+	tfResources := map[string]bool{}
+	for _, propAddr := range propAddrs {
+		prop := schema.Properties[propAddr]
+		for _, link := range prop.TFLinks {
+			if link.Prop.ResourceName != "" {
+				tfResources[link.Prop.ResourceName] = true
+			}
+		}
+		if schema.IsGranted || prop.IsGranted || len(prop.TFLinks) != 0 {
+			continue
+		}
+		summary.Uncovered = append(summary.Uncovered, SWGUncoveredProperty{
+			Property:    propAddr,
+			Description: prop.Description(),
+		})
+	}
+	for res := range tfResources {
+		summary.TFResources = append(summary.TFResources, res)
+	}
+	sort.Strings(summary.TFResources)
+	return summary
+}
+
+// WriteMarkdown writes the SWGCoverageSummary in Markdown, which contains the tables of the resource providers, the API
+// versions and the swagger schemas, together with the uncovered properties of each swagger schema.
+func (summary SWGCoverageSummary) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("# Swagger Schema Coverage\n\n")
+	fmt.Fprintf(&sb, "Overall: `%s`\n\n", summary.Bar())
+
+	sb.WriteString("| Resource Provider | API Versions | Coverage |\n|---|---|---|\n")
+	for _, rp := range summary.ResourceProviders {
+		fmt.Fprintf(&sb, "| [%s](#%s) | %d | `%s` |\n", rp.ResourceProvider, markdownAnchor(rp.ResourceProvider), len(rp.APIVersions), rp.Bar())
+	}
+
+	for _, rp := range summary.ResourceProviders {
+		fmt.Fprintf(&sb, "\n## %s\n\n", rp.ResourceProvider)
+		sb.WriteString("| API Version | Schemas | Coverage |\n|---|---|---|\n")
+		for _, api := range rp.APIVersions {
+			fmt.Fprintf(&sb, "| %s | %d | `%s` |\n", api.APIVersion, len(api.Schemas), api.Bar())
+		}
+
+		sb.WriteString("\n| Schema | API Version | Terraform Resources | Coverage |\n|---|---|---|---|\n")
+		for _, api := range rp.APIVersions {
+			for _, schema := range api.Schemas {
+				var links []string
+				for _, res := range schema.TFResources {
+					links = append(links, fmt.Sprintf("[%s](%s)", res, TerraformResourceDocURL(res)))
+				}
+				coverage := fmt.Sprintf("`%s`", schema.Bar())
+				if schema.Granted {
+					coverage += " (granted)"
+				}
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", schema.Schema.SchemaName(), api.APIVersion, strings.Join(links, "<br>"), coverage)
+			}
+		}
+
+		for _, api := range rp.APIVersions {
+			for _, schema := range api.Schemas {
+				if len(schema.Uncovered) == 0 {
+					continue
+				}
+				fmt.Fprintf(&sb, "\n<details>\n<summary>Uncovered properties of <code>%s</code> (%d)</summary>\n\n", schema.Schema, len(schema.Uncovered))
+				sb.WriteString("| Property | Description |\n|---|---|\n")
+				for _, prop := range schema.Uncovered {
+					fmt.Fprintf(&sb, "| `%s` | %s |\n", prop.Property, markdownEscapeTableCell(prop.Description))
+				}
+				sb.WriteString("\n</details>\n")
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// markdownAnchor returns the anchor of the Markdown heading, following the GitHub convention.
+func markdownAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			sb.WriteRune(r)
+		case r == ' ':
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSWGCoverageStat_Bar(t *testing.T) {
+	cases := []struct {
+		stat   SWGCoverageStat
+		expect string
+	}{
+		{SWGCoverageStat{}, "░░░░░░░░░░░░░░░░░░░░ 0.00% (0/0)"},
+		{SWGCoverageStat{Covered: 1, Total: 2}, "██████████░░░░░░░░░░ 50.00% (1/2)"},
+		{SWGCoverageStat{Covered: 3, Total: 3}, "████████████████████ 100.00% (3/3)"},
+	}
+	for idx, c := range cases {
+		require.Equal(t, c.expect, c.stat.Bar(), idx)
+	}
+}
+
+func TestTerraformResourceDocURL(t *testing.T) {
+	cases := []struct {
+		name   string
+		expect string
+	}{
+		{"azurerm_virtual_network", "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/virtual_network"},
+		{"data_azurerm_virtual_network", "https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/data-sources/virtual_network"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expect, TerraformResourceDocURL(c.name), c.name)
+	}
+}
+
+func TestNewSWGCoverageSummary(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	rel := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+
+	foo, err := NewSWGSchema(specBasePath, rel, "Foo")
+	require.NoError(t, err)
+	require.NoError(t, foo.ExpandAll(2))
+	foo.Properties["id"].TFLinks = TFLinks{{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:id")}}
+	foo.Properties["properties.p1"].TFLinks = TFLinks{{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo_bar:p1")}}
+	foo.Properties["etag"].grant(GrantInfo{Comment: "read only"}, "foo.json", "")
+
+	bar, err := NewSWGSchema(specBasePath, rel, "Bar")
+	require.NoError(t, err)
+	require.NoError(t, bar.ExpandAll(2))
+	bar.Grant(GrantInfo{Comment: "internal"}, "foo.json")
+
+	fooAddr, barAddr := NewSWGSchemaAddr(rel, "Foo"), NewSWGSchemaAddr(rel, "Bar")
+	summary := NewSWGCoverageSummary(map[SWGSchemaAddr]*SWGSchema{fooAddr: foo, barAddr: bar})
+	require.Equal(t, SWGCoverageSummary{
+		SWGCoverageStat: SWGCoverageStat{Covered: 2, Total: 5},
+		ResourceProviders: []SWGResourceProviderCoverageSummary{
+			{
+				ResourceProvider: "Microsoft.Foo",
+				SWGCoverageStat:  SWGCoverageStat{Covered: 2, Total: 5},
+				APIVersions: []SWGAPIVersionCoverageSummary{
+					{
+						APIVersion:      "stable/2020-01-01",
+						SWGCoverageStat: SWGCoverageStat{Covered: 2, Total: 5},
+						Schemas: []SWGSchemaCoverageSummary{
+							{
+								Schema:          barAddr,
+								SWGCoverageStat: SWGCoverageStat{Covered: 0, Total: 1},
+								Granted:         true,
+							},
+							{
+								Schema:          fooAddr,
+								SWGCoverageStat: SWGCoverageStat{Covered: 2, Total: 4},
+								TFResources:     []string{"azurerm_foo", "azurerm_foo_bar"},
+								Uncovered: []SWGUncoveredProperty{
+									{Property: "old", Description: "The old property."},
+									{Property: "properties.p2", Description: "The p2 | property."},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, summary)
+
+	var sb strings.Builder
+	require.NoError(t, summary.WriteMarkdown(&sb))
+	md := sb.String()
+	require.Contains(t, md, "| [Microsoft.Foo](#microsoftfoo) | 1 | `████████░░░░░░░░░░░░ 40.00% (2/5)` |\n")
+	require.Contains(t, md, "| Foo | stable/2020-01-01 | [azurerm_foo](https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/foo)<br>[azurerm_foo_bar](https://registry.terraform.io/providers/hashicorp/azurerm/latest/docs/resources/foo_bar) | `██████████░░░░░░░░░░ 50.00% (2/4)` |\n")
+	require.Contains(t, md, "| Bar | stable/2020-01-01 |  | `░░░░░░░░░░░░░░░░░░░░ 0.00% (0/1)` (granted) |\n")
+	require.Contains(t, md, "| `properties.p2` | The p2 \\| property. |\n")
+}
@@ -2,56 +2,373 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
-type SWGGrant map[SWGSchemaAddr]SWGSchemaGrant
+// GrantCategory categorizes the reason why a swagger schema/property is granted.
+type GrantCategory string
 
-type SWGSchemaGrant struct {
-	// The grant comment why the schema is granted.
+const (
+	// The swagger schema/property is deprecated by the service.
+	GrantCategoryDeprecated GrantCategory = "deprecated"
+	// The swagger property is read-only and carries no value for the users (e.g. "etag", "provisioningState").
+	GrantCategoryReadOnlyNoise GrantCategory = "read_only_noise"
+	// The swagger schema/property is deliberately not exposed by the provider.
+	GrantCategoryByDesign GrantCategory = "by_design"
+	// The swagger schema/property is not supported by the Go SDK used by the provider.
+	GrantCategorySDKLimitation GrantCategory = "sdk_limitation"
+	// The swagger schema/property can't be supported until some upstream issue (e.g. of the service or the API) is fixed.
+	GrantCategoryBlockedUpstream GrantCategory = "blocked_upstream"
+)
+
+var grantCategories = []GrantCategory{
+	GrantCategoryDeprecated,
+	GrantCategoryReadOnlyNoise,
+	GrantCategoryByDesign,
+	GrantCategorySDKLimitation,
+	GrantCategoryBlockedUpstream,
+}
+
+// GrantDateLayout is the layout of the review-by date of a grant.
+const GrantDateLayout = "2006-01-02"
+
+// GrantInfo is the metadata of a grant.
+type GrantInfo struct {
+	// The grant comment why the schema/property is granted.
 	Comment string `json:",omitempty"`
 
-	// Property grant map, whose key is the propertyaddr, whose value is the grant comment.
-	Properties map[string]string `json:",omitempty"`
+	// The category of the grant, which is one of the GrantCategory.
+	Category GrantCategory `json:",omitempty"`
+
+	// The URL of the issue that tracks the grant.
+	Issue string `json:",omitempty"`
+
+	// The owner who is responsible for the grant.
+	Owner string `json:",omitempty"`
+
+	// The date (in the form of GrantDateLayout) by which the grant should be reviewed again, after which the grant is
+	// regarded as expired.
+	ReviewBy string `json:",omitempty"`
+}
+
+// Validate validates the category and the review-by date of the GrantInfo.
+func (info GrantInfo) Validate() error {
+	if info.Category != "" {
+		valid := false
+		for _, category := range grantCategories {
+			if info.Category == category {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown grant category %q (expect one of %v)", info.Category, grantCategories)
+		}
+	}
+	if info.ReviewBy != "" {
+		if _, err := time.Parse(GrantDateLayout, info.ReviewBy); err != nil {
+			return fmt.Errorf("invalid review-by date %q (expect the form of %q)", info.ReviewBy, GrantDateLayout)
+		}
+	}
+	return nil
+}
+
+// IsExpired tells whether the review-by date of the grant is before the date of now. A grant without (valid) review-by date never expires.
+func (info GrantInfo) IsExpired(now time.Time) bool {
+	if info.ReviewBy == "" {
+		return false
+	}
+	reviewBy, err := time.Parse(GrantDateLayout, info.ReviewBy)
+	if err != nil {
+		return false
+	}
+	y, m, d := now.UTC().Date()
+	return reviewBy.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// SWGPropertyGrant is the grant of a swagger property. It is either in the form of a string, which is the grant comment,
+// or an object of the GrantInfo.
+type SWGPropertyGrant struct {
+	GrantInfo
+
+	// The schema whose grant this property grant is inherited from, empty if it is not inherited.
+	inheritedFrom SWGSchemaAddr
+
+	// The path of the grant file where the property grant is loaded from, relative to the grant base directory.
+	file string
+}
+
+func (g SWGPropertyGrant) MarshalJSON() ([]byte, error) {
+	if g.GrantInfo == (GrantInfo{Comment: g.Comment}) {
+		return json.Marshal(g.Comment)
+	}
+	return json.Marshal(g.GrantInfo)
+}
+
+func (g *SWGPropertyGrant) UnmarshalJSON(b []byte) error {
+	var comment string
+	if err := json.Unmarshal(b, &comment); err == nil {
+		*g = SWGPropertyGrant{GrantInfo: GrantInfo{Comment: comment}}
+		return nil
+	}
+	var info GrantInfo
+	if err := json.Unmarshal(b, &info); err != nil {
+		return err
+	}
+	*g = SWGPropertyGrant{GrantInfo: info}
+	return nil
+}
+
+// SWGGrantOptions tunes how the SWGGrant is applied onto the SWGSchemas.
+type SWGGrantOptions struct {
+	// Whether the swagger schemas whose swagger spec has no grant file inherit the grants from the closest older API version.
+	// Regardless of this option, a schema grant can always opt in to inherit via SWGSchemaGrant.Inherit.
+	InheritMissing bool
+}
+
+// SWGGrant is the grants of the swagger schemas/properties which are not fit to be included in Terraform.
+type SWGGrant struct {
+	// The grants of each swagger schema, which are loaded from the grant file named after the swagger spec.
+	Schemas map[SWGSchemaAddr]SWGSchemaGrant
+
+	// The pattern grants that apply to every swagger schema underneath their directories, ordered by precedence.
+	Patterns []SWGPatternGrant
+
+	// The directory grants that grant every swagger schema underneath their directories, ordered by precedence.
+	Directories []SWGDirectoryGrant
+}
+
+type SWGSchemaGrant struct {
+	// The grant metadata of the schema, which only takes effect when the whole schema is granted.
+	GrantInfo
+
+	// Whether to inherit the grants of the same schema from the closest older API version. The inherited property grants
+	// only apply to the properties that still exist with the same shape, and are overridden by the property grants here.
+	Inherit bool `json:",omitempty"`
+
+	// Property grant map, whose key is the propertyaddr, whose value is the property grant.
+	Properties map[string]SWGPropertyGrant `json:",omitempty"`
+
+	// The path of the grant file where the grant is loaded from, relative to the grant base directory.
+	file string
 }
 
 func (g SWGSchemaGrant) IsSchemaGranted() bool {
 	return len(g.Properties) == 0
 }
 
+// inherit merges the grant inherited from the older API version into this grant.
+func (g SWGSchemaGrant) inherit(from SWGSchemaAddr, inherited SWGSchemaGrant) SWGSchemaGrant {
+	out := SWGSchemaGrant{
+		GrantInfo: g.GrantInfo,
+		file:      g.file,
+	}
+	if inherited.IsSchemaGranted() {
+		if len(g.Properties) != 0 {
+			out.Properties = g.Properties
+			return out
+		}
+		if g.GrantInfo == (GrantInfo{}) {
+			out.GrantInfo = inherited.GrantInfo
+			out.file = inherited.file
+		}
+		return out
+	}
+
+	out.Properties = map[string]SWGPropertyGrant{}
+	for propertyAddr, propertyGrant := range inherited.Properties {
+		if propertyGrant.inheritedFrom == "" {
+			propertyGrant.inheritedFrom = from
+			propertyGrant.file = inherited.file
+		}
+		out.Properties[propertyAddr] = propertyGrant
+	}
+	for propertyAddr, propertyGrant := range g.Properties {
+		out.Properties[propertyAddr] = propertyGrant
+	}
+	return out
+}
+
+// Validate validates the grant metadata of the schema and each property. The returned error (if any) is a Diagnostics,
+// whose pointer is relative to the schema grant.
+func (g SWGSchemaGrant) Validate() error {
+	var diags Diagnostics
+	if err := g.GrantInfo.Validate(); err != nil {
+		diags = append(diags, Diagnostic{Err: err})
+	}
+	for _, propertyAddr := range sortedGrantProperties(g.Properties) {
+		if err := g.Properties[propertyAddr].Validate(); err != nil {
+			diags = append(diags, Diagnostic{
+				Pointer: jsonPointer("Properties", propertyAddr),
+				Err:     fmt.Errorf("grant of property %q: %v", propertyAddr, err),
+			})
+		}
+	}
+	return diags.Err()
+}
+
+func sortedGrantProperties(m map[string]SWGPropertyGrant) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolve resolves the effective grant of the schema, taking the inheritance from the older API versions into account.
+func (g SWGGrant) resolve(addr SWGSchemaAddr, opts SWGGrantOptions) (SWGSchemaGrant, bool) {
+	schemaGrant, ok := g.Schemas[addr]
+	if ok && !schemaGrant.Inherit {
+		return schemaGrant, true
+	}
+	if !ok && (!opts.InheritMissing || g.hasGrantFile(addr.SwaggerRelPath())) {
+		return SWGSchemaGrant{}, false
+	}
+	olderAddr, found := g.closestOlderSchema(addr)
+	if !found {
+		return schemaGrant, ok
+	}
+	inherited, found := g.resolve(olderAddr, opts)
+	if !found {
+		return schemaGrant, ok
+	}
+	return schemaGrant.inherit(olderAddr, inherited), true
+}
+
+func (g SWGGrant) hasGrantFile(swaggerRelPath string) bool {
+	for addr := range g.Schemas {
+		if addr.SwaggerRelPath() == swaggerRelPath {
+			return true
+		}
+	}
+	return false
+}
+
+// closestOlderSchema finds the grant of the same schema of the same swagger spec, in the closest older API version.
+// The API version is the parent directory of the swagger spec (e.g. "Microsoft.Network/stable/2020-05-01/virtualNetwork.json").
+func (g SWGGrant) closestOlderSchema(addr SWGSchemaAddr) (SWGSchemaAddr, bool) {
+	versionDir, fileName := filepath.Split(addr.SwaggerRelPath())
+	versionDir = filepath.Clean(versionDir)
+	baseDir, version := filepath.Split(versionDir)
+
+	var (
+		closest        SWGSchemaAddr
+		closestVersion string
+	)
+	for candidate := range g.Schemas {
+		if candidate.SchemaName() != addr.SchemaName() {
+			continue
+		}
+		candidateVersionDir, candidateFileName := filepath.Split(candidate.SwaggerRelPath())
+		candidateBaseDir, candidateVersion := filepath.Split(filepath.Clean(candidateVersionDir))
+		if candidateFileName != fileName || candidateBaseDir != baseDir {
+			continue
+		}
+		if candidateVersion < version && candidateVersion > closestVersion {
+			closest, closestVersion = candidate, candidateVersion
+		}
+	}
+	return closest, closest != ""
+}
+
 // NewSWGGrantFromFiles construct a SWGGrant from a grantBaseDir which contains the
 // folder layout as defined by the SWGSchemaAddr.
+// The grant files are either in JSON or YAML, a YAML grant file (e.g. "foo/bar.yaml") grants the
+// schemas of the swagger of the same name (e.g. "foo/bar.json").
+// Additionally, a pattern grant file (i.e. "_patterns.json" or "_patterns.yaml") in any directory level maps the
+// property address patterns to their grants, which apply to every schema underneath that directory. While a directory
+// grant file (i.e. "_grant.json" or "_grant.yaml") grants every schema underneath that directory as a whole.
+// A grant file that fails to be decoded doesn't stop the others from being loaded, the returned error (if any)
+// is a Diagnostics that records every such file.
 func NewSWGGrantFromFiles(grantBaseDir string) (SWGGrant, error) {
-	var swgGrant SWGGrant = map[SWGSchemaAddr]SWGSchemaGrant{}
-	return swgGrant, filepath.Walk(grantBaseDir, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() ||
-			(!strings.HasSuffix(info.Name(), ".json") &&
-				!strings.HasSuffix(info.Name(), ".yaml") &&
-				!strings.HasSuffix(info.Name(), ".yml")) {
+	swgGrant := SWGGrant{
+		Schemas: map[SWGSchemaAddr]SWGSchemaGrant{},
+	}
+	var diags Diagnostics
+	err := filepath.Walk(grantBaseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
 			return err
 		}
-
-		infileSwgGrant := map[string]SWGSchemaGrant{}
+		if info.IsDir() {
+			return nil
+		}
+		format, ok := FileFormatOf(path)
+		if !ok {
+			return nil
+		}
 
 		b, err := ioutil.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		if err := json.Unmarshal(b, &infileSwgGrant); err != nil {
-			return err
-		}
 
-		relPath, err := filepath.Rel( grantBaseDir, path)
+		relPath, err := filepath.Rel(grantBaseDir, path)
 		if err != nil {
 			return err
 		}
 
+		if isDirectoryGrantFile(path) {
+			var info GrantInfo
+			if err := DecodeFile(FileKindSWGDirectoryGrant, format, b, &info); err != nil {
+				diags = append(diags, newFileDiagnostics(path, b, err)...)
+				return nil
+			}
+			if err := info.Validate(); err != nil {
+				diags = append(diags, Diagnostics{{Err: err}}.WithFile(path, b)...)
+			}
+			swgGrant.Directories = append(swgGrant.Directories, SWGDirectoryGrant{
+				Dir:       filepath.Dir(relPath),
+				GrantInfo: info,
+				file:      relPath,
+			})
+			return nil
+		}
+
+		if isPatternGrantFile(path) {
+			patternGrants, err := loadPatternGrantFile(format, relPath, b)
+			if fileDiags, ok := err.(Diagnostics); ok {
+				diags = append(diags, fileDiags.WithFile(path, b)...)
+			} else if err != nil {
+				diags = append(diags, newFileDiagnostic(path, b, err))
+				return nil
+			}
+			swgGrant.Patterns = append(swgGrant.Patterns, patternGrants...)
+			return nil
+		}
+
+		infileSwgGrant := map[string]SWGSchemaGrant{}
+		if err := DecodeFile(FileKindSWGGrant, format, b, &infileSwgGrant); err != nil {
+			diags = append(diags, newFileDiagnostics(path, b, err)...)
+			return nil
+		}
+
+		swaggerRelPath := strings.TrimSuffix(relPath, filepath.Ext(relPath)) + ".json"
+
+		var fileDiags Diagnostics
 		for schemaName, schemaGrant := range infileSwgGrant {
-			swgGrant[NewSWGSchemaAddr(relPath, schemaName)] = schemaGrant
+			if err := schemaGrant.Validate(); err != nil {
+				for _, diag := range err.(Diagnostics) {
+					diag.Pointer = jsonPointer(schemaName) + diag.Pointer
+					fileDiags = append(fileDiags, diag)
+				}
+			}
+			schemaGrant.file = relPath
+			swgGrant.Schemas[NewSWGSchemaAddr(swaggerRelPath, schemaName)] = schemaGrant
 		}
+		diags = append(diags, fileDiags.WithFile(path, b)...)
 		return nil
 	})
+	if err != nil {
+		diags.Append(fmt.Errorf("walking the swagger grant directory %q: %v", grantBaseDir, err))
+	}
+	sortPatternGrants(swgGrant.Patterns)
+	sortDirectoryGrants(swgGrant.Directories)
+	return swgGrant, diags.Err()
 }
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// swgDirectoryGrantFileName is the name (without the extension) of the directory grant file, which can reside in any directory
+// level of the grant base directory (e.g. a resource provider, or an API version).
+const swgDirectoryGrantFileName = "_grant"
+
+// SWGDirectoryGrant grants every swagger schema underneath the directory as a whole, e.g. the resource providers or the API
+// versions that are never going to be supported in Terraform.
+type SWGDirectoryGrant struct {
+	// The directory relative to the grant base directory (e.g. "network/resource-manager/Microsoft.Network/preview/2020-05-01").
+	Dir string
+
+	GrantInfo
+
+	// The path of the directory grant file where the grant is loaded from, relative to the grant base directory.
+	file string
+}
+
+// Source returns the directory grant file (relative to the grant base directory) where the grant comes from.
+func (g SWGDirectoryGrant) Source() string {
+	return g.file
+}
+
+func isDirectoryGrantFile(path string) bool {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) == swgDirectoryGrantFileName
+}
+
+// covers tells whether the directory (relative to the grant base directory) is the directory of the grant, or underneath it.
+func (g SWGDirectoryGrant) covers(dir string) bool {
+	dir = filepath.Clean(dir)
+	return g.Dir == "." || dir == g.Dir || strings.HasPrefix(dir, g.Dir+string(filepath.Separator))
+}
+
+// sortDirectoryGrants sorts the SWGDirectoryGrants so that the grants of the deeper directories precede the ones of their ancestor
+// directories.
+func sortDirectoryGrants(grants []SWGDirectoryGrant) {
+	sort.Slice(grants, func(i, j int) bool {
+		if di, dj := dirDepth(grants[i].Dir), dirDepth(grants[j].Dir); di != dj {
+			return di > dj
+		}
+		return grants[i].Dir < grants[j].Dir
+	})
+}
+
+// DirectoryGrant finds the grant of the directory (relative to the grant base directory, e.g. the path of a resource provider
+// or an API version), which is granted either by itself or by one of its ancestor directories, the closest grant wins.
+func (g SWGGrant) DirectoryGrant(dir string) (SWGDirectoryGrant, bool) {
+	for _, dirGrant := range g.Directories {
+		if dirGrant.covers(dir) {
+			return dirGrant, true
+		}
+	}
+	return SWGDirectoryGrant{}, false
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSWGGrant_DirectoryGrant(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swggrant, err := NewSWGGrantFromFiles(filepath.Join(pwd, "testdata", "swagger_grants_directory"))
+	require.NoError(t, err)
+
+	cases := []struct {
+		dir          string
+		expectSource string
+	}{
+		{"Microsoft.Foo", ""},
+		{filepath.Join("Microsoft.Foo", "stable", "2020-01-01"), ""},
+		{filepath.Join("Microsoft.Foo", "stable", "2020-02-01"), filepath.Join("Microsoft.Foo", "stable", "2020-02-01", "_grant.yaml")},
+		{"Microsoft.Bar", filepath.Join("Microsoft.Bar", "_grant.json")},
+		{filepath.Join("Microsoft.Bar", "stable", "2020-01-01"), filepath.Join("Microsoft.Bar", "_grant.json")},
+		{"Microsoft.BarBaz", ""},
+	}
+	for idx, c := range cases {
+		dirGrant, ok := swggrant.DirectoryGrant(c.dir)
+		require.Equal(t, c.expectSource != "", ok, idx)
+		require.Equal(t, c.expectSource, dirGrant.Source(), idx)
+	}
+}
+
+func TestSWGSchemas_Grant_Directory(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	swggrant, err := NewSWGGrantFromFiles(filepath.Join(pwd, "testdata", "swagger_grants_directory"))
+	require.NoError(t, err)
+
+	v1 := NewSWGSchemaAddr(filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json"), "Foo")
+	v2 := NewSWGSchemaAddr(filepath.Join("Microsoft.Foo", "stable", "2020-02-01", "foo.json"), "Foo")
+	swgschemas := NewSGWSchemas()
+	for _, addr := range []SWGSchemaAddr{v1, v2} {
+		schema, err := NewSWGSchema(specBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+		require.NoError(t, err)
+		swgschemas.Set(addr, schema)
+	}
+	require.NoError(t, swgschemas.Grant(specBasePath, swggrant, SWGGrantOptions{}))
+
+	require.False(t, swgschemas.Get(v1).IsGranted)
+	schema := swgschemas.Get(v2)
+	require.True(t, schema.IsGranted)
+	require.Equal(t, GrantInfo{Comment: "this API version is never going to be supported", Category: GrantCategoryDeprecated}, schema.GrantInfo())
+	require.Equal(t, filepath.Join("Microsoft.Foo", "stable", "2020-02-01", "_grant.yaml"), schema.GrantSource)
+}
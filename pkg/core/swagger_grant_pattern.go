@@ -0,0 +1,168 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// swgPatternGrantFileName is the name (without the extension) of the pattern grant file, which can reside in any directory
+// level of the grant base directory.
+const swgPatternGrantFileName = "_patterns"
+
+// swgPatternSegmentSep separates the segments of the pattern, which is the same as the swagger property address.
+const swgPatternSegmentSep = "."
+
+// swgPatternAnySegments is the pattern segment that matches zero or more segments of the property address.
+const swgPatternAnySegments = "**"
+
+// SWGPatternGrant grants the properties, whose address matches the pattern, of every swagger schema underneath the directory.
+// The pattern is a swagger schema relative property address, whose segments are matched via path.Match (e.g. "systemData.*"),
+// additionally, a "**" segment matches zero or more segments (e.g. "**.provisioningState").
+type SWGPatternGrant struct {
+	// The directory relative to the grant base directory (e.g. "network/resource-manager/Microsoft.Network"), which is "." for the
+	// grant base directory itself.
+	Dir string
+
+	Pattern string
+
+	GrantInfo
+
+	// The path of the pattern grant file where the grant is loaded from, relative to the grant base directory.
+	file string
+}
+
+func isPatternGrantFile(path string) bool {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) == swgPatternGrantFileName
+}
+
+// validatePattern validates the pattern of the SWGPatternGrant.
+func validatePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	for _, segment := range strings.Split(pattern, swgPatternSegmentSep) {
+		if segment == "" {
+			return fmt.Errorf("invalid pattern %q: empty segment", pattern)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// appliesTo tells whether the swagger schema is underneath the directory of the SWGPatternGrant.
+func (g SWGPatternGrant) appliesTo(schemaAddr SWGSchemaAddr) bool {
+	return g.Dir == "." || strings.HasPrefix(schemaAddr.SwaggerRelPath(), g.Dir+string(filepath.Separator))
+}
+
+// Match tells whether the swagger schema relative property address matches the pattern.
+func (g SWGPatternGrant) Match(propertyAddr string) bool {
+	if propertyAddr == "" {
+		return false
+	}
+	return matchPatternSegments(strings.Split(g.Pattern, swgPatternSegmentSep), strings.Split(propertyAddr, swgPatternSegmentSep))
+}
+
+func matchPatternSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == swgPatternAnySegments {
+		for i := 0; i <= len(segments); i++ {
+			if matchPatternSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchPatternSegments(pattern[1:], segments[1:])
+}
+
+// dirDepth returns the depth of the directory relative to the grant base directory, which is 0 for the grant base directory itself.
+func dirDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, string(filepath.Separator)) + 1
+}
+
+// sortPatternGrants sorts the SWGPatternGrants by their precedence: the grants in a deeper directory precede the ones in its
+// ancestor directories, and the grants in the same directory are ordered by their patterns.
+func sortPatternGrants(grants []SWGPatternGrant) {
+	sort.Slice(grants, func(i, j int) bool {
+		if di, dj := dirDepth(grants[i].Dir), dirDepth(grants[j].Dir); di != dj {
+			return di > dj
+		}
+		if grants[i].Dir != grants[j].Dir {
+			return grants[i].Dir < grants[j].Dir
+		}
+		return grants[i].Pattern < grants[j].Pattern
+	})
+}
+
+// literalPrefix returns the leading segments of the pattern that have no wildcard (e.g. "systemData" of "systemData.*"). It
+// returns false if the pattern starts with a wildcard segment, or has no wildcard segment at all.
+func (g SWGPatternGrant) literalPrefix() (string, bool) {
+	segments := strings.Split(g.Pattern, swgPatternSegmentSep)
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			if i == 0 {
+				return "", false
+			}
+			return strings.Join(segments[:i], swgPatternSegmentSep), true
+		}
+	}
+	return "", false
+}
+
+// matchPatternGrant finds the SWGPatternGrant with the highest precedence that grants the property of the swagger schema.
+func (g SWGGrant) matchPatternGrant(schemaAddr SWGSchemaAddr, propertyAddr string) (SWGPatternGrant, bool) {
+	for _, patternGrant := range g.Patterns {
+		if patternGrant.appliesTo(schemaAddr) && patternGrant.Match(propertyAddr) {
+			return patternGrant, true
+		}
+	}
+	return SWGPatternGrant{}, false
+}
+
+// loadPatternGrantFile loads the SWGPatternGrants from the content of the pattern grant file, whose path is relative to the
+// grant base directory. The returned error (if any) is either a decoding error, or a Diagnostics that records every invalid grant
+// (or every violation of the file format, in which case no grant is returned).
+func loadPatternGrantFile(format FileFormat, relPath string, b []byte) ([]SWGPatternGrant, error) {
+	infilePatternGrants := map[string]SWGPropertyGrant{}
+	if err := DecodeFile(FileKindSWGPatternGrant, format, b, &infilePatternGrants); err != nil {
+		return nil, err
+	}
+
+	var (
+		grants []SWGPatternGrant
+		diags  Diagnostics
+	)
+	for _, pattern := range sortedGrantProperties(infilePatternGrants) {
+		patternGrant := infilePatternGrants[pattern]
+		if err := validatePattern(pattern); err != nil {
+			diags = append(diags, Diagnostic{Pointer: jsonPointer(pattern), Err: err})
+			continue
+		}
+		if err := patternGrant.Validate(); err != nil {
+			diags = append(diags, Diagnostic{Pointer: jsonPointer(pattern), Err: fmt.Errorf("grant of pattern %q: %v", pattern, err)})
+		}
+		grants = append(grants, SWGPatternGrant{
+			Dir:       filepath.Dir(relPath),
+			Pattern:   pattern,
+			GrantInfo: patternGrant.GrantInfo,
+			file:      relPath,
+		})
+	}
+	return grants, diags.Err()
+}
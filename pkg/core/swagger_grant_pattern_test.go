@@ -0,0 +1,183 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSWGPatternGrant_Match(t *testing.T) {
+	cases := []struct {
+		pattern      string
+		propertyAddr string
+		expect       bool
+	}{
+		{"etag", "etag", true},
+		{"etag", "properties.etag", false},
+		{"systemData.*", "systemData.createdBy", true},
+		{"systemData.*", "systemData", false},
+		{"systemData.*", "systemData.foo.bar", false},
+		{"*.provisioningState", "properties.provisioningState", true},
+		{"**.provisioningState", "provisioningState", true},
+		{"**.provisioningState", "properties.foo.provisioningState", true},
+		{"**.provisioningState", "properties.foo.provisioningStateX", false},
+		{"properties.**", "properties.foo.bar", true},
+		{"prop.*", "prop.p1{variant}", true},
+		{"**", "", false},
+	}
+	for idx, c := range cases {
+		require.Equal(t, c.expect, SWGPatternGrant{Pattern: c.pattern}.Match(c.propertyAddr), idx)
+	}
+}
+
+func TestSWGPatternGrant_appliesTo(t *testing.T) {
+	cases := []struct {
+		dir    string
+		expect bool
+	}{
+		{".", true},
+		{"Microsoft.Foo", true},
+		{filepath.Join("Microsoft.Foo", "stable"), true},
+		{filepath.Join("Microsoft.Foo", "stab"), false},
+		{"Microsoft.Bar", false},
+	}
+	schemaAddr := NewSWGSchemaAddr(filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json"), "Foo")
+	for idx, c := range cases {
+		require.Equal(t, c.expect, SWGPatternGrant{Dir: c.dir}.appliesTo(schemaAddr), idx)
+	}
+}
+
+func TestSWGSchemas_Grant_Pattern(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	grantDir := filepath.Join(pwd, "testdata", "swagger_grants_patterns")
+
+	v1 := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+	v2 := filepath.Join("Microsoft.Foo", "stable", "2020-02-01", "foo.json")
+	rpPatterns := filepath.Join("Microsoft.Foo", "_patterns.json")
+
+	swggrant, err := NewSWGGrantFromFiles(grantDir)
+	require.Error(t, err)
+	diags := err.(Diagnostics)
+	require.Len(t, diags, 1)
+	require.Equal(t, filepath.Join(grantDir, "_patterns.yaml"), diags[0].File)
+	require.Equal(t, 5, diags[0].Line)
+
+	type granted struct {
+		property string
+		source   string
+		pattern  string
+	}
+	expect := map[SWGSchemaAddr][]granted{
+		NewSWGSchemaAddr(v1, "Foo"): {
+			{"etag", "_patterns.yaml", "etag"},
+			{"properties.p1", rpPatterns, "properties.*"},
+			{"properties.p2", v1, ""},
+		},
+		NewSWGSchemaAddr(v2, "Foo"): {
+			{"etag", "_patterns.yaml", "etag"},
+			{"properties.p1", rpPatterns, "properties.*"},
+			{"properties.p2", rpPatterns, "properties.*"},
+		},
+	}
+
+	swgschemas := NewSGWSchemas()
+	for addr := range expect {
+		schema, err := NewSWGSchema(specBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+		require.NoError(t, err)
+		require.NoError(t, schema.ExpandAll(2))
+		swgschemas.Set(addr, schema)
+	}
+	require.NoError(t, swgschemas.Grant(specBasePath, swggrant, SWGGrantOptions{}))
+
+	for addr, expect := range expect {
+		schema := swgschemas.Get(addr)
+		actual := []granted{}
+		for propertyAddr, prop := range schema.Properties {
+			if prop.IsGranted {
+				actual = append(actual, granted{propertyAddr, prop.GrantSource, prop.GrantPattern})
+			}
+		}
+		sort.Slice(actual, func(i, j int) bool {
+			return actual[i].property < actual[j].property
+		})
+		require.Equal(t, expect, actual, addr)
+	}
+	require.Equal(t, GrantCategoryReadOnlyNoise, swgschemas.Get(NewSWGSchemaAddr(v1, "Foo")).Properties["etag"].GrantCategory)
+}
+
+func TestSWGSchemas_Grant_PatternKnowledgeBase(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_knowledgebase")
+	grantDir := filepath.Join(pwd, "..", "..", "azure_knowledgebase", KnowledgeBaseSwaggerGrantDir)
+	addr := NewSWGSchemaAddr(filepath.Join("network", "resource-manager", "Microsoft.Network", "stable", "2020-05-01", "virtualNetwork.json"), "VirtualNetwork")
+
+	swggrant, err := NewSWGGrantFromFiles(grantDir)
+	require.NoError(t, err)
+
+	// The "systemData" is not expanded beforehand, it is expanded on demand for the "systemData.*" pattern.
+	schema, err := NewSWGSchema(specBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+	require.NoError(t, err)
+	require.NoError(t, schema.ExpandAll(1))
+	require.Contains(t, schema.Properties, "systemData")
+
+	swgschemas := NewSGWSchemas()
+	swgschemas.Set(addr, schema)
+	require.NoError(t, swgschemas.Grant(specBasePath, swggrant, SWGGrantOptions{}))
+
+	require.NotContains(t, schema.Properties, "systemData")
+	for _, propertyAddr := range []string{"systemData.createdBy", "systemData.createdAt", "systemData.lastModifiedBy", "systemData.lastModifiedAt"} {
+		require.Contains(t, schema.Properties, propertyAddr)
+		prop := schema.Properties[propertyAddr]
+		require.True(t, prop.IsGranted, propertyAddr)
+		require.Equal(t, "systemData.*", prop.GrantPattern, propertyAddr)
+		require.Equal(t, "_patterns.json", prop.GrantSource, propertyAddr)
+	}
+	require.Equal(t, "etag", schema.Properties["etag"].GrantPattern)
+	require.Equal(t, "type", schema.Properties["type"].GrantPattern)
+	require.Equal(t, "properties.provisioningState", schema.Properties["properties.provisioningState"].GrantPattern)
+	require.False(t, schema.Properties["location"].IsGranted)
+}
+
+func TestSWGSchemas_Grant_PatternPrefixGrantedExplicitly(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_knowledgebase")
+	grantDir := filepath.Join(pwd, "testdata", "swagger_grants_pattern_prefix")
+	swaggerRelPath := filepath.Join("network", "resource-manager", "Microsoft.Network", "stable", "2020-05-01", "virtualNetwork.json")
+	addr := NewSWGSchemaAddr(swaggerRelPath, "VirtualNetwork")
+
+	swggrant, err := NewSWGGrantFromFiles(grantDir)
+	require.NoError(t, err)
+
+	// The "systemData" is granted explicitly while it is not expanded, which overrides the "systemData.*" pattern that expands it.
+	schema, err := NewSWGSchema(specBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+	require.NoError(t, err)
+	require.NoError(t, schema.ExpandAll(1))
+	require.Contains(t, schema.Properties, "systemData")
+
+	swgschemas := NewSGWSchemas()
+	swgschemas.Set(addr, schema)
+	require.NoError(t, swgschemas.Grant(specBasePath, swggrant, SWGGrantOptions{}))
+
+	require.NotContains(t, schema.Properties, "systemData")
+	for _, propertyAddr := range []string{"systemData.createdBy", "systemData.createdAt", "systemData.lastModifiedBy", "systemData.lastModifiedAt"} {
+		require.Contains(t, schema.Properties, propertyAddr)
+		prop := schema.Properties[propertyAddr]
+		require.True(t, prop.IsGranted, propertyAddr)
+		require.Equal(t, "", prop.GrantPattern, propertyAddr)
+		require.Equal(t, swaggerRelPath, prop.GrantSource, propertyAddr)
+		require.Equal(t, GrantCategoryByDesign, prop.GrantCategory, propertyAddr)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// GrantCategoryUncategorized groups the grants that don't specify a category in the GrantReport.
+const GrantCategoryUncategorized GrantCategory = "uncategorized"
+
+// GrantReportItem is a granted swagger schema, or a granted swagger property.
+type GrantReportItem struct {
+	Schema SWGSchemaAddr `json:"schema"`
+
+	// The swagger schema relative property address, empty if the whole schema is granted.
+	Property string `json:"property,omitempty"`
+
+	Comment  string `json:"comment,omitempty"`
+	Issue    string `json:"issue,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+	ReviewBy string `json:"review_by,omitempty"`
+
+	// The grant file (relative to the grant base directory) where the grant comes from, which might be of an older API
+	// version if the grant is inherited.
+	Source string `json:"source,omitempty"`
+
+	// The address pattern of the pattern grant that grants the property, empty if the property is granted explicitly.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Whether the review-by date of the grant has passed.
+	Expired bool `json:"expired,omitempty"`
+}
+
+// GrantCategoryReport records the granted swagger schemas and properties of a certain GrantCategory.
+type GrantCategoryReport struct {
+	Category GrantCategory     `json:"category"`
+	Items    []GrantReportItem `json:"items"`
+}
+
+// GrantReport is the granted swagger schemas and properties grouped by their category, together with the expired ones,
+// which should be reviewed again as the reason of granting might not hold any more.
+type GrantReport struct {
+	Categories []GrantCategoryReport `json:"categories"`
+	Expired    []GrantReportItem     `json:"expired"`
+}
+
+// NewGrantReport builds the GrantReport from the granted SWGSchemas, the grants whose review-by date is before the date of now
+// are flagged as expired.
+// The categories are ordered as they are defined, followed by the uncategorized grants. The items are ordered by schema and property.
+func NewGrantReport(schemas map[SWGSchemaAddr]*SWGSchema, now time.Time) GrantReport {
+	itemsByCategory := map[GrantCategory][]GrantReportItem{}
+	addItem := func(schemaAddr SWGSchemaAddr, propertyAddr string, info GrantInfo, source, pattern string) {
+		category := info.Category
+		if category == "" {
+			category = GrantCategoryUncategorized
+		}
+		itemsByCategory[category] = append(itemsByCategory[category], GrantReportItem{
+			Schema:   schemaAddr,
+			Property: propertyAddr,
+			Comment:  info.Comment,
+			Issue:    info.Issue,
+			Owner:    info.Owner,
+			ReviewBy: info.ReviewBy,
+			Source:   source,
+			Pattern:  pattern,
+			Expired:  info.IsExpired(now),
+		})
+	}
+
+	for schemaAddr, schema := range schemas {
+		if schema.IsGranted {
+			addItem(schemaAddr, "", schema.GrantInfo(), schema.GrantSource, "")
+			continue
+		}
+		for propertyAddr, prop := range schema.Properties {
+			if prop.IsGranted {
+				addItem(schemaAddr, propertyAddr, prop.GrantInfo(), prop.GrantSource, prop.GrantPattern)
+			}
+		}
+	}
+
+	report := GrantReport{
+		Categories: []GrantCategoryReport{},
+		Expired:    []GrantReportItem{},
+	}
+	for _, category := range append(grantCategories, GrantCategoryUncategorized) {
+		items, ok := itemsByCategory[category]
+		if !ok {
+			continue
+		}
+		sortGrantReportItems(items)
+		report.Categories = append(report.Categories, GrantCategoryReport{Category: category, Items: items})
+		for _, item := range items {
+			if item.Expired {
+				report.Expired = append(report.Expired, item)
+			}
+		}
+	}
+	sortGrantReportItems(report.Expired)
+	return report
+}
+
+func sortGrantReportItems(items []GrantReportItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Schema != items[j].Schema {
+			return items[i].Schema < items[j].Schema
+		}
+		return items[i].Property < items[j].Property
+	})
+}
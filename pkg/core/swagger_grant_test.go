@@ -0,0 +1,315 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSWGSchemaGrant_JSON(t *testing.T) {
+	input := `{
+    "Properties": {
+        "p1": "granted",
+        "p2": {
+            "Comment": "granted with metadata",
+            "Category": "sdk_limitation",
+            "Issue": "https://github.com/foo/bar/issues/1",
+            "Owner": "foo",
+            "ReviewBy": "2020-01-01"
+        }
+    }
+}`
+	expect := SWGSchemaGrant{
+		Properties: map[string]SWGPropertyGrant{
+			"p1": {GrantInfo: GrantInfo{Comment: "granted"}},
+			"p2": {GrantInfo: GrantInfo{
+				Comment:  "granted with metadata",
+				Category: GrantCategorySDKLimitation,
+				Issue:    "https://github.com/foo/bar/issues/1",
+				Owner:    "foo",
+				ReviewBy: "2020-01-01",
+			}},
+		},
+	}
+
+	var actual SWGSchemaGrant
+	require.NoError(t, json.Unmarshal([]byte(input), &actual))
+	require.Equal(t, expect, actual)
+
+	b, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, input, string(b))
+}
+
+func TestSWGSchemaGrant_Validate(t *testing.T) {
+	cases := []struct {
+		grant         SWGSchemaGrant
+		expectPointer []string
+	}{
+		{
+			grant: SWGSchemaGrant{
+				GrantInfo: GrantInfo{Category: GrantCategoryDeprecated, ReviewBy: "2020-01-01"},
+			},
+		},
+		{
+			grant: SWGSchemaGrant{
+				GrantInfo: GrantInfo{Category: "foo"},
+			},
+			expectPointer: []string{""},
+		},
+		{
+			grant: SWGSchemaGrant{
+				Properties: map[string]SWGPropertyGrant{
+					"p1":    {GrantInfo: GrantInfo{Comment: "granted"}},
+					"p2":    {GrantInfo: GrantInfo{ReviewBy: "01/01/2020"}},
+					"p3/p4": {GrantInfo: GrantInfo{Category: "foo"}},
+				},
+			},
+			expectPointer: []string{"/Properties/p2", "/Properties/p3~1p4"},
+		},
+	}
+
+	for idx, c := range cases {
+		err := c.grant.Validate()
+		if len(c.expectPointer) == 0 {
+			require.NoError(t, err, idx)
+			continue
+		}
+		require.Error(t, err, idx)
+		actual := []string{}
+		for _, diag := range err.(Diagnostics) {
+			actual = append(actual, diag.Pointer)
+		}
+		require.Equal(t, c.expectPointer, actual, idx)
+	}
+}
+
+func TestGrantInfo_IsExpired(t *testing.T) {
+	now := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		reviewBy string
+		expect   bool
+	}{
+		{"", false},
+		{"invalid", false},
+		{"2020-01-01", true},
+		{"2020-01-02", false},
+		{"2020-01-03", false},
+	}
+	for idx, c := range cases {
+		require.Equal(t, c.expect, GrantInfo{ReviewBy: c.reviewBy}.IsExpired(now), idx)
+	}
+
+	// The date of now is taken in UTC, regardless of the location (it is still 2020-01-01 in UTC).
+	local := time.Date(2020, 1, 2, 1, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+	require.False(t, GrantInfo{ReviewBy: "2020-01-01"}.IsExpired(local))
+}
+
+func TestNewGrantReport(t *testing.T) {
+	now := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	schemas := map[SWGSchemaAddr]*SWGSchema{
+		NewSWGSchemaAddr("foo.json", "schema1"): {
+			IsGranted:     true,
+			GrantComment:  "deprecated since 2019",
+			GrantCategory: GrantCategoryDeprecated,
+			GrantReviewBy: "2020-01-01",
+		},
+		NewSWGSchemaAddr("foo.json", "schema2"): {
+			Properties: SWGSchemaProperties{
+				"p1": {IsGranted: true, GrantComment: "etag", GrantCategory: GrantCategoryReadOnlyNoise, GrantSource: "_patterns.json", GrantPattern: "p1"},
+				"p2": {IsGranted: true, GrantComment: "why", GrantReviewBy: "2019-12-31", GrantOwner: "foo"},
+				"p3": {TFLinks: []TFLink{}},
+				"p4": {IsGranted: true, GrantCategory: GrantCategoryReadOnlyNoise, GrantReviewBy: "2020-02-01"},
+			},
+		},
+	}
+
+	expect := GrantReport{
+		Categories: []GrantCategoryReport{
+			{
+				Category: GrantCategoryDeprecated,
+				Items: []GrantReportItem{
+					{Schema: NewSWGSchemaAddr("foo.json", "schema1"), Comment: "deprecated since 2019", ReviewBy: "2020-01-01", Expired: true},
+				},
+			},
+			{
+				Category: GrantCategoryReadOnlyNoise,
+				Items: []GrantReportItem{
+					{Schema: NewSWGSchemaAddr("foo.json", "schema2"), Property: "p1", Comment: "etag", Source: "_patterns.json", Pattern: "p1"},
+					{Schema: NewSWGSchemaAddr("foo.json", "schema2"), Property: "p4", ReviewBy: "2020-02-01"},
+				},
+			},
+			{
+				Category: GrantCategoryUncategorized,
+				Items: []GrantReportItem{
+					{Schema: NewSWGSchemaAddr("foo.json", "schema2"), Property: "p2", Comment: "why", Owner: "foo", ReviewBy: "2019-12-31", Expired: true},
+				},
+			},
+		},
+		Expired: []GrantReportItem{
+			{Schema: NewSWGSchemaAddr("foo.json", "schema1"), Comment: "deprecated since 2019", ReviewBy: "2020-01-01", Expired: true},
+			{Schema: NewSWGSchemaAddr("foo.json", "schema2"), Property: "p2", Comment: "why", Owner: "foo", ReviewBy: "2019-12-31", Expired: true},
+		},
+	}
+	require.Equal(t, expect, NewGrantReport(schemas, now))
+}
+
+func TestSWGSchemas_Grant_Inherit(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	grantDir := filepath.Join(pwd, "testdata", "swagger_grants_versions")
+
+	v1 := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+	v2 := filepath.Join("Microsoft.Foo", "stable", "2020-02-01", "foo.json")
+	v3 := filepath.Join("Microsoft.Foo", "stable", "2020-03-01", "foo.json")
+
+	type granted struct {
+		property string
+		source   string
+	}
+
+	cases := []struct {
+		opts          SWGGrantOptions
+		expect        map[SWGSchemaAddr][]granted
+		expectPointer []string
+	}{
+		{
+			opts: SWGGrantOptions{},
+			expect: map[SWGSchemaAddr][]granted{
+				NewSWGSchemaAddr(v1, "Foo"): {{"etag", v1}, {"old", v1}, {"properties.p2", v1}},
+				NewSWGSchemaAddr(v1, "Bar"): {{"", v1}},
+				NewSWGSchemaAddr(v2, "Foo"): {},
+				NewSWGSchemaAddr(v2, "Bar"): {},
+				NewSWGSchemaAddr(v3, "Foo"): {{"etag", v1}, {"id", v3}},
+				NewSWGSchemaAddr(v3, "Bar"): {},
+			},
+			expectPointer: []string{"/Foo/Properties/old", "/Foo/Properties/properties.p2"},
+		},
+		{
+			opts: SWGGrantOptions{InheritMissing: true},
+			expect: map[SWGSchemaAddr][]granted{
+				NewSWGSchemaAddr(v1, "Foo"): {{"etag", v1}, {"old", v1}, {"properties.p2", v1}},
+				NewSWGSchemaAddr(v1, "Bar"): {{"", v1}},
+				NewSWGSchemaAddr(v2, "Foo"): {{"etag", v1}, {"old", v1}, {"properties.p2", v1}},
+				NewSWGSchemaAddr(v2, "Bar"): {{"", v1}},
+				NewSWGSchemaAddr(v3, "Foo"): {{"etag", v1}, {"id", v3}},
+				NewSWGSchemaAddr(v3, "Bar"): {},
+			},
+			expectPointer: []string{"/Foo/Properties/old", "/Foo/Properties/properties.p2"},
+		},
+	}
+
+	for idx, c := range cases {
+		swgschemas := NewSGWSchemas()
+		for addr := range c.expect {
+			schema, err := NewSWGSchema(specBasePath, addr.SwaggerRelPath(), addr.SchemaName())
+			require.NoError(t, err, idx)
+			require.NoError(t, schema.ExpandAll(2), idx)
+			swgschemas.Set(addr, schema)
+		}
+
+		swggrant, err := NewSWGGrantFromFiles(grantDir)
+		require.NoError(t, err, idx)
+
+		// The stale inherited grants are recorded as warnings, rather than failing the others.
+		require.NoError(t, swgschemas.Grant(specBasePath, swggrant, c.opts), idx)
+		actualPointer := []string{}
+		for _, diag := range swgschemas.StaleInheritedGrants {
+			require.Equal(t, v1, diag.File, idx)
+			actualPointer = append(actualPointer, diag.Pointer)
+		}
+		sort.Strings(actualPointer)
+		require.Equal(t, c.expectPointer, actualPointer, idx)
+
+		for addr, expect := range c.expect {
+			schema := swgschemas.Get(addr)
+			actual := []granted{}
+			if schema.IsGranted {
+				actual = append(actual, granted{"", schema.GrantSource})
+			}
+			for propertyAddr, prop := range schema.Properties {
+				if prop.IsGranted {
+					actual = append(actual, granted{propertyAddr, prop.GrantSource})
+				}
+			}
+			sort.Slice(actual, func(i, j int) bool {
+				return actual[i].property < actual[j].property
+			})
+			require.Equal(t, expect, actual, "%d: %s", idx, addr)
+		}
+	}
+}
+
+func TestSWGSchemas_Grant_Expand(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	v1 := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+
+	cases := []struct {
+		expandDepth   int
+		grant         SWGSchemaGrant
+		expectGranted []string
+	}{
+		// grant a property that is not expanded yet
+		{
+			expandDepth: 0,
+			grant: SWGSchemaGrant{
+				Properties: map[string]SWGPropertyGrant{
+					"properties.p2": {GrantInfo: GrantInfo{Comment: "granted"}},
+				},
+			},
+			expectGranted: []string{"properties.p2"},
+		},
+		// grant a property that has been expanded
+		{
+			expandDepth: 2,
+			grant: SWGSchemaGrant{
+				Properties: map[string]SWGPropertyGrant{
+					"properties": {GrantInfo: GrantInfo{Comment: "granted"}},
+				},
+			},
+			expectGranted: []string{"properties.p1", "properties.p2"},
+		},
+	}
+
+	for idx, c := range cases {
+		schema, err := NewSWGSchema(specBasePath, v1, "Foo")
+		require.NoError(t, err, idx)
+		require.NoError(t, schema.ExpandAll(c.expandDepth), idx)
+		swgschemas := NewSGWSchemas()
+		swgschemas.Set(NewSWGSchemaAddr(v1, "Foo"), schema)
+
+		swggrant := SWGGrant{
+			Schemas: map[SWGSchemaAddr]SWGSchemaGrant{
+				NewSWGSchemaAddr(v1, "Foo"): c.grant,
+				// The schema granted as a whole is loaded even if it is not loaded yet.
+				NewSWGSchemaAddr(v1, "Bar"): {GrantInfo: GrantInfo{Comment: "granted"}},
+			},
+		}
+		require.NoError(t, swgschemas.Grant(specBasePath, swggrant, SWGGrantOptions{}), idx)
+
+		actual := []string{}
+		for propertyAddr, prop := range schema.Properties {
+			if prop.IsGranted {
+				actual = append(actual, propertyAddr)
+			}
+		}
+		sort.Strings(actual)
+		require.Equal(t, c.expectGranted, actual, idx)
+
+		bar := swgschemas.Get(NewSWGSchemaAddr(v1, "Bar"))
+		require.NotNil(t, bar, idx)
+		require.True(t, bar.IsGranted, idx)
+	}
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// FindUncoveredRequiredProperties reports each required swagger property of the SWGSchemas that is neither linked nor
+// granted, unless it is read only or its schema is granted as a whole. Only the properties that have been expanded are
+// inspected, as is the case for the coverage.
+// A property is required relative to its parent, so a required property under an optional ancestor (e.g. "properties.sku"
+// under the optional "properties" envelope) is only reported if that ancestor is present, i.e. any property under it is
+// linked.
+// Each Diagnostic points to the "PropertyLinks" of every TFSchema file that links to the schema of the property, or has no
+// file if there is no such TFSchema file.
+func FindUncoveredRequiredProperties(swgschemas *SWGSchemas, files []TFSchemaFile) Diagnostics {
+	filesBySchema := map[SWGSchemaAddr][]TFSchemaFile{}
+	for _, f := range files {
+		linked := map[SWGSchemaAddr]bool{}
+		for _, links := range f.PropertyLinks {
+			for _, link := range links {
+				if link.IsNotApplicable() {
+					continue
+				}
+				swaggerRelPath := f.SwaggerSpec
+				if link.Spec != nil {
+					swaggerRelPath = *link.Spec
+				}
+				addr := NewSWGSchemaAddr(swaggerRelPath, link.SchemaProp.Schema)
+				if !linked[addr] {
+					linked[addr] = true
+					filesBySchema[addr] = append(filesBySchema[addr], f)
+				}
+			}
+		}
+	}
+
+	schemas := swgschemas.GetAll()
+	schemaAddrs := make([]SWGSchemaAddr, 0, len(schemas))
+	for addr := range schemas {
+		schemaAddrs = append(schemaAddrs, addr)
+	}
+	sort.Slice(schemaAddrs, func(i, j int) bool {
+		return schemaAddrs[i] < schemaAddrs[j]
+	})
+
+	var diags Diagnostics
+	for _, schemaAddr := range schemaAddrs {
+		schema := schemas[schemaAddr]
+		if schema.IsGranted {
+			continue
+		}
+		present := map[string]bool{"": true}
+		for propAddr, prop := range schema.Properties {
+			if len(prop.TFLinks) == 0 {
+				continue
+			}
+			addr := propertyaddr.MustNewSwaggerPropertyAddr(schema.Name, propAddr)
+			for i := 1; i < len(addr.PropertyAddr); i++ {
+				present[addr.PropertyAddr[:i].String()] = true
+			}
+		}
+		propAddrs := make([]string, 0, len(schema.Properties))
+		for propAddr, prop := range schema.Properties {
+			if propAddr == "" || !prop.Required() || prop.ReadOnly() || prop.IsGranted || len(prop.TFLinks) != 0 {
+				continue
+			}
+			if !present[prop.requiredAncestor(propertyaddr.MustNewSwaggerPropertyAddr(schema.Name, propAddr))] {
+				continue
+			}
+			propAddrs = append(propAddrs, propAddr)
+		}
+		sort.Strings(propAddrs)
+
+		for _, propAddr := range propAddrs {
+			err := fmt.Errorf("required swagger property %s (%s) is neither linked nor granted",
+				propertyaddr.MustNewSwaggerPropertyAddr(schema.Name, propAddr), schemaAddr.SwaggerRelPath())
+			diag := Diagnostic{Pointer: jsonPointer("PropertyLinks"), Rule: RuleUncoveredRequiredProperty, Err: err}
+			if len(filesBySchema[schemaAddr]) == 0 {
+				diags = append(diags, Diagnostic{Rule: RuleUncoveredRequiredProperty, Err: err})
+				continue
+			}
+			for _, f := range filesBySchema[schemaAddr] {
+				diags = append(diags, f.Locate(diag)...)
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,289 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	openapispec "github.com/go-openapi/spec"
+)
+
+// SWGResourceTypeCandidate is a path of a swagger file that has the PUT, GET and DELETE operations, which is considered as a
+// candidate of a terraform resource.
+type SWGResourceTypeCandidate struct {
+	// The ARM resource type derived from the path template (e.g. "Microsoft.Network/virtualNetworks/subnets"), which is empty
+	// if it can't be derived (e.g. "/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}").
+	Type         string
+	PathTemplate string
+
+	// The schema of the body parameter of the PUT operation.
+	Schema SWGSchemaAddr
+}
+
+// SWGUnlinkedResourceType is a candidate ARM resource type that no terraform resource links to.
+type SWGUnlinkedResourceType struct {
+	Type         string        `json:"type"`
+	APIVersion   string        `json:"api_version"`
+	PathTemplate string        `json:"path_template"`
+	Schema       SWGSchemaAddr `json:"schema"`
+
+	// The amount of the (expanded) properties of the schema, and the ones that are not read only.
+	Properties         int `json:"properties"`
+	WritableProperties int `json:"writable_properties"`
+}
+
+// SWGResourceTypeOf derives the ARM resource type from the path template, which is the resource provider namespace followed by
+// the resource type segments after the last "providers" segment (e.g. "Microsoft.Network/virtualNetworks/subnets" of
+// "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}/subnets/{subnetName}").
+// It returns false if the path template doesn't address a single resource (e.g. a collection or an action).
+func SWGResourceTypeOf(pathTemplate string) (string, bool) {
+	segments := strings.Split(strings.Trim(pathTemplate, "/"), "/")
+	idx := -1
+	for i, segment := range segments {
+		if strings.EqualFold(segment, "providers") {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return "", false
+	}
+	// The namespace is followed by the pairs of the resource type and the resource name.
+	segments = segments[idx+1:]
+	if len(segments) < 3 || len(segments)%2 != 1 {
+		return "", false
+	}
+	types := []string{segments[0]}
+	for i := 1; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	for _, t := range types {
+		if strings.HasPrefix(t, "{") {
+			return "", false
+		}
+	}
+	return strings.Join(types, "/"), true
+}
+
+// CollectSWGResourceTypeCandidates collects the SWGResourceTypeCandidates of the swagger file, ordered by the path templates.
+// The paths whose PUT body schema is defined in another swagger file are skipped, while the ones whose resource type can't be
+// derived are kept with an empty type. This is the definition of a terraform resource candidate shared by the tools.
+func CollectSWGResourceTypeCandidates(swaggerBasePath, swaggerRelPath string) ([]SWGResourceTypeCandidate, error) {
+	swaggerURI := swaggerBasePath + "/" + filepath.ToSlash(swaggerRelPath)
+	swagger, err := LoadSwagger(swaggerURI)
+	if err != nil {
+		return nil, err
+	}
+	if swagger.Paths == nil {
+		return nil, nil
+	}
+	var candidates []SWGResourceTypeCandidate
+	for path, item := range swagger.Paths.Paths {
+		if item.Put == nil || item.Get == nil || item.Delete == nil {
+			continue
+		}
+		resourceType, _ := SWGResourceTypeOf(path)
+		name, ok, err := swgBodySchemaName(swagger, swaggerURI, item.Put)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, SWGResourceTypeCandidate{
+			Type:         resourceType,
+			PathTemplate: path,
+			Schema:       NewSWGSchemaAddr(swaggerRelPath, name),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].PathTemplate < candidates[j].PathTemplate
+	})
+	return candidates, nil
+}
+
+// swgBodySchemaName returns the name of the definition referred by the body parameter of the operation. The parameter refs
+// are resolved, while it returns false if the body schema is not a definition of the same swagger file.
+func swgBodySchemaName(swagger *openapispec.Swagger, swaggerURI string, op *openapispec.Operation) (string, bool, error) {
+	for _, param := range op.Parameters {
+		if param.Ref.String() != "" {
+			resolved, err := openapispec.ResolveParameterWithBase(swagger, param.Ref, &openapispec.ExpandOptions{RelativeBase: swaggerURI})
+			if err != nil {
+				return "", false, fmt.Errorf("resolving parameter %s in %s: %v", param.Ref.String(), swaggerURI, err)
+			}
+			param = *resolved
+		}
+		if param.In != "body" || param.Schema == nil || !param.Schema.Ref.HasFragmentOnly {
+			continue
+		}
+		matches := swgDefinitionRefPattern.FindStringSubmatch(param.Schema.Ref.GetPointer().String())
+		if len(matches) != 2 {
+			continue
+		}
+		return matches[1], true, nil
+	}
+	return "", false, nil
+}
+
+// NewSWGUnlinkedResourceTypes finds the candidate ARM resource types that no TFSchema links to, in the latest API versions of
+// the resource providers. The resource provider directories are relative to the swagger spec directory (e.g. "network"),
+// which default to the ones of the swagger files linked by the TFSchemas. A resource type is linked if the PUT body schema of
+// any of its paths, in any API version, is linked by a TFSchema.
+//
+// For each resource provider namespace, the latest stable API version takes precedence over the latest preview one for the
+// resource types existing in both. The schemas of the unlinked resource types are expanded up to maxDepth levels deep, and
+// the resource types are ordered by the amount of the properties (descending), then by the resource types. The swagger spec
+// directory is required to be a local path, as the API versions are listed.
+// The swagger files (or the schemas) that fail to be loaded are skipped, which are reported as a Diagnostics error, together
+// with the resource types found in the others.
+func NewSWGUnlinkedResourceTypes(swaggerBasePath string, rpDirs []string, tfschemas []TFSchema, maxDepth int) ([]SWGUnlinkedResourceType, error) {
+	var linkedSchemas []SWGSchemaAddr
+	for _, tfschema := range tfschemas {
+		linkedSchemas = append(linkedSchemas, tfschema.linkedSWGSchemas()...)
+	}
+
+	if len(rpDirs) == 0 {
+		set := map[string]bool{}
+		for _, addr := range linkedSchemas {
+			set[addr.ResourceProvider()] = true
+		}
+		for rpDir := range set {
+			rpDirs = append(rpDirs, rpDir)
+		}
+		sort.Strings(rpDirs)
+	}
+
+	var diags Diagnostics
+
+	// The linked resource types are keyed by the lower cased type, as the resource types are case insensitive.
+	linked := map[string]bool{}
+	candidatesOfFile := map[string][]SWGResourceTypeCandidate{}
+	for _, addr := range linkedSchemas {
+		swaggerRelPath := addr.SwaggerRelPath()
+		candidates, ok := candidatesOfFile[swaggerRelPath]
+		if !ok {
+			var err error
+			candidates, err = CollectSWGResourceTypeCandidates(swaggerBasePath, swaggerRelPath)
+			if err != nil {
+				diags = append(diags, Diagnostic{File: swaggerRelPath, Err: fmt.Errorf("collecting resource types: %v", err)})
+			}
+			candidatesOfFile[swaggerRelPath] = candidates
+		}
+		for _, candidate := range candidates {
+			if candidate.Type != "" && candidate.Schema == addr {
+				linked[strings.ToLower(candidate.Type)] = true
+			}
+		}
+	}
+
+	unlinked := map[string]SWGResourceTypeCandidate{}
+	var keys []string
+	for _, rpDir := range rpDirs {
+		stableDirs, err := filepath.Glob(filepath.Join(swaggerBasePath, rpDir, "resource-manager", "*", "stable"))
+		if err != nil {
+			return nil, err
+		}
+		previewDirs, err := filepath.Glob(filepath.Join(swaggerBasePath, rpDir, "resource-manager", "*", "preview"))
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range append(stableDirs, previewDirs...) {
+			dir, err := filepath.Rel(swaggerBasePath, dir)
+			if err != nil {
+				return nil, err
+			}
+			latest, err := LatestSWGAPIVersion(swaggerBasePath, dir)
+			if err != nil {
+				return nil, err
+			}
+			entries, err := ioutil.ReadDir(filepath.Join(swaggerBasePath, dir, latest))
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+					continue
+				}
+				swaggerRelPath := filepath.Join(dir, latest, entry.Name())
+				candidates, err := CollectSWGResourceTypeCandidates(swaggerBasePath, swaggerRelPath)
+				if err != nil {
+					diags = append(diags, Diagnostic{File: swaggerRelPath, Err: fmt.Errorf("collecting resource types: %v", err)})
+					continue
+				}
+				for _, candidate := range candidates {
+					if candidate.Type == "" {
+						continue
+					}
+					key := strings.ToLower(candidate.Type)
+					if linked[key] {
+						continue
+					}
+					if _, ok := unlinked[key]; ok {
+						continue
+					}
+					unlinked[key] = candidate
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+
+	types := make([]SWGUnlinkedResourceType, 0, len(keys))
+	for _, key := range keys {
+		candidate := unlinked[key]
+		swaggerRelPath := candidate.Schema.SwaggerRelPath()
+		schema, err := NewSWGSchema(swaggerBasePath, swaggerRelPath, candidate.Schema.SchemaName())
+		if err != nil {
+			diags = append(diags, Diagnostic{File: swaggerRelPath, Err: err})
+			continue
+		}
+		if err := schema.ExpandAll(maxDepth); err != nil {
+			diags = append(diags, Diagnostic{File: swaggerRelPath, Err: fmt.Errorf("expanding swagger schema %s: %v", candidate.Schema.SchemaName(), err)})
+			continue
+		}
+		resourceType := SWGUnlinkedResourceType{
+			Type:         candidate.Type,
+			APIVersion:   SWGAPIVersionOf(swaggerRelPath),
+			PathTemplate: candidate.PathTemplate,
+			Schema:       candidate.Schema,
+			Properties:   len(schema.Properties),
+		}
+		for _, prop := range schema.Properties {
+			if prop.writable() {
+				resourceType.WritableProperties++
+			}
+		}
+		types = append(types, resourceType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Properties != types[j].Properties {
+			return types[i].Properties > types[j].Properties
+		}
+		return types[i].Type < types[j].Type
+	})
+	return types, diags.Err()
+}
+
+// WriteSWGUnlinkedResourceTypesMarkdown writes the SWGUnlinkedResourceTypes as a Markdown table.
+func WriteSWGUnlinkedResourceTypesMarkdown(w io.Writer, types []SWGUnlinkedResourceType) error {
+	var sb strings.Builder
+	sb.WriteString("# Unlinked Resource Types\n\n")
+	fmt.Fprintf(&sb, "Resource types: %d\n", len(types))
+	if len(types) != 0 {
+		sb.WriteString("\n| Resource Type | Properties | Writable Properties | API Version | Schema | Path |\n|---|---|---|---|---|---|\n")
+		for _, t := range types {
+			fmt.Fprintf(&sb, "| `%s` | %d | %d | %s | `%s` | `%s` |\n",
+				t.Type,
+				t.Properties,
+				t.WritableProperties,
+				t.APIVersion,
+				markdownEscapeTableCell(string(t.Schema)),
+				t.PathTemplate,
+			)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSWGResourceTypeOf(t *testing.T) {
+	cases := []struct {
+		path   string
+		expect string
+		ok     bool
+	}{
+		{
+			path:   "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks/{virtualNetworkName}/subnets/{subnetName}",
+			expect: "Microsoft.Network/virtualNetworks/subnets",
+			ok:     true,
+		},
+		{
+			path:   "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Storage/storageAccounts/{accountName}/blobServices/default",
+			expect: "Microsoft.Storage/storageAccounts/blobServices",
+			ok:     true,
+		},
+		{
+			path:   "/{scope}/providers/Microsoft.Authorization/locks/{lockName}",
+			expect: "Microsoft.Authorization/locks",
+			ok:     true,
+		},
+		{
+			path: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Network/virtualNetworks",
+		},
+		{
+			path: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/virtualMachines/{vmName}/restart",
+		},
+		{
+			path: "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}",
+		},
+	}
+	for _, c := range cases {
+		actual, ok := SWGResourceTypeOf(c.path)
+		require.Equal(t, c.ok, ok, c.path)
+		require.Equal(t, c.expect, actual, c.path)
+	}
+}
+
+func TestCollectSWGResourceTypeCandidates(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_resource_types")
+	swaggerRelPath := filepath.Join("foo", "resource-manager", "Microsoft.Foo", "stable", "2021-01-01", "foo.json")
+	prefix := "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Foo"
+
+	// The path without a "providers" segment is still a candidate, while its resource type is unknown.
+	candidates, err := CollectSWGResourceTypeCandidates(specBasePath, swaggerRelPath)
+	require.NoError(t, err)
+	require.Equal(t, []SWGResourceTypeCandidate{
+		{
+			Type:         "Microsoft.Foo/bazs",
+			PathTemplate: prefix + "/bazs/{bazName}",
+			Schema:       NewSWGSchemaAddr(swaggerRelPath, "Baz"),
+		},
+		{
+			Type:         "Microsoft.Foo/foos",
+			PathTemplate: prefix + "/foos/{fooName}",
+			Schema:       NewSWGSchemaAddr(swaggerRelPath, "Foo"),
+		},
+		{
+			Type:         "Microsoft.Foo/foos/bars",
+			PathTemplate: prefix + "/foos/{fooName}/bars/{barName}",
+			Schema:       NewSWGSchemaAddr(swaggerRelPath, "Bar"),
+		},
+		{
+			PathTemplate: "/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}",
+			Schema:       NewSWGSchemaAddr(swaggerRelPath, "ResourceGroup"),
+		},
+	}, candidates)
+}
+
+func TestNewSWGUnlinkedResourceTypes(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_resource_types")
+	stableDir := filepath.Join("foo", "resource-manager", "Microsoft.Foo", "stable")
+	prefix := "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Foo"
+
+	// The resource type of Foo is linked in an older API version.
+	tfschemas := []TFSchema{
+		{
+			Name:        "azurerm_foo",
+			SwaggerSpec: filepath.Join(stableDir, "2020-01-01", "foo.json"),
+			PropertyLinks: TFSchemaPropertyLinks{
+				"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Foo:properties.p1")}},
+			},
+		},
+	}
+
+	// The malformed swagger file is skipped, while the resource types of the others are still reported.
+	types, err := NewSWGUnlinkedResourceTypes(specBasePath, nil, tfschemas, 5)
+	require.IsType(t, Diagnostics{}, err)
+	diags := err.(Diagnostics)
+	require.Len(t, diags, 1)
+	require.Equal(t, filepath.Join(stableDir, "2021-01-01", "broken.json"), diags[0].File)
+	require.Equal(t, []SWGUnlinkedResourceType{
+		{
+			Type:               "Microsoft.Foo/bazs",
+			APIVersion:         "2021-01-01",
+			PathTemplate:       prefix + "/bazs/{bazName}",
+			Schema:             NewSWGSchemaAddr(filepath.Join(stableDir, "2021-01-01", "foo.json"), "Baz"),
+			Properties:         3,
+			WritableProperties: 1,
+		},
+		{
+			Type:               "Microsoft.Foo/foos/bars",
+			APIVersion:         "2021-01-01",
+			PathTemplate:       prefix + "/foos/{fooName}/bars/{barName}",
+			Schema:             NewSWGSchemaAddr(filepath.Join(stableDir, "2021-01-01", "foo.json"), "Bar"),
+			Properties:         2,
+			WritableProperties: 1,
+		},
+		{
+			Type:               "Microsoft.Foo/quxes",
+			APIVersion:         "2021-06-01-preview",
+			PathTemplate:       "/subscriptions/{subscriptionId}/providers/Microsoft.Foo/quxes/{quxName}",
+			Schema:             NewSWGSchemaAddr(filepath.Join("foo", "resource-manager", "Microsoft.Foo", "preview", "2021-06-01-preview", "foo.json"), "Qux"),
+			Properties:         1,
+			WritableProperties: 1,
+		},
+	}, types)
+
+	var sb strings.Builder
+	require.NoError(t, WriteSWGUnlinkedResourceTypesMarkdown(&sb, types))
+	require.Contains(t, sb.String(), "Resource types: 3\n")
+	require.Contains(t, sb.String(), "| `Microsoft.Foo/foos/bars` | 2 | 1 | 2021-01-01 |")
+}
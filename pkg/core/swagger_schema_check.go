@@ -0,0 +1,248 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/utils"
+)
+
+// coverageEpsilon is the tolerance when comparing two coverages, to avoid flagging the float rounding as a regression.
+const coverageEpsilon = 1e-9
+
+// SWGCoverageThresholds specifies the minimum coverage (<=1) of the resource providers and the swagger schemas.
+// The coverage of a resource provider is the coverage of all the properties of its swagger schemas in the report.
+type SWGCoverageThresholds struct {
+	// The key is the resource provider directory (e.g. "network").
+	ResourceProviders map[string]float64 `json:",omitempty"`
+
+	Schemas map[SWGSchemaAddr]float64 `json:",omitempty"`
+}
+
+// LoadSWGCoverageThresholds loads the SWGCoverageThresholds from a JSON or YAML file.
+func LoadSWGCoverageThresholds(path string) (SWGCoverageThresholds, error) {
+	var thresholds SWGCoverageThresholds
+	format, ok := FileFormatOf(path)
+	if !ok {
+		return thresholds, fmt.Errorf("unknown format of the threshold file %q", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return thresholds, err
+	}
+	if err := UnmarshalFile(format, b, &thresholds); err != nil {
+		return thresholds, newFileDiagnostic(path, b, err)
+	}
+	if err := thresholds.Validate(); err != nil {
+		diags := err.(Diagnostics).WithFile(path, b)
+		diags.Sort()
+		return thresholds, diags
+	}
+	return thresholds, nil
+}
+
+// Validate validates that each threshold is within [0, 1].
+func (t SWGCoverageThresholds) Validate() error {
+	var diags Diagnostics
+	validate := func(threshold float64, tokens ...string) {
+		if threshold < 0 || threshold > 1 {
+			diags = append(diags, Diagnostic{Pointer: jsonPointer(tokens...), Err: fmt.Errorf("threshold %v is not within [0, 1]", threshold)})
+		}
+	}
+	for rp, threshold := range t.ResourceProviders {
+		validate(threshold, "ResourceProviders", rp)
+	}
+	for addr, threshold := range t.Schemas {
+		validate(threshold, "Schemas", string(addr))
+	}
+	sort.Slice(diags, func(i, j int) bool {
+		return diags[i].Pointer < diags[j].Pointer
+	})
+	return diags.Err()
+}
+
+// SWGCoverageRegressionKind is the kind of a SWGCoverageRegression.
+type SWGCoverageRegressionKind string
+
+const (
+	// The swagger schema tracked in the baseline is missing in the current report.
+	SWGRegressionSchemaMissing SWGCoverageRegressionKind = "schema_missing"
+	// The coverage of the swagger schema is lower than the one in the baseline.
+	SWGRegressionCoverageDecreased SWGCoverageRegressionKind = "coverage_decreased"
+	// The property of the swagger schema tracked in the baseline is neither covered nor granted, while it was either covered
+	// or granted in the baseline. The properties that are not in the baseline (e.g. newly expanded ones) are not checked.
+	SWGRegressionUncoveredProperty SWGCoverageRegressionKind = "uncovered_property"
+	// The coverage of the resource provider or the swagger schema is lower than its threshold.
+	SWGRegressionBelowThreshold SWGCoverageRegressionKind = "below_threshold"
+)
+
+// SWGCoverageRegression is a coverage regression found by CheckSWGCoverage.
+type SWGCoverageRegression struct {
+	Kind SWGCoverageRegressionKind `json:"kind"`
+
+	// Either the resource provider or the schema is set, depending on what the regression is about.
+	ResourceProvider string        `json:"resource_provider,omitempty"`
+	Schema           SWGSchemaAddr `json:"schema,omitempty"`
+
+	// The swagger schema relative property address, only set for the SWGRegressionUncoveredProperty.
+	Property string `json:"property,omitempty"`
+
+	// The coverages are pointers, as a 0 coverage is meaningful, while they are only set for the kinds comparing coverages.
+	Baseline  *float64 `json:"baseline,omitempty"`
+	Current   *float64 `json:"current,omitempty"`
+	Threshold float64  `json:"threshold,omitempty"`
+}
+
+func (r SWGCoverageRegression) String() string {
+	switch r.Kind {
+	case SWGRegressionSchemaMissing:
+		return fmt.Sprintf("%s: the schema is missing", r.Schema)
+	case SWGRegressionCoverageDecreased:
+		return fmt.Sprintf("%s: coverage decreased from %.2f%% to %.2f%%", r.Schema, *r.Baseline*100, *r.Current*100)
+	case SWGRegressionUncoveredProperty:
+		return fmt.Sprintf("%s: property %q is neither covered nor granted", r.Schema, r.Property)
+	case SWGRegressionBelowThreshold:
+		target := string(r.Schema)
+		if r.ResourceProvider != "" {
+			target = "resource provider " + r.ResourceProvider
+		}
+		return fmt.Sprintf("%s: coverage %.2f%% is below the threshold %.2f%%", target, *r.Current*100, r.Threshold*100)
+	}
+	return string(r.Kind)
+}
+
+// SWGCoverageRegressions is a list of SWGCoverageRegression.
+type SWGCoverageRegressions []SWGCoverageRegression
+
+// WriteText writes a summary of the regressions, grouped by their kinds, in plain text.
+func (regressions SWGCoverageRegressions) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	if len(regressions) == 0 {
+		sb.WriteString("No coverage regression found.\n")
+	} else {
+		fmt.Fprintf(&sb, "%d coverage regression(s) found:\n", len(regressions))
+		var kind SWGCoverageRegressionKind
+		for _, r := range regressions {
+			if r.Kind != kind {
+				kind = r.Kind
+				fmt.Fprintf(&sb, "\n[%s]\n", kind)
+			}
+			fmt.Fprintf(&sb, "  %s\n", r)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// CheckSWGCoverage checks the current SWGSchemaReport against the baseline, only the swagger schemas tracked in the baseline
+// are checked. Additionally, the coverage of the resource providers and the swagger schemas are checked against the thresholds.
+// The regressions are ordered by kind, then by the resource provider/schema and the property.
+func CheckSWGCoverage(baseline, current SWGSchemaReport, thresholds SWGCoverageThresholds) SWGCoverageRegressions {
+	regressions := SWGCoverageRegressions{}
+
+	for _, addr := range baseline.SortedAddrs() {
+		baseSchema := baseline[addr]
+		curSchema, ok := current[addr]
+		if !ok {
+			regressions = append(regressions, SWGCoverageRegression{Kind: SWGRegressionSchemaMissing, Schema: addr})
+			continue
+		}
+		if curSchema.Coverage < baseSchema.Coverage-coverageEpsilon {
+			regressions = append(regressions, SWGCoverageRegression{
+				Kind:     SWGRegressionCoverageDecreased,
+				Schema:   addr,
+				Baseline: utils.Float(baseSchema.Coverage),
+				Current:  utils.Float(curSchema.Coverage),
+			})
+		}
+		if curSchema.IsGranted {
+			continue
+		}
+		propAddrs := make([]string, 0, len(curSchema.Properties))
+		for propAddr := range curSchema.Properties {
+			propAddrs = append(propAddrs, propAddr)
+		}
+		sort.Strings(propAddrs)
+		for _, propAddr := range propAddrs {
+			curProp := curSchema.Properties[propAddr]
+			if curProp.IsGranted || len(curProp.TFLinks) != 0 {
+				continue
+			}
+			if baseProp, ok := baseSchema.Properties[propAddr]; !ok || (!baseProp.IsGranted && len(baseProp.TFLinks) == 0) {
+				continue
+			}
+			regressions = append(regressions, SWGCoverageRegression{
+				Kind:     SWGRegressionUncoveredProperty,
+				Schema:   addr,
+				Property: propAddr,
+			})
+		}
+	}
+
+	rpCoverages := map[string][2]int{}
+	for addr, schema := range current {
+		covered, total := schema.propertyCoverage()
+		cov := rpCoverages[addr.ResourceProvider()]
+		rpCoverages[addr.ResourceProvider()] = [2]int{cov[0] + covered, cov[1] + total}
+	}
+	rps := make([]string, 0, len(thresholds.ResourceProviders))
+	for rp := range thresholds.ResourceProviders {
+		rps = append(rps, rp)
+	}
+	sort.Strings(rps)
+	for _, rp := range rps {
+		threshold := thresholds.ResourceProviders[rp]
+		var cov float64
+		if c := rpCoverages[rp]; c[1] != 0 {
+			cov = float64(c[0]) / float64(c[1])
+		}
+		if cov < threshold-coverageEpsilon {
+			regressions = append(regressions, SWGCoverageRegression{
+				Kind:             SWGRegressionBelowThreshold,
+				ResourceProvider: rp,
+				Current:          utils.Float(cov),
+				Threshold:        threshold,
+			})
+		}
+	}
+
+	addrs := make([]SWGSchemaAddr, 0, len(thresholds.Schemas))
+	for addr := range thresholds.Schemas {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+	for _, addr := range addrs {
+		threshold := thresholds.Schemas[addr]
+		schema, ok := current[addr]
+		if !ok {
+			if _, tracked := baseline[addr]; !tracked {
+				regressions = append(regressions, SWGCoverageRegression{Kind: SWGRegressionSchemaMissing, Schema: addr})
+			}
+			continue
+		}
+		if schema.Coverage < threshold-coverageEpsilon {
+			regressions = append(regressions, SWGCoverageRegression{
+				Kind:      SWGRegressionBelowThreshold,
+				Schema:    addr,
+				Current:   utils.Float(schema.Coverage),
+				Threshold: threshold,
+			})
+		}
+	}
+
+	kindOrder := map[SWGCoverageRegressionKind]int{
+		SWGRegressionSchemaMissing:     0,
+		SWGRegressionCoverageDecreased: 1,
+		SWGRegressionUncoveredProperty: 2,
+		SWGRegressionBelowThreshold:    3,
+	}
+	sort.SliceStable(regressions, func(i, j int) bool {
+		return kindOrder[regressions[i].Kind] < kindOrder[regressions[j].Kind]
+	})
+	return regressions
+}
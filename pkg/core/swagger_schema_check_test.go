@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSWGCoverageThresholds(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(pwd, "testdata", "swagger_schema_check")
+
+	thresholds, err := LoadSWGCoverageThresholds(filepath.Join(dir, "thresholds.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{"Microsoft.Foo": 0.8}, thresholds.ResourceProviders)
+	require.Len(t, thresholds.Schemas, 3)
+
+	_, err = LoadSWGCoverageThresholds(filepath.Join(dir, "invalid.yaml"))
+	require.Error(t, err)
+	diags := err.(Diagnostics)
+	require.Len(t, diags, 1)
+	require.Equal(t, "/ResourceProviders/Microsoft.Bar", diags[0].Pointer)
+	require.Equal(t, 3, diags[0].Line)
+}
+
+func TestCheckSWGCoverage(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldReport, err := LoadSWGSchemaReport(filepath.Join(pwd, "testdata", "swagger_schema_diff", "old.json"))
+	require.NoError(t, err)
+	newReport, err := LoadSWGSchemaReport(filepath.Join(pwd, "testdata", "swagger_schema_diff", "new.json"))
+	require.NoError(t, err)
+	thresholds, err := LoadSWGCoverageThresholds(filepath.Join(pwd, "testdata", "swagger_schema_check", "thresholds.yaml"))
+	require.NoError(t, err)
+
+	const rel = "Microsoft.Foo/stable/2020-01-01/foo.json"
+	cases := []struct {
+		baseline   SWGSchemaReport
+		current    SWGSchemaReport
+		thresholds SWGCoverageThresholds
+		expect     SWGCoverageRegressions
+	}{
+		{
+			baseline: newReport,
+			current:  newReport,
+			expect:   SWGCoverageRegressions{},
+		},
+		{
+			baseline: newReport,
+			current:  oldReport,
+			expect: SWGCoverageRegressions{
+				{Kind: SWGRegressionSchemaMissing, Schema: NewSWGSchemaAddr(rel, "Qux")},
+				{Kind: SWGRegressionCoverageDecreased, Schema: NewSWGSchemaAddr(rel, "Foo"), Baseline: utils.Float(0.75), Current: utils.Float(0.5)},
+				{Kind: SWGRegressionUncoveredProperty, Schema: NewSWGSchemaAddr(rel, "Foo"), Property: "etag"},
+			},
+		},
+		{
+			baseline:   oldReport,
+			current:    newReport,
+			thresholds: thresholds,
+			expect: SWGCoverageRegressions{
+				{Kind: SWGRegressionSchemaMissing, Schema: NewSWGSchemaAddr(rel, "Nope")},
+				{Kind: SWGRegressionBelowThreshold, ResourceProvider: "Microsoft.Foo", Current: utils.Float(5.0 / 7.0), Threshold: 0.8},
+				{Kind: SWGRegressionBelowThreshold, Schema: NewSWGSchemaAddr(rel, "Foo"), Current: utils.Float(0.75), Threshold: 0.8},
+			},
+		},
+	}
+	for idx, c := range cases {
+		require.Equal(t, c.expect, CheckSWGCoverage(c.baseline, c.current, c.thresholds), idx)
+	}
+
+	var sb strings.Builder
+	require.NoError(t, cases[1].expect.WriteText(&sb))
+	require.Contains(t, sb.String(), "  "+rel+"#/definitions/Foo: coverage decreased from 75.00% to 50.00%\n")
+
+	// A 0 coverage is kept in the JSON output.
+	b, err := json.Marshal(SWGCoverageRegression{Kind: SWGRegressionCoverageDecreased, Schema: NewSWGSchemaAddr(rel, "Foo"), Baseline: utils.Float(0.5), Current: utils.Float(0)})
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"current":0`)
+}
@@ -0,0 +1,355 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SWGDiffStatus is the status of a swagger schema or a swagger property in the SWGSchemaReportDiff.
+type SWGDiffStatus string
+
+const (
+	SWGDiffAdded   SWGDiffStatus = "added"
+	SWGDiffRemoved SWGDiffStatus = "removed"
+	SWGDiffChanged SWGDiffStatus = "changed"
+)
+
+// SWGPropertyDiff records how a swagger property changes between two SWGSchemaReports.
+type SWGPropertyDiff struct {
+	// The swagger schema relative property address.
+	Property string        `json:"property"`
+	Status   SWGDiffStatus `json:"status"`
+
+	// The terraform property addresses that are linked to/unlinked from the property.
+	AddedLinks   []string `json:"added_links,omitempty"`
+	RemovedLinks []string `json:"removed_links,omitempty"`
+
+	// Whether the property has gained its first link, or has lost all its links.
+	NewlyCovered bool `json:"newly_covered,omitempty"`
+	LostCoverage bool `json:"lost_coverage,omitempty"`
+
+	// Whether the property becomes granted, or is no longer granted. The comment is of the new grant, or of the removed grant.
+	Granted      bool   `json:"granted,omitempty"`
+	Ungranted    bool   `json:"ungranted,omitempty"`
+	GrantComment string `json:"grant_comment,omitempty"`
+}
+
+// SWGSchemaDiff records how a swagger schema changes between two SWGSchemaReports.
+type SWGSchemaDiff struct {
+	Schema SWGSchemaAddr `json:"schema"`
+	Status SWGDiffStatus `json:"status"`
+
+	OldCoverage   float64 `json:"old_coverage"`
+	NewCoverage   float64 `json:"new_coverage"`
+	CoverageDelta float64 `json:"coverage_delta"`
+
+	// Whether the schema becomes granted as a whole, or is no longer granted. The comment is of the new grant, or of the removed grant.
+	Granted      bool   `json:"granted,omitempty"`
+	Ungranted    bool   `json:"ungranted,omitempty"`
+	GrantComment string `json:"grant_comment,omitempty"`
+
+	Properties []SWGPropertyDiff `json:"properties,omitempty"`
+}
+
+// SWGSchemaReportDiffSummary counts the changes in the SWGSchemaReportDiff.
+type SWGSchemaReportDiffSummary struct {
+	AddedSchemas   int `json:"added_schemas"`
+	RemovedSchemas int `json:"removed_schemas"`
+	ChangedSchemas int `json:"changed_schemas"`
+
+	NewlyCoveredProperties int `json:"newly_covered_properties"`
+	LostCoverageProperties int `json:"lost_coverage_properties"`
+	AddedLinks             int `json:"added_links"`
+	RemovedLinks           int `json:"removed_links"`
+	NewGrants              int `json:"new_grants"`
+	RemovedGrants          int `json:"removed_grants"`
+}
+
+// SWGSchemaReportDiff is the difference between two SWGSchemaReports, e.g. the ones generated before and after editing the
+// knowledge base. Only the swagger schemas and properties that have changed are recorded, ordered by their addresses.
+type SWGSchemaReportDiff struct {
+	Summary SWGSchemaReportDiffSummary `json:"summary"`
+	Schemas []SWGSchemaDiff            `json:"schemas"`
+}
+
+// NewSWGSchemaReportDiff diffs the new SWGSchemaReport against the old one.
+func NewSWGSchemaReportDiff(oldReport, newReport SWGSchemaReport) SWGSchemaReportDiff {
+	addrSet := map[SWGSchemaAddr]bool{}
+	for addr := range oldReport {
+		addrSet[addr] = true
+	}
+	for addr := range newReport {
+		addrSet[addr] = true
+	}
+	addrs := make([]SWGSchemaAddr, 0, len(addrSet))
+	for addr := range addrSet {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+
+	diff := SWGSchemaReportDiff{Schemas: []SWGSchemaDiff{}}
+	for _, addr := range addrs {
+		oldSchema, inOld := oldReport[addr]
+		newSchema, inNew := newReport[addr]
+		schemaDiff, changed := diffSWGSchema(addr, oldSchema, inOld, newSchema, inNew)
+		if !changed {
+			continue
+		}
+		diff.Schemas = append(diff.Schemas, schemaDiff)
+
+		switch schemaDiff.Status {
+		case SWGDiffAdded:
+			diff.Summary.AddedSchemas++
+		case SWGDiffRemoved:
+			diff.Summary.RemovedSchemas++
+		default:
+			diff.Summary.ChangedSchemas++
+		}
+		if schemaDiff.Granted {
+			diff.Summary.NewGrants++
+		}
+		if schemaDiff.Ungranted {
+			diff.Summary.RemovedGrants++
+		}
+		for _, propDiff := range schemaDiff.Properties {
+			diff.Summary.AddedLinks += len(propDiff.AddedLinks)
+			diff.Summary.RemovedLinks += len(propDiff.RemovedLinks)
+			if propDiff.NewlyCovered {
+				diff.Summary.NewlyCoveredProperties++
+			}
+			if propDiff.LostCoverage {
+				diff.Summary.LostCoverageProperties++
+			}
+			if propDiff.Granted {
+				diff.Summary.NewGrants++
+			}
+			if propDiff.Ungranted {
+				diff.Summary.RemovedGrants++
+			}
+		}
+	}
+	return diff
+}
+
+// diffSWGSchema diffs a swagger schema, which might only exist in one of the reports. The returned bool indicates whether
+// there is any change.
+func diffSWGSchema(addr SWGSchemaAddr, oldSchema SWGSchemaWithCoverage, inOld bool, newSchema SWGSchemaWithCoverage, inNew bool) (SWGSchemaDiff, bool) {
+	diff := SWGSchemaDiff{
+		Schema: addr,
+		Status: SWGDiffChanged,
+	}
+
+	var oldProps, newProps SWGSchemaProperties
+	var oldGranted, newGranted bool
+	if inOld {
+		diff.OldCoverage = oldSchema.Coverage
+		oldProps = oldSchema.Properties
+		oldGranted = oldSchema.IsGranted
+	} else {
+		diff.Status = SWGDiffAdded
+	}
+	if inNew {
+		diff.NewCoverage = newSchema.Coverage
+		newProps = newSchema.Properties
+		newGranted = newSchema.IsGranted
+	} else {
+		diff.Status = SWGDiffRemoved
+	}
+	diff.CoverageDelta = diff.NewCoverage - diff.OldCoverage
+
+	switch {
+	case newGranted && !oldGranted:
+		diff.Granted = true
+		diff.GrantComment = newSchema.GrantComment
+	case oldGranted && !newGranted:
+		diff.Ungranted = true
+		diff.GrantComment = oldSchema.GrantComment
+	}
+
+	propAddrSet := map[string]bool{}
+	for propAddr := range oldProps {
+		propAddrSet[propAddr] = true
+	}
+	for propAddr := range newProps {
+		propAddrSet[propAddr] = true
+	}
+	propAddrs := make([]string, 0, len(propAddrSet))
+	for propAddr := range propAddrSet {
+		propAddrs = append(propAddrs, propAddr)
+	}
+	sort.Strings(propAddrs)
+
+	for _, propAddr := range propAddrs {
+		if propDiff, changed := diffSWGSchemaProperty(propAddr, oldProps[propAddr], newProps[propAddr]); changed {
+			diff.Properties = append(diff.Properties, propDiff)
+		}
+	}
+
+	changed := diff.Status != SWGDiffChanged || diff.CoverageDelta != 0 || diff.Granted || diff.Ungranted || len(diff.Properties) != 0
+	return diff, changed
+}
+
+// diffSWGSchemaProperty diffs a swagger property, either of the properties can be nil if it doesn't exist in that report.
+// The returned bool indicates whether there is any change.
+func diffSWGSchemaProperty(propAddr string, oldProp, newProp *SWGSchemaProperty) (SWGPropertyDiff, bool) {
+	diff := SWGPropertyDiff{
+		Property: propAddr,
+		Status:   SWGDiffChanged,
+	}
+
+	var oldLinks, newLinks TFLinks
+	var oldGranted, newGranted bool
+	if oldProp != nil {
+		oldLinks = oldProp.TFLinks
+		oldGranted = oldProp.IsGranted
+	} else {
+		diff.Status = SWGDiffAdded
+	}
+	if newProp != nil {
+		newLinks = newProp.TFLinks
+		newGranted = newProp.IsGranted
+	} else {
+		diff.Status = SWGDiffRemoved
+	}
+
+	diff.AddedLinks, diff.RemovedLinks = diffTFLinks(oldLinks, newLinks)
+	diff.NewlyCovered = len(oldLinks) == 0 && len(newLinks) != 0
+	diff.LostCoverage = len(oldLinks) != 0 && len(newLinks) == 0
+
+	switch {
+	case newGranted && !oldGranted:
+		diff.Granted = true
+		diff.GrantComment = newProp.GrantComment
+	case oldGranted && !newGranted:
+		diff.Ungranted = true
+		diff.GrantComment = oldProp.GrantComment
+	}
+
+	changed := diff.Status != SWGDiffChanged || len(diff.AddedLinks) != 0 || len(diff.RemovedLinks) != 0 || diff.Granted || diff.Ungranted
+	return diff, changed
+}
+
+// diffTFLinks returns the terraform property addresses that are only in the new links, and the ones that are only in the old links.
+func diffTFLinks(oldLinks, newLinks TFLinks) (added, removed []string) {
+	oldSet := map[string]bool{}
+	for _, link := range oldLinks {
+		oldSet[link.Prop.String()] = true
+	}
+	newSet := map[string]bool{}
+	for _, link := range newLinks {
+		newSet[link.Prop.String()] = true
+	}
+	for addr := range newSet {
+		if !oldSet[addr] {
+			added = append(added, addr)
+		}
+	}
+	for addr := range oldSet {
+		if !newSet[addr] {
+			removed = append(removed, addr)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// changes describes the changes of the property in a human readable form.
+func (d SWGPropertyDiff) changes() []string {
+	var changes []string
+	if d.Status != SWGDiffChanged {
+		changes = append(changes, string(d.Status))
+	}
+	if d.NewlyCovered {
+		changes = append(changes, "newly covered")
+	}
+	if d.LostCoverage {
+		changes = append(changes, "lost coverage")
+	}
+	for _, link := range d.AddedLinks {
+		changes = append(changes, "+"+link)
+	}
+	for _, link := range d.RemovedLinks {
+		changes = append(changes, "-"+link)
+	}
+	if d.Granted {
+		changes = append(changes, fmt.Sprintf("granted (%s)", d.GrantComment))
+	}
+	if d.Ungranted {
+		changes = append(changes, fmt.Sprintf("ungranted (%s)", d.GrantComment))
+	}
+	return changes
+}
+
+// grantChange describes the grant change of the schema in a human readable form, empty if the grant is not changed.
+func (d SWGSchemaDiff) grantChange() string {
+	switch {
+	case d.Granted:
+		return fmt.Sprintf("granted (%s)", d.GrantComment)
+	case d.Ungranted:
+		return fmt.Sprintf("ungranted (%s)", d.GrantComment)
+	}
+	return ""
+}
+
+func formatCoverageChange(d SWGSchemaDiff) string {
+	return fmt.Sprintf("%.2f%% -> %.2f%% (%+.2f%%)", d.OldCoverage*100, d.NewCoverage*100, d.CoverageDelta*100)
+}
+
+func (s SWGSchemaReportDiffSummary) String() string {
+	return fmt.Sprintf("schemas: %d added, %d removed, %d changed; properties: %d newly covered, %d lost coverage; links: %d added, %d removed; grants: %d added, %d removed",
+		s.AddedSchemas, s.RemovedSchemas, s.ChangedSchemas,
+		s.NewlyCoveredProperties, s.LostCoverageProperties,
+		s.AddedLinks, s.RemovedLinks,
+		s.NewGrants, s.RemovedGrants)
+}
+
+// WriteText writes the SWGSchemaReportDiff in plain text.
+func (diff SWGSchemaReportDiff) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString(diff.Summary.String() + "\n")
+	for _, schemaDiff := range diff.Schemas {
+		fmt.Fprintf(&sb, "\n%s [%s] %s\n", schemaDiff.Schema, schemaDiff.Status, formatCoverageChange(schemaDiff))
+		if change := schemaDiff.grantChange(); change != "" {
+			fmt.Fprintf(&sb, "  schema %s\n", change)
+		}
+		for _, propDiff := range schemaDiff.Properties {
+			fmt.Fprintf(&sb, "  %s: %s\n", propDiff.Property, strings.Join(propDiff.changes(), ", "))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMarkdown writes the SWGSchemaReportDiff in Markdown, where each changed swagger schema has its own section.
+func (diff SWGSchemaReportDiff) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("# Swagger Schema Diff\n\n")
+	sb.WriteString(diff.Summary.String() + "\n")
+	for _, schemaDiff := range diff.Schemas {
+		fmt.Fprintf(&sb, "\n## `%s`\n\n", schemaDiff.Schema)
+		fmt.Fprintf(&sb, "- Status: %s\n", schemaDiff.Status)
+		fmt.Fprintf(&sb, "- Coverage: %s\n", formatCoverageChange(schemaDiff))
+		if change := schemaDiff.grantChange(); change != "" {
+			fmt.Fprintf(&sb, "- Grant: %s\n", markdownEscapeTableCell(change))
+		}
+		if len(schemaDiff.Properties) == 0 {
+			continue
+		}
+		sb.WriteString("\n| Property | Changes |\n|---|---|\n")
+		for _, propDiff := range schemaDiff.Properties {
+			fmt.Fprintf(&sb, "| `%s` | %s |\n", propDiff.Property, markdownEscapeTableCell(strings.Join(propDiff.changes(), "<br>")))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func markdownEscapeTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSWGSchemaReportDiff(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(pwd, "testdata", "swagger_schema_diff")
+	oldReport, err := LoadSWGSchemaReport(filepath.Join(dir, "old.json"))
+	require.NoError(t, err)
+	newReport, err := LoadSWGSchemaReport(filepath.Join(dir, "new.json"))
+	require.NoError(t, err)
+
+	const rel = "Microsoft.Foo/stable/2020-01-01/foo.json"
+	diff := NewSWGSchemaReportDiff(oldReport, newReport)
+	require.Equal(t, SWGSchemaReportDiff{
+		Summary: SWGSchemaReportDiffSummary{
+			AddedSchemas:           1,
+			ChangedSchemas:         2,
+			NewlyCoveredProperties: 2,
+			LostCoverageProperties: 1,
+			AddedLinks:             3,
+			RemovedLinks:           2,
+			NewGrants:              2,
+			RemovedGrants:          1,
+		},
+		Schemas: []SWGSchemaDiff{
+			{
+				Schema:       NewSWGSchemaAddr(rel, "Bar"),
+				Status:       SWGDiffChanged,
+				Granted:      true,
+				GrantComment: "internal",
+			},
+			{
+				Schema:        NewSWGSchemaAddr(rel, "Foo"),
+				Status:        SWGDiffChanged,
+				OldCoverage:   0.5,
+				NewCoverage:   0.75,
+				CoverageDelta: 0.25,
+				Properties: []SWGPropertyDiff{
+					{Property: "etag", Status: SWGDiffChanged, Granted: true, GrantComment: "read only"},
+					{Property: "old", Status: SWGDiffRemoved, RemovedLinks: []string{"azurerm_foo:old"}, LostCoverage: true},
+					{Property: "properties.p1", Status: SWGDiffChanged, AddedLinks: []string{"azurerm_foo:p1_new"}, RemovedLinks: []string{"azurerm_foo:p1"}},
+					{Property: "properties.p2", Status: SWGDiffChanged, AddedLinks: []string{"azurerm_foo:p2"}, NewlyCovered: true, Ungranted: true, GrantComment: "not supported"},
+					{Property: "properties.p3", Status: SWGDiffAdded},
+				},
+			},
+			{
+				Schema:        NewSWGSchemaAddr(rel, "Qux"),
+				Status:        SWGDiffAdded,
+				NewCoverage:   1,
+				CoverageDelta: 1,
+				Properties: []SWGPropertyDiff{
+					{Property: "name", Status: SWGDiffAdded, AddedLinks: []string{"azurerm_qux:name"}, NewlyCovered: true},
+				},
+			},
+		},
+	}, diff)
+
+	var text strings.Builder
+	require.NoError(t, diff.WriteText(&text))
+	require.Contains(t, text.String(), "  properties.p2: newly covered, +azurerm_foo:p2, ungranted (not supported)\n")
+
+	var md strings.Builder
+	require.NoError(t, diff.WriteMarkdown(&md))
+	require.Contains(t, md.String(), "| `properties.p1` | +azurerm_foo:p1_new<br>-azurerm_foo:p1 |\n")
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportListSep separates the items of a list (e.g. the links of a property) in a single exported column.
+const ExportListSep = ";"
+
+// SWGPropertyRow is the flat form of a swagger property, which is exported as a row of the "swagger_properties" table.
+type SWGPropertyRow struct {
+	ResourceProvider string
+	APIVersion       string
+	SwaggerFile      string
+	Schema           string
+	Property         string
+	Type             string
+	ReadOnly         bool
+	Granted          bool
+	GrantComment     string
+	TFLinks          []string
+}
+
+// SWGPropertyColumns are the columns of the "swagger_properties" table, in the order of the fields of the SWGPropertyRow.
+var SWGPropertyColumns = []string{
+	"resource_provider",
+	"api_version",
+	"swagger_file",
+	"schema",
+	"property",
+	"type",
+	"read_only",
+	"granted",
+	"grant_comment",
+	"tf_links",
+}
+
+// Values returns the values of the row, in the order of SWGPropertyColumns.
+func (r SWGPropertyRow) Values() []interface{} {
+	return []interface{}{
+		r.ResourceProvider,
+		r.APIVersion,
+		r.SwaggerFile,
+		r.Schema,
+		r.Property,
+		r.Type,
+		r.ReadOnly,
+		r.Granted,
+		r.GrantComment,
+		strings.Join(r.TFLinks, ExportListSep),
+	}
+}
+
+// NewSWGPropertyRows flattens the properties of the swagger schemas into rows, ordered by the schema and the property.
+// A property is regarded as granted if either itself or its schema is granted, in which case the grant comment is of the one
+// that is granted.
+func NewSWGPropertyRows(schemas map[SWGSchemaAddr]*SWGSchema) []SWGPropertyRow {
+	addrs := make([]SWGSchemaAddr, 0, len(schemas))
+	for addr := range schemas {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+
+	rows := []SWGPropertyRow{}
+	for _, addr := range addrs {
+		schema := schemas[addr]
+		swaggerFile := filepath.ToSlash(addr.SwaggerRelPath())
+
+		propAddrs := make([]string, 0, len(schema.Properties))
+		for propAddr := range schema.Properties {
+			propAddrs = append(propAddrs, propAddr)
+		}
+		sort.Strings(propAddrs)
+
+		for _, propAddr := range propAddrs {
+			prop := schema.Properties[propAddr]
+			row := SWGPropertyRow{
+				ResourceProvider: addr.ResourceProvider(),
+				APIVersion:       path.Base(path.Dir(swaggerFile)),
+				SwaggerFile:      swaggerFile,
+				Schema:           addr.SchemaName(),
+				Property:         propAddr,
+				Type:             prop.Type(),
+				ReadOnly:         prop.ReadOnly(),
+			}
+			switch {
+			case prop.IsGranted:
+				row.Granted, row.GrantComment = true, prop.GrantComment
+			case schema.IsGranted:
+				row.Granted, row.GrantComment = true, schema.GrantComment
+			}
+			for _, link := range prop.TFLinks {
+				row.TFLinks = append(row.TFLinks, link.Prop.String())
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// TFPropertyRow is the flat form of a terraform property, which is exported as a row of the "terraform_properties" table.
+type TFPropertyRow struct {
+	Resource            string
+	Property            string
+	Status              TFPropertyStatus
+	NotApplicableReason string
+	SwaggerSpec         string
+
+	// The linked swagger properties, each is followed by the swagger spec in parenthesis if it overrides the SwaggerSpec.
+	SwaggerLinks []string
+}
+
+// TFPropertyColumns are the columns of the "terraform_properties" table, in the order of the fields of the TFPropertyRow.
+var TFPropertyColumns = []string{
+	"resource",
+	"property",
+	"status",
+	"not_applicable_reason",
+	"swagger_spec",
+	"swagger_links",
+}
+
+// Values returns the values of the row, in the order of TFPropertyColumns.
+func (r TFPropertyRow) Values() []interface{} {
+	return []interface{}{
+		r.Resource,
+		r.Property,
+		string(r.Status),
+		r.NotApplicableReason,
+		r.SwaggerSpec,
+		strings.Join(r.SwaggerLinks, ExportListSep),
+	}
+}
+
+// NewTFPropertyRows flattens the properties of the terraform schemas into rows, ordered by the resource and the property.
+func NewTFPropertyRows(schemas []TFSchema) []TFPropertyRow {
+	schemas = append([]TFSchema(nil), schemas...)
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+
+	rows := []TFPropertyRow{}
+	for _, schema := range schemas {
+		for _, tfProp := range schema.PropertyLinks.sortedKeys() {
+			row := TFPropertyRow{
+				Resource:    schema.Name,
+				Property:    tfProp,
+				Status:      schema.PropertyLinks.Status(tfProp),
+				SwaggerSpec: schema.SwaggerSpec,
+			}
+			switch row.Status {
+			case TFPropertyNotApplicable:
+				row.NotApplicableReason, _ = schema.PropertyLinks.NotApplicableReason(tfProp)
+			case TFPropertyLinked:
+				for _, link := range schema.PropertyLinks[tfProp] {
+					swgLink := link.SchemaProp.String()
+					if link.Spec != nil && *link.Spec != schema.SwaggerSpec {
+						swgLink += fmt.Sprintf(" (%s)", *link.Spec)
+					}
+					row.SwaggerLinks = append(row.SwaggerLinks, swgLink)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes the rows as CSV, with the columns as the header. The booleans are written as "true" or "false".
+func WriteCSV(w io.Writer, columns []string, rows [][]interface{}) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, 0, len(row))
+		for _, v := range row {
+			switch v := v.(type) {
+			case bool:
+				record = append(record, strconv.FormatBool(v))
+			default:
+				record = append(record, fmt.Sprint(v))
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SWGPropertyRowValues returns the values of each SWGPropertyRow.
+func SWGPropertyRowValues(rows []SWGPropertyRow) [][]interface{} {
+	values := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row.Values())
+	}
+	return values
+}
+
+// TFPropertyRowValues returns the values of each TFPropertyRow.
+func TFPropertyRowValues(rows []TFPropertyRow) [][]interface{} {
+	values := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row.Values())
+	}
+	return values
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSWGPropertyRows(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	rel := filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json")
+
+	foo, err := NewSWGSchema(specBasePath, rel, "Foo")
+	require.NoError(t, err)
+	require.NoError(t, foo.ExpandAll(2))
+	foo.Properties["id"].TFLinks = TFLinks{
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo:id")},
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("azurerm_foo_bar:foo_id")},
+	}
+	foo.Properties["etag"].grant(GrantInfo{Comment: "read only"}, "foo.json", "")
+
+	bar, err := NewSWGSchema(specBasePath, rel, "Bar")
+	require.NoError(t, err)
+	require.NoError(t, bar.ExpandAll(2))
+	bar.Grant(GrantInfo{Comment: "internal"}, "foo.json")
+
+	rows := NewSWGPropertyRows(map[SWGSchemaAddr]*SWGSchema{
+		NewSWGSchemaAddr(rel, "Foo"): foo,
+		NewSWGSchemaAddr(rel, "Bar"): bar,
+	})
+	swaggerFile := "Microsoft.Foo/stable/2020-01-01/foo.json"
+	newRow := func(schema, property, typ string) SWGPropertyRow {
+		return SWGPropertyRow{
+			ResourceProvider: "Microsoft.Foo",
+			APIVersion:       "2020-01-01",
+			SwaggerFile:      swaggerFile,
+			Schema:           schema,
+			Property:         property,
+			Type:             typ,
+		}
+	}
+	expect := []SWGPropertyRow{
+		newRow("Bar", "name", "string"),
+		newRow("Foo", "etag", "string"),
+		newRow("Foo", "id", "string"),
+		newRow("Foo", "old", "string"),
+		newRow("Foo", "properties.p1", "string"),
+		newRow("Foo", "properties.p2", "integer"),
+	}
+	expect[0].Granted, expect[0].GrantComment = true, "internal"
+	expect[1].ReadOnly, expect[1].Granted, expect[1].GrantComment = true, true, "read only"
+	expect[2].TFLinks = []string{"azurerm_foo:id", "azurerm_foo_bar:foo_id"}
+	require.Equal(t, expect, rows)
+
+	var sb strings.Builder
+	require.NoError(t, WriteCSV(&sb, SWGPropertyColumns, SWGPropertyRowValues(rows[1:3])))
+	require.Equal(t, `resource_provider,api_version,swagger_file,schema,property,type,read_only,granted,grant_comment,tf_links
+Microsoft.Foo,2020-01-01,Microsoft.Foo/stable/2020-01-01/foo.json,Foo,etag,string,true,true,read only,
+Microsoft.Foo,2020-01-01,Microsoft.Foo/stable/2020-01-01/foo.json,Foo,id,string,false,false,,azurerm_foo:id;azurerm_foo_bar:foo_id
+`, sb.String())
+}
+
+func TestNewTFPropertyRows(t *testing.T) {
+	otherSpec := "other.json"
+	rows := NewTFPropertyRows([]TFSchema{
+		{
+			Name:        "azurerm_foo",
+			SwaggerSpec: "foo.json",
+			PropertyLinks: TFSchemaPropertyLinks{
+				"name":                {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Foo:name")}},
+				"other":               {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("Other:id"), Spec: &otherSpec}},
+				"resource_group_name": {NewNotApplicableLink("part of the resource id")},
+				"tags":                {},
+			},
+		},
+	})
+	require.Equal(t, []TFPropertyRow{
+		{Resource: "azurerm_foo", Property: "name", Status: TFPropertyLinked, SwaggerSpec: "foo.json", SwaggerLinks: []string{"Foo:name"}},
+		{Resource: "azurerm_foo", Property: "other", Status: TFPropertyLinked, SwaggerSpec: "foo.json", SwaggerLinks: []string{"Other:id (other.json)"}},
+		{Resource: "azurerm_foo", Property: "resource_group_name", Status: TFPropertyNotApplicable, NotApplicableReason: "part of the resource id", SwaggerSpec: "foo.json"},
+		{Resource: "azurerm_foo", Property: "tags", Status: TFPropertyUnmapped, SwaggerSpec: "foo.json"},
+	}, rows)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SWGSchemaWithCoverage is the SWGSchema together with its property coverage (<=1), which is the form of the SWGSchema in
+// the SWGSchemaReport.
+type SWGSchemaWithCoverage struct {
+	Coverage float64
+	*SWGSchema
+}
+
+// SWGSchemaReport is the output of the SWGSchemas (e.g. the "swagger_schema.json" generated by cmd/swagger_schema), whose
+// key is the SWGSchemaAddr.
+type SWGSchemaReport map[SWGSchemaAddr]SWGSchemaWithCoverage
+
+// NewSWGSchemaReport builds the SWGSchemaReport from the SWGSchemas, whose coverage is expected to be calculated already.
+func NewSWGSchemaReport(swgschemas *SWGSchemas) SWGSchemaReport {
+	report := SWGSchemaReport{}
+	for schemaAddr, schema := range swgschemas.GetAll() {
+		covered, total := schema.SchemaCoverage()
+		var cov float64
+		if total != 0 {
+			cov = float64(covered) / float64(total)
+		}
+		report[schemaAddr] = SWGSchemaWithCoverage{
+			Coverage:  cov,
+			SWGSchema: schema,
+		}
+	}
+	return report
+}
+
+// LoadSWGSchemaReport loads the SWGSchemaReport from the JSON file.
+func LoadSWGSchemaReport(path string) (SWGSchemaReport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report SWGSchemaReport
+	if err := DecodeFile(FileKindSWGSchemaReport, FileFormatJSON, b, &report); err != nil {
+		return nil, fmt.Errorf("unmarshalling swagger schema report %q: %w", path, newFileDiagnostics(path, b, err).Err())
+	}
+	for schemaAddr, schema := range report {
+		if schema.SWGSchema == nil {
+			return nil, fmt.Errorf("swagger schema report %q: schema %s is empty", path, schemaAddr)
+		}
+	}
+	return report, nil
+}
+
+// SortedAddrs returns the SWGSchemaAddrs of the report in order.
+func (report SWGSchemaReport) SortedAddrs() []SWGSchemaAddr {
+	addrs := make([]SWGSchemaAddr, 0, len(report))
+	for addr := range report {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+	return addrs
+}
+
+const (
+	// KnowledgeBaseTerraformSchemaDir is the directory, relative to the knowledge base directory, that contains the terraform schemas.
+	KnowledgeBaseTerraformSchemaDir = "terraform_schema"
+	// KnowledgeBaseSwaggerGrantDir is the directory, relative to the knowledge base directory, that contains the swagger grants.
+	KnowledgeBaseSwaggerGrantDir = "swagger_grants"
+)
+
+// NewSWGSchemaReportFromKnowledgeBase builds the SWGSchemaReport from the knowledge base directory (e.g. azure_knowledgebase),
+// which contains the terraform schemas and the swagger grants. The inherited grants that no longer apply are returned as well,
+// which are the same as the SWGSchemas.StaleInheritedGrants.
+func NewSWGSchemaReportFromKnowledgeBase(swaggerBasePath, knowledgeBaseDir string, grantOpts SWGGrantOptions) (SWGSchemaReport, Diagnostics, error) {
+	swgschemas, err := NewSWGSchemasFromTerraformSchema(
+		swaggerBasePath,
+		filepath.Join(knowledgeBaseDir, KnowledgeBaseTerraformSchemaDir),
+		filepath.Join(knowledgeBaseDir, KnowledgeBaseSwaggerGrantDir),
+		grantOpts,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewSWGSchemaReport(swgschemas), swgschemas.StaleInheritedGrants, nil
+}
+
+// NewSWGSchemaReportFromPath builds the SWGSchemaReport from either a swagger schema file, or a knowledge base directory, in
+// which case the swaggerBasePath is required, and the stale inherited grants are returned the same as
+// NewSWGSchemaReportFromKnowledgeBase.
+func NewSWGSchemaReportFromPath(path, swaggerBasePath string, grantOpts SWGGrantOptions) (SWGSchemaReport, Diagnostics, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !stat.IsDir() {
+		report, err := LoadSWGSchemaReport(path)
+		return report, nil, err
+	}
+	if swaggerBasePath == "" {
+		return nil, nil, fmt.Errorf("the swagger spec path is required to build the swagger schema report from the knowledge base directory %q", path)
+	}
+	return NewSWGSchemaReportFromKnowledgeBase(swaggerBasePath, path, grantOpts)
+}
+
+// propertyCoverage counts the covered properties and the total properties of the swagger schema, the granted properties are
+// not counted, which is the same as SWGSchema.SchemaCoverage, except that it doesn't rely on the CalcCoverage (e.g. the schema
+// is loaded from the SWGSchemaReport).
+func (s *SWGSchema) propertyCoverage() (covered, total int) {
+	for _, prop := range s.Properties {
+		if prop.IsGranted {
+			continue
+		}
+		total++
+		if len(prop.TFLinks) != 0 {
+			covered++
+		}
+	}
+	return covered, total
+}
+
+// Schemas returns the swagger schemas of the report.
+func (report SWGSchemaReport) Schemas() map[SWGSchemaAddr]*SWGSchema {
+	schemas := make(map[SWGSchemaAddr]*SWGSchema, len(report))
+	for addr, schema := range report {
+		schemas[addr] = schema.SWGSchema
+	}
+	return schemas
+}
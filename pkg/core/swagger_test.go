@@ -1114,7 +1114,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
 								},
 								schema: specFoo.Definitions["def_foo"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1162,8 +1162,8 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
 								},
 								schema: specFoo.Definitions["def_foo"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1210,8 +1210,8 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
 								},
 								schema: specFoo.Definitions["def_foo"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1222,7 +1222,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 							},
 							"p1.prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
 								},
 								schema: specBar.Definitions["def_foo"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1269,8 +1269,8 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
 								},
 								schema: specFoo.Definitions["def_foo"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1281,7 +1281,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 							},
 							"p1.prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
 								},
 								schema: specBar.Definitions["def_bar"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1308,7 +1308,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 							},
 							"p3.prop_primitive": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p3")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p3")},
 								},
 								schema: specBar.Definitions["def_bar"].Properties["prop_primitive"],
 								resolvedRefs: map[string]interface{}{
@@ -1339,7 +1339,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"p1.p1_1": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
 								},
 								schema: specFoo.Definitions["def_b"].Properties["p1"].Properties["p1_1"],
 								resolvedRefs: map[string]interface{}{
@@ -1361,8 +1361,8 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"p1.p1_1": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
 								},
 								schema: specFoo.Definitions["def_b"].Properties["p1"].Properties["p1_1"],
 								resolvedRefs: map[string]interface{}{
@@ -1392,7 +1392,7 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"p1": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
 								},
 								schema: specFoo.Definitions["def_b"].Properties["p1"],
 								resolvedRefs: map[string]interface{}{
@@ -1414,8 +1414,8 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 						Properties: map[string]*SWGSchemaProperty{
 							"p1.p1_1": {
 								TFLinks: []TFLink{
-									{*propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
-									{*propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+									{Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1")},
 								},
 								schema: specFoo.Definitions["def_b"].Properties["p1"].Properties["p1_1"],
 								resolvedRefs: map[string]interface{}{
@@ -1438,10 +1438,10 @@ func TestLinkSWGSchema_AddTFLink(t *testing.T) {
 		if err == nil {
 			for iidx, s := range c.steps {
 				if s.err {
-					require.Error(t, schema.AddTFLink(s.swgPropAddr, s.tfPropAddr), fmt.Sprintf("%d.%d", idx, iidx))
+					require.Error(t, schema.AddTFLink(s.swgPropAddr, TFLink{Prop: s.tfPropAddr}), fmt.Sprintf("%d.%d", idx, iidx))
 					continue
 				}
-				require.NoError(t, schema.AddTFLink(s.swgPropAddr, s.tfPropAddr), fmt.Sprintf("%d.%d", idx, iidx))
+				require.NoError(t, schema.AddTFLink(s.swgPropAddr, TFLink{Prop: s.tfPropAddr}), fmt.Sprintf("%d.%d", idx, iidx))
 				require.Equal(t, s.expect, *schema, fmt.Sprintf("%d.%d", idx, iidx))
 			}
 		}
@@ -1509,7 +1509,7 @@ func TestSWGSchema_Marshal(t *testing.T) {
 			process: func(schema *SWGSchema) {
 				swgPropAddr := propertyaddr.MustParseSwaggerPropertyAddr("def_a:p1.prop_primitive")
 				tfPropAddr := *propertyaddr.ParseTerraformPropertyAddr("res1:p2")
-				require.NoError(t, schema.AddTFLink(swgPropAddr, tfPropAddr))
+				require.NoError(t, schema.AddTFLink(swgPropAddr, TFLink{Prop: tfPropAddr}))
 			},
 			expect: fmt.Sprintf(`{
     "SwaggerRelPath": "foo.json",
@@ -1565,7 +1565,7 @@ func TestSWGSchema_Unmarshal(t *testing.T) {
 				Name:           "def_a",
 				Properties: SWGSchemaProperties{
 					"p1.prop_primitive": &SWGSchemaProperty{TFLinks: []TFLink{
-						{*propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
+						{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2")},
 					}},
 					"p1.p1_1":        &SWGSchemaProperty{TFLinks: []TFLink{}},
 					"prop_primitive": &SWGSchemaProperty{TFLinks: []TFLink{}},
@@ -1581,6 +1581,27 @@ func TestSWGSchema_Unmarshal(t *testing.T) {
 	}
 }
 
+func TestTFLinks_MarshalJSON(t *testing.T) {
+	links := TFLinks{
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1")},
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.id"), Transform: LinkTransformSubResourceID},
+		{Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p3"), Transform: LinkTransformCustom, Note: "some note"},
+	}
+	expect := `[
+    "res1:p1",
+    {"prop": "res1:p2.id", "transform": "subresource_id"},
+    {"prop": "res1:p3", "transform": "custom", "note": "some note"}
+]`
+
+	b, err := json.Marshal(links)
+	require.NoError(t, err)
+	require.JSONEq(t, expect, string(b))
+
+	var actual TFLinks
+	require.NoError(t, json.Unmarshal(b, &actual))
+	require.Equal(t, links, actual)
+}
+
 func TestSWGSchema_CalcCoverage(t *testing.T) {
 	cases := []struct {
 		swgschema   SWGSchema
@@ -1651,18 +1672,24 @@ func TestSWGSchema_CalcCoverage(t *testing.T) {
 func TestSWGSchemas_Grant(t *testing.T) {
 	cases := []struct {
 		swggrant         SWGGrant
-		swgschemas       SWGSchemas
-		expectSwgSchemas SWGSchemas
+		swgschemas       *SWGSchemas
+		expectSwgSchemas *SWGSchemas
 		expectError      bool
 	}{
 		// grant schema
 		{
-			swggrant: map[SWGSchemaAddr]SWGSchemaGrant{
+			swggrant: SWGGrant{Schemas: map[SWGSchemaAddr]SWGSchemaGrant{
 				NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
-					Comment: "granted because of some reason",
+					GrantInfo: GrantInfo{
+						Comment:  "granted because of some reason",
+						Category: GrantCategoryByDesign,
+						Issue:    "https://github.com/foo/bar/issues/1",
+						Owner:    "foo",
+						ReviewBy: "2020-01-01",
+					},
 				},
-			},
-			swgschemas: SWGSchemas{
+			}},
+			swgschemas: &SWGSchemas{
 				m: map[SWGSchemaAddr]*SWGSchema{
 					NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
 						SwaggerRelPath: "swaggerRelPath",
@@ -1670,11 +1697,16 @@ func TestSWGSchemas_Grant(t *testing.T) {
 					},
 				},
 			},
-			expectSwgSchemas: SWGSchemas{
+			expectSwgSchemas: &SWGSchemas{
 				m: map[SWGSchemaAddr]*SWGSchema{
 					NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
 						IsGranted:      true,
 						GrantComment:   "granted because of some reason",
+						GrantCategory:  GrantCategoryByDesign,
+						GrantIssue:     "https://github.com/foo/bar/issues/1",
+						GrantOwner:     "foo",
+						GrantReviewBy:  "2020-01-01",
+						GrantSource:    "swaggerRelPath",
 						SwaggerRelPath: "swaggerRelPath",
 						Name:           "schema1",
 					},
@@ -1683,14 +1715,14 @@ func TestSWGSchemas_Grant(t *testing.T) {
 		},
 		// grant property
 		{
-			swggrant: map[SWGSchemaAddr]SWGSchemaGrant{
+			swggrant: SWGGrant{Schemas: map[SWGSchemaAddr]SWGSchemaGrant{
 				NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
-					Properties: map[string]string{
-						"prop1": "granted because of some reason",
+					Properties: map[string]SWGPropertyGrant{
+						"prop1": {GrantInfo: GrantInfo{Comment: "granted because of some reason", Category: GrantCategoryReadOnlyNoise}},
 					},
 				},
-			},
-			swgschemas: SWGSchemas{
+			}},
+			swgschemas: &SWGSchemas{
 				m: map[SWGSchemaAddr]*SWGSchema{
 					NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
 						SwaggerRelPath: "swaggerRelPath",
@@ -1706,16 +1738,18 @@ func TestSWGSchemas_Grant(t *testing.T) {
 					},
 				},
 			},
-			expectSwgSchemas: SWGSchemas{
+			expectSwgSchemas: &SWGSchemas{
 				m: map[SWGSchemaAddr]*SWGSchema{
 					NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
 						SwaggerRelPath: "swaggerRelPath",
 						Name:           "schema1",
 						Properties: map[string]*SWGSchemaProperty{
 							"prop1": {
-								IsGranted:    true,
-								GrantComment: "granted because of some reason",
-								TFLinks:      []TFLink{},
+								IsGranted:     true,
+								GrantComment:  "granted because of some reason",
+								GrantCategory: GrantCategoryReadOnlyNoise,
+								GrantSource:   "swaggerRelPath",
+								TFLinks:       []TFLink{},
 							},
 							"prop2": {
 								TFLinks: []TFLink{},
@@ -1728,14 +1762,14 @@ func TestSWGSchemas_Grant(t *testing.T) {
 
 		// the property to be granted doesn't exist
 		{
-			swggrant: map[SWGSchemaAddr]SWGSchemaGrant{
+			swggrant: SWGGrant{Schemas: map[SWGSchemaAddr]SWGSchemaGrant{
 				NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
-					Properties: map[string]string{
-						"non_exist_prop1": "granted because of some reason",
+					Properties: map[string]SWGPropertyGrant{
+						"non_exist_prop1": {GrantInfo: GrantInfo{Comment: "granted because of some reason"}},
 					},
 				},
-			},
-			swgschemas: SWGSchemas{
+			}},
+			swgschemas: &SWGSchemas{
 				m: map[SWGSchemaAddr]*SWGSchema{
 					NewSWGSchemaAddr("swaggerRelPath", "schema1"): {
 						SwaggerRelPath: "swaggerRelPath",
@@ -1754,10 +1788,36 @@ func TestSWGSchemas_Grant(t *testing.T) {
 	for idx, c := range cases {
 		swgschemas := c.swgschemas
 		if !c.expectError {
-			require.NoError(t, swgschemas.Grant(c.swggrant), idx)
+			require.NoError(t, swgschemas.Grant("", c.swggrant, SWGGrantOptions{}), idx)
 			require.Equal(t, c.expectSwgSchemas, swgschemas, idx)
 		} else {
-			require.Error(t, swgschemas.Grant(c.swggrant), idx)
+			require.Error(t, swgschemas.Grant("", c.swggrant, SWGGrantOptions{}), idx)
 		}
 	}
 }
+
+func TestSWGSchema_FindPropertyDescription(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger_versions")
+	schema, err := NewSWGSchema(specBasePath, filepath.Join("Microsoft.Foo", "stable", "2020-01-01", "foo.json"), "Foo")
+	require.NoError(t, err)
+
+	cases := []struct {
+		property string
+		expect   string
+	}{
+		{"old", "The old property."},
+		// The properties along the way are expanded on demand.
+		{"properties.p2", "The p2 | property."},
+		{"properties.p1", ""},
+		{"missing", ""},
+	}
+	for _, c := range cases {
+		actual, err := schema.FindPropertyDescription(c.property)
+		require.NoError(t, err, c.property)
+		require.Equal(t, c.expect, actual, c.property)
+	}
+}
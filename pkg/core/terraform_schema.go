@@ -1,6 +1,15 @@
 package core
 
-import "github.com/zclconf/go-cty/cty"
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
 
 type TerraformProviderSchemas struct {
 	FormatVersion string                       `json:"format_version"`
@@ -28,3 +37,107 @@ type TerraformAttribute struct {
 type TerraformNestedBlock struct {
 	TerraformBlock `json:"block"`
 }
+
+// LoadTerraformProvider loads the schema of the specified provider from the provider schema file,
+// which is generated by "$ terraform providers schema -json".
+func LoadTerraformProvider(providerSchemaPath, providerName string) (*TerraformProvider, error) {
+	b, err := ioutil.ReadFile(providerSchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	var providerSchemas TerraformProviderSchemas
+	if err := json.Unmarshal(b, &providerSchemas); err != nil {
+		return nil, fmt.Errorf("unmarshalling provider schema file %s: %v", providerSchemaPath, err)
+	}
+	provider, ok := providerSchemas.Schemas[providerName]
+	if !ok {
+		return nil, fmt.Errorf("provider %s not found in the provider schemas", providerName)
+	}
+	return &provider, nil
+}
+
+// FindBlock finds the block of the resource or data source (whose name is prefixed with "data_") for a TFSchema.
+func (p TerraformProvider) FindBlock(tfSchemaName string) (*TerraformBlock, bool) {
+	schemas := p.ResourceSchemas
+	if strings.HasPrefix(tfSchemaName, "data_") {
+		schemas = p.DataSourceSchemas
+		tfSchemaName = strings.TrimPrefix(tfSchemaName, "data_")
+	}
+	schema, ok := schemas[tfSchemaName]
+	if !ok || schema.Block == nil {
+		return nil, false
+	}
+	return schema.Block, true
+}
+
+// TFPropertyType describes the type of a property in the flattened TFSchema, which is either a primitive type or a collection of primitive type.
+type TFPropertyType struct {
+	// The collection type wrapping the primitive type, which is one of "", "list", "set" and "map".
+	Collection string
+
+	// The primitive type, which is cty.NilType if the type is not specified.
+	Primitive cty.Type
+}
+
+func (t TFPropertyType) String() string {
+	primitive := "any"
+	if t.Primitive != cty.NilType {
+		primitive = t.Primitive.FriendlyName()
+	}
+	if t.Collection == "" {
+		return primitive
+	}
+	return t.Collection + " of " + primitive
+}
+
+// FindPropertyType finds the type of the property specified by the relative address in the flattened TFSchema.
+func (blk *TerraformBlock) FindPropertyType(addrs propertyaddr.TerraformRelativeAddrs) (*TFPropertyType, bool) {
+	if len(addrs) == 0 {
+		return nil, false
+	}
+	if attr, ok := blk.Attributes[addrs[0]]; ok {
+		return findPropertyTypeByType(attr.Type, addrs[1:])
+	}
+	if nestedBlock, ok := blk.BlockTypes[addrs[0]]; ok {
+		return nestedBlock.TerraformBlock.FindPropertyType(addrs[1:])
+	}
+	return nil, false
+}
+
+func findPropertyTypeByType(elementType *cty.Type, addrs propertyaddr.TerraformRelativeAddrs) (*TFPropertyType, bool) {
+	switch {
+	case elementType == nil,
+		elementType.IsPrimitiveType():
+		if len(addrs) != 0 {
+			return nil, false
+		}
+		if elementType == nil {
+			return &TFPropertyType{}, true
+		}
+		return &TFPropertyType{Primitive: *elementType}, true
+	case elementType.IsListType(),
+		elementType.IsSetType(),
+		elementType.IsMapType():
+		var collection string
+		switch {
+		case elementType.IsListType():
+			collection = "list"
+		case elementType.IsSetType():
+			collection = "set"
+		default:
+			collection = "map"
+		}
+		elemType := elementType.ElementType()
+		if elemType.IsPrimitiveType() && len(addrs) == 0 {
+			return &TFPropertyType{Collection: collection, Primitive: elemType}, true
+		}
+		return findPropertyTypeByType(&elemType, addrs)
+	case elementType.IsObjectType():
+		if len(addrs) == 0 || !elementType.HasAttribute(addrs[0]) {
+			return nil, false
+		}
+		attrType := elementType.AttributeType(addrs[0])
+		return findPropertyTypeByType(&attrType, addrs[1:])
+	}
+	return nil, false
+}
@@ -1,8 +1,14 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/zclconf/go-cty/cty"
@@ -11,12 +17,120 @@ import (
 )
 
 type SwaggerLink struct {
-	Spec       *string                          `json:"swagger,omitempty"` // swagger spec relative path that this propertyaddr resides in, this overrides the global swagger scope
-	SchemaProp propertyaddr.SwaggerPropertyAddr `json:"prop"`              // dot-separated swagger schemas propertyaddr, starting from the schemas used as the PUT body parameter
+	Spec       *string                          `json:"swagger,omitempty"`   // swagger spec relative path that this propertyaddr resides in, this overrides the global swagger scope
+	SchemaProp propertyaddr.SwaggerPropertyAddr `json:"prop"`                // dot-separated swagger schemas propertyaddr, starting from the schemas used as the PUT body parameter
+	Transform  LinkTransform                    `json:"transform,omitempty"` // how the terraform property is transformed to the swagger property, empty if it is mapped as is
+	Note       string                           `json:"note,omitempty"`      // free-form note explaining the mapping to the reviewers
+
+	// The reason why the terraform property has no counterpart in swagger (e.g. "resource_group_name" and "timeouts.*"),
+	// which is only set on the not applicable marker, see NewNotApplicableLink.
+	NotApplicable string `json:"not_applicable,omitempty"`
+}
+
+// NewNotApplicableLink returns the marker of a terraform property that has no counterpart in swagger, which is expected to be
+// the only entry in the links of that property.
+func NewNotApplicableLink(reason string) SwaggerLink {
+	return SwaggerLink{NotApplicable: reason}
+}
+
+// IsNotApplicable tells whether the SwaggerLink is a not applicable marker rather than a link.
+func (link SwaggerLink) IsNotApplicable() bool {
+	return link.NotApplicable != ""
+}
+
+func (link SwaggerLink) MarshalJSON() ([]byte, error) {
+	if link.IsNotApplicable() {
+		return json.Marshal(struct {
+			NotApplicable string `json:"not_applicable"`
+		}{link.NotApplicable})
+	}
+	type swaggerLink SwaggerLink
+	return json.Marshal(swaggerLink(link))
+}
+
+// LinkTransform describes how the value of a terraform property is transformed to the linked swagger property.
+type LinkTransform string
+
+const (
+	// The terraform property is the "id" inside a SubResource object (e.g. "ddos_protection_plan.id").
+	LinkTransformSubResourceID LinkTransform = "subresource_id"
+
+	// The terraform boolean is translated to an enum (e.g. "Enabled"/"Disabled").
+	LinkTransformBoolToEnum LinkTransform = "bool_to_enum"
+
+	// The terraform block (with max_items=1) is flattened from an object, or vice versa.
+	LinkTransformFlatten LinkTransform = "flatten"
+
+	// Any other transform, which should be explained in the note.
+	LinkTransformCustom LinkTransform = "custom"
+)
+
+var linkTransforms = []LinkTransform{
+	LinkTransformSubResourceID,
+	LinkTransformBoolToEnum,
+	LinkTransformFlatten,
+	LinkTransformCustom,
+}
+
+// IsValid tells whether the LinkTransform is one of the known transforms (or empty).
+func (t LinkTransform) IsValid() bool {
+	if t == "" {
+		return true
+	}
+	for _, transform := range linkTransforms {
+		if t == transform {
+			return true
+		}
+	}
+	return false
 }
 
 type TFSchemaPropertyLinks map[string][]SwaggerLink
 
+// TFPropertyStatus describes how far a terraform property is authored in the knowledge base.
+type TFPropertyStatus string
+
+const (
+	// The terraform property links to at least one swagger property.
+	TFPropertyLinked TFPropertyStatus = "linked"
+
+	// The terraform property is marked to have no counterpart in swagger.
+	TFPropertyNotApplicable TFPropertyStatus = "not_applicable"
+
+	// The terraform property is neither linked nor marked, which still needs authoring.
+	TFPropertyUnmapped TFPropertyStatus = "unmapped"
+)
+
+// Status returns the TFPropertyStatus of the terraform property.
+func (links TFSchemaPropertyLinks) Status(tfProp string) TFPropertyStatus {
+	if _, ok := links.NotApplicableReason(tfProp); ok {
+		return TFPropertyNotApplicable
+	}
+	if len(links[tfProp]) != 0 {
+		return TFPropertyLinked
+	}
+	return TFPropertyUnmapped
+}
+
+// NotApplicableReason returns the reason if the terraform property is marked as not applicable.
+func (links TFSchemaPropertyLinks) NotApplicableReason(tfProp string) (string, bool) {
+	for _, link := range links[tfProp] {
+		if link.IsNotApplicable() {
+			return link.NotApplicable, true
+		}
+	}
+	return "", false
+}
+
+func (links TFSchemaPropertyLinks) sortedKeys() []string {
+	keys := make([]string, 0, len(links))
+	for k := range links {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 type TFSchema struct {
 	Name          string
 	SwaggerSpec   string `json:"swagger"` // swagger spec relative path path that all the linked swagger property resides in by default
@@ -30,43 +144,169 @@ func NewSchema(name string) *TFSchema {
 	}
 }
 
+// LinkSwagger links each swagger property referred by this TFSchema to the corresponding terraform property.
+// It keeps going on failure, the returned error (if any) is a Diagnostics that records every failed link,
+// each pointing to the offending "prop" entry.
 func (schema TFSchema) LinkSwagger(swgSchemaCache *SWGSchemas, swaggerBasePath string) error {
-	for tfProp, tfToSwaggerLinks := range schema.PropertyLinks {
+	var diags Diagnostics
+	for _, tfProp := range schema.PropertyLinks.sortedKeys() {
 		tfPropAddr := propertyaddr.NewTerraformPropertyAddr(schema.Name, tfProp)
-		for _, link := range tfToSwaggerLinks {
+		for idx, link := range schema.PropertyLinks[tfProp] {
+			if link.IsNotApplicable() {
+				continue
+			}
 			swaggerRelPath := schema.SwaggerSpec
 			if link.Spec != nil {
 				swaggerRelPath = *link.Spec
 			}
 			// link swgschema
-			if err := swgSchemaCache.LinkSWGSchema(swaggerBasePath, swaggerRelPath, link.SchemaProp, *tfPropAddr); err != nil {
-				return fmt.Errorf("linking swgschema: %w", err)
+			tfLink := TFLink{Prop: *tfPropAddr, Transform: link.Transform, Note: link.Note}
+			if err := swgSchemaCache.LinkSWGSchema(swaggerBasePath, swaggerRelPath, link.SchemaProp, tfLink); err != nil {
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "prop"),
+					Err:     fmt.Errorf("linking swgschema: %w", err),
+				})
 			}
 		}
 	}
 
-	return nil
+	return diags.Err()
 }
 
-// Validate validates the swagger property and tf schemas property has the correct form
+// Validate validates the swagger property and tf schemas property has the correct form.
+// The returned error (if any) is a Diagnostics that records every invalid entry.
 func (schema TFSchema) Validate() error {
+	var diags Diagnostics
 	if strings.HasPrefix(schema.SwaggerSpec, "/") {
-		return fmt.Errorf(`swagger spec path should be relative (not starting with "/")`)
+		diags = append(diags, Diagnostic{
+			Pointer: jsonPointer("swagger"),
+			Err:     fmt.Errorf(`swagger spec path should be relative (not starting with "/")`),
+		})
 	}
-	for tfProp, tfToSwaggerLinks := range schema.PropertyLinks {
+	for _, tfProp := range schema.PropertyLinks.sortedKeys() {
 		if addr := propertyaddr.ParseTerraformPropertyAddr(tfProp); addr.ResourceName != "" {
-			return fmt.Errorf("terraform property addr %s should not specify owner", addr)
+			diags = append(diags, Diagnostic{
+				Pointer: jsonPointer("PropertyLinks", tfProp),
+				Err:     fmt.Errorf("terraform property addr %s should not specify owner", addr),
+			})
 		}
-		for _, link := range tfToSwaggerLinks {
+		links := schema.PropertyLinks[tfProp]
+		for idx, link := range links {
+			if link.IsNotApplicable() {
+				if len(links) != 1 {
+					diags = append(diags, Diagnostic{
+						Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx)),
+						Err:     fmt.Errorf("not applicable marker should be the only entry of terraform property %s", tfProp),
+					})
+				}
+				if link.SchemaProp.Schema != "" || len(link.SchemaProp.PropertyAddr) != 0 || link.Spec != nil || link.Transform != "" {
+					diags = append(diags, Diagnostic{
+						Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "not_applicable"),
+						Err:     fmt.Errorf("not applicable marker should not link to any swagger property"),
+					})
+				}
+				continue
+			}
 			if link.SchemaProp.Schema == "" {
-				return fmt.Errorf("swagger property addr %s should specify owner", link.SchemaProp)
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "prop"),
+					Err:     fmt.Errorf("swagger property addr %s should specify owner", link.SchemaProp),
+				})
 			}
 			if link.Spec != nil && strings.HasPrefix(*link.Spec, "/") {
-				return fmt.Errorf(`swagger spec path should be relative (not starting with "/")`)
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "swagger"),
+					Err:     fmt.Errorf(`swagger spec path should be relative (not starting with "/")`),
+				})
+			}
+			if !link.Transform.IsValid() {
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "transform"),
+					Err:     fmt.Errorf("unknown link transform %q (expected one of: %v)", link.Transform, linkTransforms),
+				})
 			}
+			if link.Transform == LinkTransformCustom && link.Note == "" {
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "transform"),
+					Err:     fmt.Errorf("link with %q transform should have a note explaining it", link.Transform),
+				})
+			}
+		}
+	}
+	return diags.Err()
+}
+
+// TFSchemaFile is a TFSchema together with the knowledge base file where it is loaded from.
+type TFSchemaFile struct {
+	Path    string
+	Format  FileFormat
+	Content []byte
+	TFSchema
+}
+
+// Locate sets the file and the position of each Diagnostic of the error, which is reported against this TFSchema.
+func (f TFSchemaFile) Locate(err error) Diagnostics {
+	var diags Diagnostics
+	diags.Append(err)
+	return diags.WithFile(f.Path, f.Content)
+}
+
+// LoadTFSchemaFile loads the TFSchema from a file, which is either in JSON or YAML.
+// The returned error is a Diagnostics if the file fails to be decoded, or doesn't conform to the format of its version.
+func LoadTFSchemaFile(path string) (*TFSchemaFile, error) {
+	format, ok := FileFormatOf(path)
+	if !ok {
+		return nil, fmt.Errorf("unknown format of terraform schema file %s (expected one of: .json, .yaml, .yml)", path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &TFSchemaFile{Path: path, Format: format, Content: b}
+	if err := DecodeFile(FileKindTFSchema, format, b, &f.TFSchema); err != nil {
+		return nil, newFileDiagnostics(path, b, err)
+	}
+	return f, nil
+}
+
+// LoadTFSchemaFiles loads and validates the TFSchema from each file (either in JSON or YAML) resides in the tfSchemaDir.
+// The files that are failed to be loaded or validated are skipped, in which case the returned error is a Diagnostics
+// that records every problem found.
+func LoadTFSchemaFiles(tfSchemaDir string) ([]TFSchemaFile, error) {
+	var (
+		files []TFSchemaFile
+		diags Diagnostics
+	)
+	err := filepath.Walk(tfSchemaDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if _, ok := FileFormatOf(path); !ok {
+			return nil
+		}
+		f, err := LoadTFSchemaFile(path)
+		if err != nil {
+			fileDiags, ok := err.(Diagnostics)
+			if !ok {
+				return err
+			}
+			diags = append(diags, fileDiags...)
+			return nil
+		}
+		if err := f.Validate(); err != nil {
+			diags = append(diags, f.Locate(err)...)
+			return nil
 		}
+		files = append(files, *f)
+		return nil
+	})
+	if err != nil {
+		diags.Append(fmt.Errorf("walking the terraform schema directory %q: %v", tfSchemaDir, err))
 	}
-	return nil
+	return files, diags.Err()
 }
 
 // NewSchemaScaffoldFromTerraformBlock construct the TFSchema for a certain resource from the terraform resource block derived
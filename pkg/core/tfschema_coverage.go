@@ -0,0 +1,87 @@
+package core
+
+import "sort"
+
+// TFSchemaCoverage is the terraform side coverage of a TFSchema, which tells how far its terraform properties are authored
+// in the knowledge base. The swagger side coverage of the schemas linked by a TFSchema is only trustworthy if this is complete.
+type TFSchemaCoverage struct {
+	// The name of the TFSchema, empty for the aggregated coverage.
+	Name string `json:"name,omitempty"`
+
+	Linked        int `json:"linked"`
+	NotApplicable int `json:"not_applicable"`
+	Unmapped      int `json:"unmapped"`
+	Total         int `json:"total"`
+
+	// The percentages of the terraform properties in each status, which range in [0, 100].
+	LinkedPercent        float64 `json:"linked_percent"`
+	NotApplicablePercent float64 `json:"not_applicable_percent"`
+	UnmappedPercent      float64 `json:"unmapped_percent"`
+
+	// The terraform properties that still need authoring, only recorded per TFSchema.
+	UnmappedProperties []string `json:"unmapped_properties,omitempty"`
+}
+
+// NewTFSchemaCoverage calculates the terraform side coverage of the TFSchema.
+func NewTFSchemaCoverage(schema TFSchema) TFSchemaCoverage {
+	cov := TFSchemaCoverage{Name: schema.Name}
+	for _, tfProp := range schema.PropertyLinks.sortedKeys() {
+		switch schema.PropertyLinks.Status(tfProp) {
+		case TFPropertyLinked:
+			cov.Linked++
+		case TFPropertyNotApplicable:
+			cov.NotApplicable++
+		default:
+			cov.Unmapped++
+			cov.UnmappedProperties = append(cov.UnmappedProperties, tfProp)
+		}
+	}
+	cov.Total = len(schema.PropertyLinks)
+	cov.calcPercents()
+	return cov
+}
+
+// Completeness returns the ratio of the terraform properties that are either linked or marked as not applicable, which ranges in [0, 1].
+// A TFSchema without any property is regarded as complete.
+func (cov TFSchemaCoverage) Completeness() float64 {
+	if cov.Total == 0 {
+		return 1
+	}
+	return float64(cov.Linked+cov.NotApplicable) / float64(cov.Total)
+}
+
+func (cov *TFSchemaCoverage) calcPercents() {
+	if cov.Total == 0 {
+		cov.LinkedPercent, cov.NotApplicablePercent, cov.UnmappedPercent = 0, 0, 0
+		return
+	}
+	cov.LinkedPercent = 100 * float64(cov.Linked) / float64(cov.Total)
+	cov.NotApplicablePercent = 100 * float64(cov.NotApplicable) / float64(cov.Total)
+	cov.UnmappedPercent = 100 * float64(cov.Unmapped) / float64(cov.Total)
+}
+
+// TFCoverageReport is the terraform side coverage of each TFSchema, together with the coverage aggregated across the provider.
+type TFCoverageReport struct {
+	Resources []TFSchemaCoverage `json:"resources"`
+	Total     TFSchemaCoverage   `json:"total"`
+}
+
+// NewTFCoverageReport calculates the terraform side coverage of the TFSchemas, the resources in the report are ordered by name.
+func NewTFCoverageReport(schemas []TFSchema) TFCoverageReport {
+	report := TFCoverageReport{
+		Resources: make([]TFSchemaCoverage, 0, len(schemas)),
+	}
+	for _, schema := range schemas {
+		cov := NewTFSchemaCoverage(schema)
+		report.Resources = append(report.Resources, cov)
+		report.Total.Linked += cov.Linked
+		report.Total.NotApplicable += cov.NotApplicable
+		report.Total.Unmapped += cov.Unmapped
+		report.Total.Total += cov.Total
+	}
+	report.Total.calcPercents()
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Name < report.Resources[j].Name
+	})
+	return report
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+func TestNewTFCoverageReport(t *testing.T) {
+	schemas := []TFSchema{
+		{
+			Name:        "res2",
+			SwaggerSpec: "spec1",
+			PropertyLinks: map[string][]SwaggerLink{
+				"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p1")}},
+				"p2": {},
+				"p3": {},
+				"p4": {NewNotApplicableLink("terraform only")},
+			},
+		},
+		{
+			Name:        "res1",
+			SwaggerSpec: "spec1",
+			PropertyLinks: map[string][]SwaggerLink{
+				"p1": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p1")}},
+			},
+		},
+		{
+			Name:          "res3",
+			PropertyLinks: map[string][]SwaggerLink{},
+		},
+	}
+
+	report := NewTFCoverageReport(schemas)
+	expect := TFCoverageReport{
+		Resources: []TFSchemaCoverage{
+			{
+				Name:          "res1",
+				Linked:        1,
+				Total:         1,
+				LinkedPercent: 100,
+			},
+			{
+				Name:                 "res2",
+				Linked:               1,
+				NotApplicable:        1,
+				Unmapped:             2,
+				Total:                4,
+				LinkedPercent:        25,
+				NotApplicablePercent: 25,
+				UnmappedPercent:      50,
+				UnmappedProperties:   []string{"p2", "p3"},
+			},
+			{
+				Name: "res3",
+			},
+		},
+		Total: TFSchemaCoverage{
+			Linked:               2,
+			NotApplicable:        1,
+			Unmapped:             2,
+			Total:                5,
+			LinkedPercent:        40,
+			NotApplicablePercent: 20,
+			UnmappedPercent:      40,
+		},
+	}
+	require.Equal(t, expect, report)
+
+	require.Equal(t, float64(1), report.Resources[0].Completeness())
+	require.Equal(t, 0.5, report.Resources[1].Completeness())
+	require.Equal(t, float64(1), report.Resources[2].Completeness())
+	require.Equal(t, 0.6, report.Total.Completeness())
+}
@@ -152,8 +152,9 @@ func TestUpdateSchemaScaffoldFromTerraformBlock(t *testing.T) {
 		Name:        "res1",
 		SwaggerSpec: "path",
 		PropertyLinks: map[string][]SwaggerLink{
-			"foo":        {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:foo")}},
+			"foo":        {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:foo"), Transform: LinkTransformSubResourceID, Note: "note"}},
 			"deprecated": {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:deprecated")}},
+			"baz":        {NewNotApplicableLink("terraform only")},
 		},
 	}
 
@@ -161,11 +162,11 @@ func TestUpdateSchemaScaffoldFromTerraformBlock(t *testing.T) {
 		Name:        "res1",
 		SwaggerSpec: "path",
 		PropertyLinks: map[string][]SwaggerLink{
-			"foo":                   {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:foo")}},
+			"foo":                   {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:foo"), Transform: LinkTransformSubResourceID, Note: "note"}},
 			"bar.p1":                {},
 			"bar.p2.p2_1":           {},
 			"bar.p3":                {},
-			"baz":                   {},
+			"baz":                   {NewNotApplicableLink("terraform only")},
 			"block_a.block_a_a.bar": {},
 			"block_a.foo":           {},
 		},
@@ -204,6 +205,14 @@ func TestMarshalTFSchema(t *testing.T) {
 					SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema2:p3.p4"),
 				},
 			},
+			"baz.id": {
+				{
+					SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:baz"),
+					Transform:  LinkTransformSubResourceID,
+					Note:       "the id inside the SubResource",
+				},
+			},
+			"resource_group_name": {NewNotApplicableLink("part of the resource id")},
 		},
 	}
 
@@ -228,6 +237,18 @@ func TestMarshalTFSchema(t *testing.T) {
                 "prop": "schema2:p3.p4",
                 "swagger": "yyy"
             }
+        ],
+        "baz.id": [
+            {
+                "prop": "schema1:baz",
+                "transform": "subresource_id",
+                "note": "the id inside the SubResource"
+            }
+        ],
+        "resource_group_name": [
+            {
+                "not_applicable": "part of the resource id"
+            }
         ]
     },
     "swagger": "spec1"
@@ -263,6 +284,11 @@ func TestUnmarshalTFSchema(t *testing.T) {
                 "prop": "schema2:p3.p4",
                 "swagger": "yyy"
             }
+        ],
+        "timeouts.create": [
+            {
+                "not_applicable": "terraform only"
+            }
         ]
     },
     "swagger": "spec1"
@@ -291,6 +317,7 @@ func TestUnmarshalTFSchema(t *testing.T) {
 					SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema2:p3.p4"),
 				},
 			},
+			"timeouts.create": {NewNotApplicableLink("terraform only")},
 		},
 	}
 
@@ -351,6 +378,53 @@ func TestTFSchema_Validate(t *testing.T) {
 			},
 			err: errors.New("swagger property addr p1.p2 should specify owner"),
 		},
+		{
+			schema: TFSchema{
+				Name:        "foo",
+				SwaggerSpec: "spec1",
+				PropertyLinks: map[string][]SwaggerLink{
+					"p1": {
+						{
+							SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p1"),
+							Transform:  LinkTransformFlatten,
+						},
+					},
+					"p2": {
+						{
+							SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p2"),
+							Transform:  "unknown",
+						},
+						{
+							SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p3"),
+							Transform:  LinkTransformCustom,
+						},
+					},
+				},
+			},
+			err: errors.New(`unknown link transform "unknown" (expected one of: [subresource_id bool_to_enum flatten custom])
+link with "custom" transform should have a note explaining it`),
+		},
+		{
+			schema: TFSchema{
+				Name:        "foo",
+				SwaggerSpec: "spec1",
+				PropertyLinks: map[string][]SwaggerLink{
+					"p1": {NewNotApplicableLink("terraform only")},
+					"p2": {
+						NewNotApplicableLink("terraform only"),
+						{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p2")},
+					},
+					"p3": {
+						{
+							SchemaProp:    propertyaddr.MustParseSwaggerPropertyAddr("schema1:p3"),
+							NotApplicable: "terraform only",
+						},
+					},
+				},
+			},
+			err: errors.New(`not applicable marker should be the only entry of terraform property p2
+not applicable marker should not link to any swagger property`),
+		},
 	}
 
 	for idx, c := range cases {
@@ -363,6 +437,34 @@ func TestTFSchema_Validate(t *testing.T) {
 	}
 }
 
+func TestTFSchemaPropertyLinks_Status(t *testing.T) {
+	schema := TFSchema{
+		Name:        "foo",
+		SwaggerSpec: "spec1",
+		PropertyLinks: map[string][]SwaggerLink{
+			"p1":                  {{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr("schema1:p1")}},
+			"p2":                  {},
+			"resource_group_name": {NewNotApplicableLink("part of the resource id")},
+			"timeouts.create":     {NewNotApplicableLink("terraform only")},
+			"block.p3":            {},
+		},
+	}
+
+	require.Equal(t, TFPropertyLinked, schema.PropertyLinks.Status("p1"))
+	require.Equal(t, TFPropertyUnmapped, schema.PropertyLinks.Status("p2"))
+	require.Equal(t, TFPropertyNotApplicable, schema.PropertyLinks.Status("timeouts.create"))
+
+	reason, ok := schema.PropertyLinks.NotApplicableReason("resource_group_name")
+	require.True(t, ok)
+	require.Equal(t, "part of the resource id", reason)
+	_, ok = schema.PropertyLinks.NotApplicableReason("p1")
+	require.False(t, ok)
+
+	cov := NewTFSchemaCoverage(schema)
+	require.Equal(t, 0.6, cov.Completeness())
+	require.Equal(t, []string{"block.p3", "p2"}, cov.UnmappedProperties)
+}
+
 func TestTFSchema_LinkSwagger(t *testing.T) {
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -412,25 +514,25 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
 								},
 							},
 						},
 						"p1": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 							},
 						},
 						"p2": {TFLinks: []TFLink{
 							{
-								*propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
+								Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
 							},
 						}},
 						"p3.prop_primitive": {TFLinks: []TFLink{
 							{
-								*propertyaddr.ParseTerraformPropertyAddr("res1:p4.p4_1"),
+								Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p4.p4_1"),
 							},
 						}},
 					},
@@ -471,14 +573,14 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
 								},
 							},
 						},
 						"p1": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 							},
 						},
@@ -493,7 +595,7 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
 								},
 							},
 						},
@@ -540,24 +642,24 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
 								},
 							},
 						},
 						"p1.prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 							},
 						},
 						"p1.p1_1": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res2:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1"),
 								},
 							},
 						},
@@ -612,24 +714,24 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p1"),
 								},
 							},
 						},
 						"p1.prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 							},
 						},
 						"p1.p1_1": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p2.p2_1"),
 								},
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res2:p1"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res2:p1"),
 								},
 							},
 						},
@@ -644,7 +746,7 @@ func TestTFSchema_LinkSwagger(t *testing.T) {
 						"prop_primitive": {
 							TFLinks: []TFLink{
 								{
-									*propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
+									Prop: *propertyaddr.ParseTerraformPropertyAddr("res1:p3"),
 								},
 							},
 						},
@@ -0,0 +1,349 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	openapispec "github.com/go-openapi/spec"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+// maxRefResolveDepth limits the depth of resolving a chain of references, to avoid looping forever on a cyclic reference.
+const maxRefResolveDepth = 32
+
+// SWGPropertyType describes the type of a swagger property, which is either a scalar or a collection of scalar.
+type SWGPropertyType struct {
+	// The collection type wrapping the scalar, which is one of "", "array" and "map".
+	Collection string
+
+	// The type of the scalar, which is one of "", "string", "integer", "number", "boolean" and "object".
+	Type   string
+	Format string
+	Enum   []interface{}
+
+	// The property names of the scalar, if it is an object.
+	Properties []string
+}
+
+// IsAny tells whether the swagger property has no type specified at all.
+func (t SWGPropertyType) IsAny() bool {
+	return t.Collection == "" && t.Type == "" && len(t.Properties) == 0
+}
+
+func (t SWGPropertyType) String() string {
+	scalar := t.Type
+	if scalar == "" {
+		scalar = "any"
+	}
+	if t.Format != "" {
+		scalar += fmt.Sprintf(" (%s)", t.Format)
+	}
+	if len(t.Enum) != 0 {
+		values := make([]string, 0, len(t.Enum))
+		for _, v := range t.Enum {
+			values = append(values, fmt.Sprintf("%v", v))
+		}
+		scalar += fmt.Sprintf(" [%s]", strings.Join(values, ", "))
+	}
+	if len(t.Properties) != 0 {
+		scalar += fmt.Sprintf(" {%s}", strings.Join(t.Properties, ", "))
+	}
+	if t.Collection == "" {
+		return scalar
+	}
+	return t.Collection + " of " + scalar
+}
+
+// TypeConversion is a known conversion between a terraform property type and a swagger property type, whose types
+// are regarded as compatible even though they are different.
+type TypeConversion struct {
+	Name  string
+	Match func(tfType TFPropertyType, swgType SWGPropertyType) bool
+}
+
+// DefaultTypeConversions is the allow-list of the conversions that are commonly seen in the provider.
+var DefaultTypeConversions = []TypeConversion{
+	{
+		// e.g. a boolean "enabled" in Terraform is sent as "Enabled"/"Disabled" in the API.
+		Name: "bool-enum",
+		Match: func(tfType TFPropertyType, swgType SWGPropertyType) bool {
+			return isCollectionCompatible(tfType, swgType) &&
+				tfType.Primitive == cty.Bool &&
+				(swgType.Type == "" || swgType.Type == "string") &&
+				isBoolEnum(swgType.Enum)
+		},
+	},
+	{
+		// e.g. the "id" of a sub resource in Terraform is sent as a SubResource object in the API.
+		Name: "subresource-id",
+		Match: func(tfType TFPropertyType, swgType SWGPropertyType) bool {
+			if !isCollectionCompatible(tfType, swgType) || tfType.Primitive != cty.String || swgType.Type != "object" {
+				return false
+			}
+			for _, prop := range swgType.Properties {
+				if prop == "id" {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// boolEnumValues are the pairs of the enum values (in lower case) that represent a boolean.
+var boolEnumValues = [][2]string{
+	{"enabled", "disabled"},
+	{"enable", "disable"},
+	{"true", "false"},
+	{"on", "off"},
+}
+
+// isBoolEnum tells whether the enum values are one of the boolean pairs, compared case-insensitively.
+func isBoolEnum(enum []interface{}) bool {
+	if len(enum) != 2 {
+		return false
+	}
+	values := make([]string, 0, len(enum))
+	for _, v := range enum {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		values = append(values, strings.ToLower(s))
+	}
+	for _, pair := range boolEnumValues {
+		if (values[0] == pair[0] && values[1] == pair[1]) || (values[0] == pair[1] && values[1] == pair[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTypeCompatible tells whether the terraform property type is compatible with the swagger property type, either
+// directly or via one of the conversions.
+func IsTypeCompatible(tfType TFPropertyType, swgType SWGPropertyType, conversions []TypeConversion) bool {
+	if swgType.IsAny() || (isCollectionCompatible(tfType, swgType) && isPrimitiveCompatible(tfType.Primitive, swgType.Type)) {
+		return true
+	}
+	for _, conversion := range conversions {
+		if conversion.Match(tfType, swgType) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCollectionCompatible(tfType TFPropertyType, swgType SWGPropertyType) bool {
+	switch tfType.Collection {
+	case "":
+		return swgType.Collection == ""
+	case "list", "set":
+		return swgType.Collection == "array"
+	case "map":
+		return swgType.Collection == "map"
+	}
+	return false
+}
+
+func isPrimitiveCompatible(tfType cty.Type, swgType string) bool {
+	if tfType == cty.NilType || swgType == "" {
+		return true
+	}
+	switch tfType {
+	case cty.String:
+		return swgType == "string"
+	case cty.Number:
+		return swgType == "integer" || swgType == "number"
+	case cty.Bool:
+		return swgType == "boolean"
+	}
+	return false
+}
+
+// TypeMismatchError records a terraform property that is linked to a swagger property of incompatible type.
+type TypeMismatchError struct {
+	TFProp  propertyaddr.TerraformPropertyAddr
+	TFType  TFPropertyType
+	SWGProp propertyaddr.SwaggerPropertyAddr
+	SWGType SWGPropertyType
+}
+
+func (e TypeMismatchError) Error() string {
+	return fmt.Sprintf("terraform property %s (%s) is linked to swagger property %s (%s) of incompatible type", e.TFProp, e.TFType, e.SWGProp, e.SWGType)
+}
+
+// CheckTypeCompatibility checks whether the type of each terraform property of the TFSchema, which is looked up in the
+// block from the provider schema, is compatible with the type of each swagger property it links to.
+// The SWGSchemas is expected to be linked by this TFSchema already, the links that are failed to be linked, or are annotated
+// with a transform, are skipped.
+// The returned error (if any) is a Diagnostics that records every mismatch.
+func (schema TFSchema) CheckTypeCompatibility(swgschemas *SWGSchemas, block *TerraformBlock, conversions []TypeConversion) error {
+	var diags Diagnostics
+	for _, tfProp := range schema.PropertyLinks.sortedKeys() {
+		links := schema.PropertyLinks[tfProp]
+		if schema.PropertyLinks.Status(tfProp) != TFPropertyLinked {
+			continue
+		}
+		tfPropAddr := propertyaddr.NewTerraformPropertyAddr(schema.Name, tfProp)
+		tfType, ok := block.FindPropertyType(tfPropAddr.PropertyAddr)
+		if !ok {
+			diags = append(diags, Diagnostic{
+				Pointer: jsonPointer("PropertyLinks", tfProp),
+				Err:     fmt.Errorf("terraform property %s is not found in the provider schema", tfPropAddr),
+			})
+			continue
+		}
+		for idx, link := range links {
+			// The link annotated with a transform is a deliberate mapping between different types.
+			if link.IsNotApplicable() || link.Transform != "" {
+				continue
+			}
+			swaggerRelPath := schema.SwaggerSpec
+			if link.Spec != nil {
+				swaggerRelPath = *link.Spec
+			}
+			swgschema := swgschemas.Get(NewSWGSchemaAddr(swaggerRelPath, link.SchemaProp.Schema))
+			if swgschema == nil {
+				continue
+			}
+			swgType, err := swgschema.FindPropertyType(link.SchemaProp)
+			if err != nil {
+				continue
+			}
+			if !IsTypeCompatible(*tfType, *swgType, conversions) {
+				diags = append(diags, Diagnostic{
+					Pointer: jsonPointer("PropertyLinks", tfProp, strconv.Itoa(idx), "prop"),
+					Err: TypeMismatchError{
+						TFProp:  *tfPropAddr,
+						TFType:  *tfType,
+						SWGProp: link.SchemaProp,
+						SWGType: *swgType,
+					},
+				})
+			}
+		}
+	}
+	return diags.Err()
+}
+
+// FindPropertyType finds the type of the swagger property. The property is either one of the properties of the SWGSchema,
+// or an object property that has been expanded into its child properties.
+func (s *SWGSchema) FindPropertyType(addr propertyaddr.SwaggerPropertyAddr) (*SWGPropertyType, error) {
+	if prop, ok := s.Properties[addr.PropertyAddr.String()]; ok {
+		return s.newSWGPropertyType(prop.schema, prop.swaggerURL)
+	}
+
+	childNames := map[string]struct{}{}
+	for raddr := range s.Properties {
+		childAddr := propertyaddr.MustNewSwaggerPropertyAddr(s.Name, raddr)
+		if !addr.Contains(childAddr) || len(childAddr.PropertyAddr) == len(addr.PropertyAddr) {
+			continue
+		}
+		childNames[childAddr.PropertyAddr[len(addr.PropertyAddr)].String()] = struct{}{}
+	}
+	if len(childNames) == 0 {
+		return nil, fmt.Errorf("property %s doesn't belong to schemas %s (%s)", addr, s.Name, s.swaggerURL)
+	}
+	t := &SWGPropertyType{Type: "object"}
+	for name := range childNames {
+		t.Properties = append(t.Properties, name)
+	}
+	sort.Strings(t.Properties)
+	return t, nil
+}
+
+func (s *SWGSchema) newSWGPropertyType(schema openapispec.Schema, swaggerURL string) (*SWGPropertyType, error) {
+	schema, swaggerURL, err := resolveSchemaRef(schema, swaggerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case schema.Type.Contains("array") || schema.Items != nil:
+		var itemSchema openapispec.Schema
+		if schema.Items != nil {
+			if schema.Items.Schema != nil {
+				itemSchema = *schema.Items.Schema
+			} else if len(schema.Items.Schemas) != 0 {
+				itemSchema = schema.Items.Schemas[0]
+			}
+		}
+		t, err := s.newSWGScalarType(itemSchema, swaggerURL)
+		if err != nil {
+			return nil, err
+		}
+		t.Collection = "array"
+		return t, nil
+	case len(schema.Properties) == 0 && len(schema.AllOf) == 0 &&
+		schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+		t, err := s.newSWGScalarType(*schema.AdditionalProperties.Schema, swaggerURL)
+		if err != nil {
+			return nil, err
+		}
+		t.Collection = "map"
+		return t, nil
+	}
+	return s.newSWGScalarType(schema, swaggerURL)
+}
+
+func (s *SWGSchema) newSWGScalarType(schema openapispec.Schema, swaggerURL string) (*SWGPropertyType, error) {
+	schema, swaggerURL, err := resolveSchemaRef(schema, swaggerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &SWGPropertyType{
+		Format: schema.Format,
+		Enum:   schema.Enum,
+	}
+	if len(schema.Type) != 0 {
+		t.Type = schema.Type[0]
+	}
+
+	if len(schema.Properties) == 0 && len(schema.AllOf) == 0 {
+		return t, nil
+	}
+
+	// Collect the property names of the object, including those from the "allOf".
+	t.Type = "object"
+	rootAddr := propertyaddr.MustNewSwaggerPropertyAddr(s.Name, "")
+	tmpProp := NewSWGSchemaProperty(schema, nil, nil, swaggerURL)
+	tmpProp.schema.Items = nil
+	props := s.expandSubProperties(rootAddr, tmpProp)
+	allOfProps, err := s.expandAllOfProperties(rootAddr, tmpProp)
+	if err != nil {
+		return nil, err
+	}
+	props.Add(allOfProps)
+	for name := range props {
+		t.Properties = append(t.Properties, name)
+	}
+	sort.Strings(t.Properties)
+	return t, nil
+}
+
+// resolveSchemaRef resolves the schema until it is not a reference, it returns the resolved schema and the URI of the swagger
+// where the resolved schema resides in.
+func resolveSchemaRef(schema openapispec.Schema, swaggerURL string) (openapispec.Schema, string, error) {
+	for depth := 0; schema.Ref.String() != ""; depth++ {
+		if depth == maxRefResolveDepth {
+			return openapispec.Schema{}, "", fmt.Errorf("resolve reference %s: too many levels of reference", schema.Ref.String())
+		}
+		swagger, err := LoadSwagger(swaggerURL)
+		if err != nil {
+			return openapispec.Schema{}, "", err
+		}
+		ref := schema.Ref
+		resolved, err := openapispec.ResolveRefWithBase(swagger, &ref, &openapispec.ExpandOptions{RelativeBase: swaggerURL})
+		if err != nil {
+			return openapispec.Schema{}, "", fmt.Errorf("resolve reference %s: %w", ref.String(), err)
+		}
+		swaggerURL = NormalizeFileRef(&ref, swaggerURL).GetURL().Path
+		schema = *resolved
+	}
+	return schema, swaggerURL, nil
+}
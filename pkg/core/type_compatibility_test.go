@@ -0,0 +1,116 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/magodo/terraform-provider-azurerm-insight/pkg/core/propertyaddr"
+)
+
+func TestTFSchema_CheckTypeCompatibility(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specBasePath := filepath.Join(pwd, "testdata", "swagger")
+
+	blockInput := []byte(`
+{
+  "attributes": {
+    "name": {"type": "string"},
+    "count": {"type": "number"},
+    "enabled": {"type": "bool"},
+    "premium": {"type": "bool"},
+    "mode": {"type": "bool"},
+    "state": {"type": "string"},
+    "tags": {"type": ["map", "string"]},
+    "names": {"type": ["list", "string"]},
+    "subnet_id": {"type": "string"},
+    "subnet_ids": {"type": ["set", "string"]},
+    "flag": {"type": "bool"},
+    "other": {"type": "string"}
+  },
+  "block_types": {
+    "block": {
+      "block": {
+        "attributes": {
+          "id": {"type": "string"}
+        }
+      }
+    }
+  }
+}`)
+	var block TerraformBlock
+	require.NoError(t, json.Unmarshal(blockInput, &block))
+
+	link := func(prop string) []SwaggerLink {
+		return []SwaggerLink{{SchemaProp: propertyaddr.MustParseSwaggerPropertyAddr(prop)}}
+	}
+	tfschema := TFSchema{
+		Name:        "res1",
+		SwaggerSpec: "types.json",
+		PropertyLinks: map[string][]SwaggerLink{
+			"name":       link("Resource:name"),
+			"count":      link("Resource:count"),
+			"enabled":    link("Resource:state"),
+			"premium":    link("Resource:tier"),
+			"mode":       link("Resource:mode"),
+			"state":      link("Resource:count"),
+			"tags":       link("Resource:tags"),
+			"names":      link("Resource:name"),
+			"subnet_id":  link("Resource:subnet"),
+			"subnet_ids": link("Resource:subnets"),
+			"flag":       link("Resource:name"),
+			"other":      link("Resource:untyped"),
+			"block.id":   link("Resource:subnet.id"),
+			"missing":    link("Resource:name"),
+		},
+	}
+
+	swgschemas := NewSGWSchemas()
+	require.NoError(t, tfschema.LinkSwagger(swgschemas, specBasePath))
+
+	cases := []struct {
+		conversions    []TypeConversion
+		expectPointers []string
+	}{
+		{
+			conversions: DefaultTypeConversions,
+			expectPointers: []string{
+				"/PropertyLinks/flag/0/prop",
+				"/PropertyLinks/missing",
+				"/PropertyLinks/names/0/prop",
+				"/PropertyLinks/premium/0/prop",
+				"/PropertyLinks/state/0/prop",
+			},
+		},
+		{
+			conversions: nil,
+			expectPointers: []string{
+				"/PropertyLinks/enabled/0/prop",
+				"/PropertyLinks/flag/0/prop",
+				"/PropertyLinks/missing",
+				"/PropertyLinks/mode/0/prop",
+				"/PropertyLinks/names/0/prop",
+				"/PropertyLinks/premium/0/prop",
+				"/PropertyLinks/state/0/prop",
+				"/PropertyLinks/subnet_id/0/prop",
+				"/PropertyLinks/subnet_ids/0/prop",
+			},
+		},
+	}
+
+	for idx, c := range cases {
+		err := tfschema.CheckTypeCompatibility(swgschemas, &block, c.conversions)
+		require.Error(t, err, idx)
+		actual := []string{}
+		for _, diag := range err.(Diagnostics) {
+			actual = append(actual, diag.Pointer)
+		}
+		require.Equal(t, c.expectPointers, actual, idx)
+	}
+}